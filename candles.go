@@ -0,0 +1,367 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// klinePageSize is the largest page GET /klines returns.
+const klinePageSize = 1000
+
+// GetKlinesRange fetches every candle between params.StartTime and
+// params.EndTime, paging through GET /klines as needed.
+//
+// Params:
+//   - params.Interval (required).
+//   - params.StartTime (required): first candle open time, Unix ms.
+//   - params.EndTime (optional): defaults to now.
+//   - params.Limit (optional): page size, at most 1000 (the default).
+//
+// Returns:
+//   - all candles in the range, oldest first, without duplicates.
+//   - the error of the first failed page; candles fetched before it are
+//     discarded.
+//
+// Behavior:
+//   - Each page starts one millisecond after the previous page's last
+//     CloseTime, so months ("1M") page correctly as well.
+//   - Paging continues until a page is empty or the last candle closes at
+//     or after EndTime. A page shorter than Limit does not end the range,
+//     since the server may cap pages below the requested size.
+//   - Pages are paced by the client's rate limiter like any other call.
+//
+// Example:
+//
+//	from := time.Now().AddDate(0, -1, 0).UnixMilli()
+//	klines, err := client.GetKlinesRange(t.GetKlinesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Interval:         t.KlineInterval5m,
+//	    StartTime:        from,
+//	})
+func (c *Client) GetKlinesRange(params t.GetKlinesParams) ([]*t.Kline, error) {
+	return c.GetKlinesRangeCtx(context.Background(), params)
+}
+
+// GetKlinesRangeCtx is GetKlinesRange with a caller-supplied context; see RequestCtx.
+func (c *Client) GetKlinesRangeCtx(ctx context.Context, params t.GetKlinesParams) ([]*t.Kline, error) {
+	if params.StartTime <= 0 {
+		return nil, &GoTabdealError{Message: "GetKlinesRange requires a StartTime"}
+	}
+
+	if params.EndTime <= 0 {
+		params.EndTime = time.Now().UnixMilli()
+	}
+	if params.Limit <= 0 || params.Limit > klinePageSize {
+		params.Limit = klinePageSize
+	}
+
+	var klines []*t.Kline
+	for params.StartTime <= params.EndTime {
+		page, err := c.GetKlinesCtx(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil || len(*page) == 0 {
+			break
+		}
+
+		for _, kline := range *page {
+			if kline != nil && (len(klines) == 0 || kline.OpenTime > klines[len(klines)-1].OpenTime) {
+				klines = append(klines, kline)
+			}
+		}
+		// A page of only nil entries leaves nothing to continue from.
+		if len(klines) == 0 {
+			break
+		}
+
+		// Pages may be shorter than Limit when the server caps them, so only
+		// an empty page or the end of the range stops paging. A page that
+		// did not move past StartTime would repeat forever.
+		last := klines[len(klines)-1]
+		if last.CloseTime < params.StartTime {
+			break
+		}
+		params.StartTime = last.CloseTime + 1
+	}
+
+	return klines, nil
+}
+
+// GetCandles returns candles for any interval, falling back to building
+// them from trades when GET /klines cannot serve the request.
+//
+// Behavior:
+//   - Native intervals (see t.IsNativeKlineInterval) are fetched with
+//     GetKlinesRange when StartTime is set, otherwise with GetKlines.
+//   - Custom intervals such as "7m", and pairs for which /klines answers
+//     HTTP 400 or 404, are built from GetRecentTrades with BuildKlines.
+//     This only covers the period spanned by the last 1000 trades;
+//     candles outside StartTime/EndTime are dropped and Limit keeps the
+//     most recent ones.
+//   - Built candles include intervals without trades as flat candles at
+//     the previous close; the still-open candle is included last.
+//
+// Example:
+//
+//	candles, err := client.GetCandles(t.GetKlinesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Interval:         "7m",
+//	})
+func (c *Client) GetCandles(params t.GetKlinesParams) ([]*t.Kline, error) {
+	return c.GetCandlesCtx(context.Background(), params)
+}
+
+// GetCandlesCtx is GetCandles with a caller-supplied context; see RequestCtx.
+func (c *Client) GetCandlesCtx(ctx context.Context, params t.GetKlinesParams) ([]*t.Kline, error) {
+	if t.IsNativeKlineInterval(params.Interval) {
+		var klines []*t.Kline
+		var err error
+
+		if params.StartTime > 0 {
+			klines, err = c.GetKlinesRangeCtx(ctx, params)
+		} else {
+			var page *[]*t.Kline
+			if page, err = c.GetKlinesCtx(ctx, params); err == nil && page != nil {
+				klines = *page
+			}
+		}
+
+		var apiErr *APIError
+		if err == nil || !errors.As(err, &apiErr) ||
+			(apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusNotFound) {
+			return klines, err
+		}
+	}
+
+	interval, err := t.ParseKlineInterval(params.Interval)
+	if err != nil {
+		return nil, &GoTabdealError{Message: "cannot build candles", Err: err}
+	}
+
+	trades, err := c.GetRecentTradesCtx(ctx, t.GetRecentTradesParams{BaseSymbolParams: params.BaseSymbolParams, Limit: 1000})
+	if err != nil {
+		return nil, err
+	}
+
+	var built []t.Kline
+	if trades != nil {
+		built = BuildKlines(*trades, interval)
+	}
+
+	klines := make([]*t.Kline, 0, len(built))
+	for i := range built {
+		kline := &built[i]
+		if params.StartTime > 0 && kline.CloseTime < params.StartTime {
+			continue
+		}
+		if params.EndTime > 0 && kline.OpenTime > params.EndTime {
+			continue
+		}
+		klines = append(klines, kline)
+	}
+
+	if params.Limit > 0 && int64(len(klines)) > params.Limit {
+		klines = klines[int64(len(klines))-params.Limit:]
+	}
+
+	return klines, nil
+}
+
+// BuildKlines aggregates trades into candles of the given interval. The
+// trades may be in any order. Candle boundaries are aligned to the Unix
+// epoch, so "4h" candles open at 00:00, 04:00, ... UTC. The last candle
+// may still be open.
+func BuildKlines(trades []*t.Trade, interval time.Duration) []t.Kline {
+	sorted := slices.DeleteFunc(slices.Clone(trades), func(trade *t.Trade) bool { return trade == nil })
+	slices.SortStableFunc(sorted, func(a, b *t.Trade) int {
+		if a.Time != b.Time {
+			return cmp.Compare(a.Time, b.Time)
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	var klines []t.Kline
+	aggregator := NewCandleAggregator(interval, func(kline t.Kline) { klines = append(klines, kline) })
+	for _, trade := range sorted {
+		aggregator.AddTrade(*trade)
+	}
+
+	if current, ok := aggregator.Current(); ok {
+		klines = append(klines, current)
+	}
+
+	return klines
+}
+
+// CandleAggregator builds candles of an arbitrary interval from a trade
+// feed, such as GetRecentTrades results or a trade stream.
+//
+// Trades must arrive in time order; trades older than the open candle are
+// ignored. When a trade opens a new candle, the previous one is closed and
+// passed to the onClose callback, followed by a flat candle for every
+// interval without trades. Flush closes a candle whose interval ended
+// without a following trade.
+//
+// CandleAggregator is safe for concurrent use.
+type CandleAggregator struct {
+	interval time.Duration
+	onClose  func(t.Kline)
+
+	mu      sync.Mutex
+	current *t.Kline
+}
+
+// NewCandleAggregator creates an aggregator for candles of length
+// interval. onClose receives every completed candle in order and may be
+// nil.
+//
+// Example:
+//
+//	agg := tabdeal.NewCandleAggregator(7*time.Minute, func(k types.Kline) {
+//	    fmt.Println(time.UnixMilli(k.OpenTime), k.Open, k.High, k.Low, k.Close, k.Volume)
+//	})
+//	go agg.Follow(ctx, trades) // trades from stream.SubscribeTrades
+func NewCandleAggregator(interval time.Duration, onClose func(t.Kline)) *CandleAggregator {
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	if onClose == nil {
+		onClose = func(t.Kline) {}
+	}
+
+	return &CandleAggregator{interval: interval, onClose: onClose}
+}
+
+// AddTrade adds a REST trade.
+func (a *CandleAggregator) AddTrade(trade t.Trade) {
+	quoteQty := trade.QuoteQty
+	if quoteQty.IsZero() {
+		quoteQty = trade.Price.Mul(trade.Qty)
+	}
+	a.add(trade.Time, trade.Price, trade.Qty, quoteQty, !trade.IsBuyerMaker)
+}
+
+// AddTradeEvent adds a streamed trade.
+func (a *CandleAggregator) AddTradeEvent(event t.TradeEvent) {
+	a.add(event.TradeTime, event.Price, event.Quantity, event.Price.Mul(event.Quantity), !event.IsBuyerMaker)
+}
+
+// Current returns the candle that is still open, if any.
+func (a *CandleAggregator) Current() (t.Kline, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.current == nil {
+		return t.Kline{}, false
+	}
+	return *a.current, true
+}
+
+// Flush closes the open candle if its interval ended before now. The next
+// trade then starts a fresh candle, with flat candles filling any gap.
+func (a *CandleAggregator) Flush(now time.Time) {
+	a.mu.Lock()
+	var closed *t.Kline
+	if a.current != nil && now.UnixMilli() > a.current.CloseTime {
+		closed = a.current
+		flat := flatKline(*closed, closed.CloseTime+1, a.interval)
+		a.current = &flat
+	}
+	a.mu.Unlock()
+
+	if closed != nil {
+		a.onClose(*closed)
+	}
+}
+
+// Follow feeds trades from a stream subscription into the aggregator and
+// flushes candles on time even when no trades arrive. It returns when ctx
+// is done or the subscription ends.
+func (a *CandleAggregator) Follow(ctx context.Context, trades *Subscription[t.TradeEvent]) {
+	ticker := time.NewTicker(min(a.interval, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			a.Flush(now)
+		case event, ok := <-trades.C():
+			if !ok {
+				return
+			}
+			a.AddTradeEvent(event)
+		}
+	}
+}
+
+func (a *CandleAggregator) add(at int64, price, quantity, quoteQuantity t.Decimal, takerBuy bool) {
+	step := a.interval.Milliseconds()
+	open := at - at%step
+
+	var closed []t.Kline
+
+	a.mu.Lock()
+	if a.current != nil && open < a.current.OpenTime {
+		a.mu.Unlock()
+		return
+	}
+
+	if a.current != nil && open > a.current.OpenTime {
+		for a.current.OpenTime < open {
+			closed = append(closed, *a.current)
+			flat := flatKline(*a.current, a.current.CloseTime+1, a.interval)
+			a.current = &flat
+		}
+	}
+
+	if a.current == nil {
+		a.current = &t.Kline{OpenTime: open, CloseTime: open + step - 1}
+	}
+
+	k := a.current
+	if k.TradeCount == 0 {
+		k.Open, k.High, k.Low = price, price, price
+	}
+	if price.GreaterThan(k.High) {
+		k.High = price
+	}
+	if price.LessThan(k.Low) {
+		k.Low = price
+	}
+	k.Close = price
+	k.Volume = k.Volume.Add(quantity)
+	k.QuoteVolume = k.QuoteVolume.Add(quoteQuantity)
+	k.TradeCount++
+	if takerBuy {
+		k.TakerBuyBaseVolume = k.TakerBuyBaseVolume.Add(quantity)
+		k.TakerBuyQuoteVolume = k.TakerBuyQuoteVolume.Add(quoteQuantity)
+	}
+	a.mu.Unlock()
+
+	for _, kline := range closed {
+		a.onClose(kline)
+	}
+}
+
+// flatKline is an empty candle opening at open whose prices all equal the
+// previous close.
+func flatKline(previous t.Kline, open int64, interval time.Duration) t.Kline {
+	return t.Kline{
+		OpenTime:  open,
+		CloseTime: open + interval.Milliseconds() - 1,
+		Open:      previous.Close,
+		High:      previous.Close,
+		Low:       previous.Close,
+		Close:     previous.Close,
+	}
+}
@@ -0,0 +1,115 @@
+package tabdeal
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+func TestGetKlinesRangeNilPage(tt *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want int
+	}{
+		{"empty page", `[]`, 0},
+		{"only nil entries", `[null,null]`, 0},
+		{"nil entries around a candle", `[null,[1000,"1","2","0.5","1.5","10",1999,"15",3,"5","7.5","0"],null]`, 1},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.page))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+			if err != nil {
+				tt.Fatal(err)
+			}
+
+			klines, err := client.GetKlinesRange(t.GetKlinesParams{
+				BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+				Interval:         t.KlineInterval1m,
+				StartTime:        1000,
+				EndTime:          100000,
+				Limit:            2,
+			})
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if len(klines) != tc.want {
+				tt.Errorf("got %d candles, want %d", len(klines), tc.want)
+			}
+		})
+	}
+}
+
+func TestGetKlinesRangeCappedPages(tt *testing.T) {
+	const minute = 60000
+
+	// The server holds ten one-minute candles opening from minute 1 and never
+	// returns more than three per page, whatever limit is asked for.
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+		start, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+
+		var page []string
+		for open := (start + minute - 1) / minute * minute; open <= end && open <= 10*minute && len(page) < 3; open += minute {
+			page = append(page, fmt.Sprintf(`[%d,"1","1","1","1","1",%d,"1",1,"0","0","0"]`, open, open+minute-1))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(page, ",") + "]"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		end      int64
+		want     int
+		requests int32
+	}{
+		// Four pages of 3, 3, 3 and 1 candles, then an empty page.
+		{"until the data ends", 20 * minute, 10, 5},
+		// The third page's only candle closes past EndTime.
+		{"until EndTime", 7 * minute, 7, 3},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			requests.Store(0)
+			klines, err := client.GetKlinesRange(t.GetKlinesParams{
+				BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+				Interval:         t.KlineInterval1m,
+				StartTime:        minute,
+				EndTime:          tc.end,
+				Limit:            500,
+			})
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if len(klines) != tc.want || requests.Load() != tc.requests {
+				tt.Fatalf("got %d candles in %d requests, want %d in %d", len(klines), requests.Load(), tc.want, tc.requests)
+			}
+			for i, kline := range klines {
+				if kline.OpenTime != int64(i+1)*minute {
+					tt.Errorf("candle %d opens at %d", i, kline.OpenTime)
+				}
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package tabdeal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	t "github.com/darhelm/go-tabdeal/types"
@@ -38,6 +42,45 @@ type ClientOptions struct {
 
 	// ApiSecret is the token used for authenticated API requests.
 	ApiSecret string
+
+	// Retry configures automatic retries of transient failures. When nil,
+	// DefaultRetryPolicy() is used. Set MaxAttempts to 1 to disable retries.
+	Retry *RetryPolicy
+
+	// RateLimit enables the client-side rate limiter. When nil, requests
+	// are not paced.
+	RateLimit *RateLimitOptions
+
+	// RecvWindow is sent as the recvWindow parameter of every signed
+	// request, limiting how long after its timestamp the request stays
+	// valid. Zero leaves the parameter out and uses the server default.
+	RecvWindow time.Duration
+
+	// TimeSync configures SyncTime and StartTimeSync. Clock offsets are
+	// only applied once a measurement has been taken.
+	TimeSync *TimeSyncOptions
+
+	// ValidateOrders runs ValidateOrder against the exchangeInfo rules
+	// cached in Client.Symbols before every CreateOrder, so invalid orders
+	// are never placed. When the market has PERCENT_PRICE, or a
+	// MIN_NOTIONAL that applies to MARKET orders, the current average
+	// price is fetched first as the reference price.
+	ValidateOrders bool
+
+	// Quantize, when set, rounds price, stopPrice and quantity of every
+	// CreateOrder to legal tickSize/stepSize multiples before sending.
+	Quantize *QuantizeOptions
+
+	// Symbols configures the client's exchangeInfo registry.
+	Symbols SymbolRegistryOptions
+
+	// DryRun, when set, keeps orders and cancellations from reaching the
+	// exchange. See DryRunOptions.
+	DryRun *DryRunOptions
+
+	// ClientOrderIds, when set, names every CreateOrder placed without a
+	// NewClientOrderId. See TaggedIdGenerator.
+	ClientOrderIds ClientOrderIdGenerator
 }
 
 // Client represents the API client for interacting with the Tabdeal Market API.
@@ -60,6 +103,43 @@ type Client struct {
 	// ApiSecret is the API Secret for authentication.
 	ApiSecret string
 
+	// RetryPolicy controls automatic retries of transient failures.
+	// A nil policy disables retries.
+	RetryPolicy *RetryPolicy
+
+	// RecvWindow is the default recvWindow of signed requests. Zero omits
+	// the parameter. It can be overridden per call with WithRecvWindow.
+	RecvWindow time.Duration
+
+	// ValidateOrders enables client-side validation in CreateOrder.
+	ValidateOrders bool
+
+	// Quantize enables automatic order quantization in CreateOrder.
+	// Nil disables it.
+	Quantize *QuantizeOptions
+
+	// Symbols is the cached exchangeInfo registry used for validation,
+	// quantization and symbol lookups.
+	Symbols *SymbolRegistry
+
+	// DryRun enables dry-run mode for order placement and cancellation.
+	// Nil sends orders normally.
+	DryRun *DryRunOptions
+
+	// ClientOrderIds generates the NewClientOrderId of orders placed
+	// without one. Nil leaves them unnamed.
+	ClientOrderIds ClientOrderIdGenerator
+
+	// limiter paces requests according to endpoint weights. Nil when
+	// ClientOptions.RateLimit is not set.
+	limiter *rateLimiter
+
+	// clock holds the measured offset to the server clock.
+	clock *timeSync
+
+	// emulator is the OrderEmulator GetOrderStatus consults, if any.
+	emulator atomic.Pointer[OrderEmulator]
+
 	// AutoAuth enables automatic authentication if no valid tokens are provided.
 	AutoAuth bool
 
@@ -80,6 +160,15 @@ type Client struct {
 //     ("https://api1.tabdeal.org") if empty.
 //   - ApiKey: API key used for authenticated endpoints.
 //   - ApiSecret: API secret used for request signing.
+//   - Retry: optional retry policy. Defaults to DefaultRetryPolicy().
+//   - RateLimit: optional client-side rate limiter settings.
+//   - RecvWindow: optional recvWindow for signed requests.
+//   - TimeSync: optional settings for server clock synchronization.
+//   - ValidateOrders: validate orders against exchangeInfo before sending.
+//   - Quantize: round order prices and quantities to legal values.
+//   - Symbols: refresh settings of the exchangeInfo registry.
+//   - DryRun: sign and log orders and cancellations without sending them.
+//   - ClientOrderIds: generate client order ids for unnamed orders.
 //
 // Returns:
 //   - A pointer to an initialized Client.
@@ -88,6 +177,8 @@ type Client struct {
 //
 // Behavior:
 //   - If opts.BaseUrl is provided, it overrides the default BaseUrl.
+//   - Version is set to the constant Version, so requests are routed to
+//     /r/api/v1 (reads) and /api/v1 (writes).
 //   - If opts.HttpClient is nil, a new http.Client is constructed using
 //     opts.Timeout.
 //   - ApiKey and ApiSecret are stored on the client for use in authenticated
@@ -112,7 +203,19 @@ type Client struct {
 //	info, _ := client.GetMarketInformation()
 func NewClient(opts ClientOptions) (*Client, error) {
 	client := &Client{
-		BaseUrl: BaseUrl,
+		BaseUrl:        BaseUrl,
+		Version:        Version,
+		RecvWindow:     opts.RecvWindow,
+		ValidateOrders: opts.ValidateOrders,
+		Quantize:       opts.Quantize,
+		DryRun:         opts.DryRun,
+		ClientOrderIds: opts.ClientOrderIds,
+		clock:          &timeSync{},
+	}
+	client.Symbols = NewSymbolRegistry(client, opts.Symbols)
+
+	if opts.TimeSync != nil {
+		client.clock.opts = *opts.TimeSync
 	}
 
 	if opts.BaseUrl != "" {
@@ -127,6 +230,16 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		client.ApiSecret = opts.ApiSecret
 	}
 
+	if opts.Retry != nil {
+		client.RetryPolicy = opts.Retry
+	} else {
+		client.RetryPolicy = DefaultRetryPolicy()
+	}
+
+	if opts.RateLimit != nil {
+		client.limiter = newRateLimiter(*opts.RateLimit)
+	}
+
 	if opts.HttpClient != nil {
 		client.HttpClient = opts.HttpClient
 	} else {
@@ -241,8 +354,13 @@ func (c *Client) createApiURI(method string, endpoint string) string {
 //   - *APIError when Tabdeal returns status != 2xx.
 //
 // Behavior:
-//   - GET: struct → ?a=b&c=d via StructToURLParams.
-//   - POST: struct → JSON in request body.
+//   - body is encoded into ?a=b&c=d with utils.EncodeParams for every
+//     method. Embedded parameter structs are flattened and empty values
+//     are dropped.
+//   - Signed requests append recvWindow (when configured), timestamp and
+//     signature via utils.WrapWithSignature; the signed payload is exactly
+//     the query string that is sent. The timestamp is corrected by the
+//     offset measured with SyncTime.
 //   - If auth=true:
 //   - handleAutoRefresh() is executed when AutoRefresh is enabled.
 //   - assertAuth() ensures ApiKey is set.
@@ -252,7 +370,7 @@ func (c *Client) createApiURI(method string, endpoint string) string {
 //     into APIError (fields: status, code, message, detail).
 //
 // Dependencies:
-//   - StructToURLParams
+//   - utils.EncodeParams / utils.WrapWithSignature
 //   - assertAuth()
 //   - handleAutoRefresh()
 //   - parseErrorResponse()
@@ -272,31 +390,68 @@ func (c *Client) createApiURI(method string, endpoint string) string {
 //	    return err
 //	}
 func (c *Client) Request(method string, url string, auth bool, body interface{}, result interface{}) error {
-	var reqBody []byte
-	var err error
+	return c.RequestCtx(context.Background(), method, url, auth, body, result)
+}
 
-	if body != nil {
-		var urlParams string
-		if auth {
-			bodyUpdate := u.WrapWithSignature(body, c.ApiSecret, time.Now().Unix()*1000)
-			urlParams, err = u.StructToURLParams(bodyUpdate)
-		} else {
-			urlParams, err = u.StructToURLParams(body)
-		}
+// RequestCtx is the context-aware variant of Request. The request is built
+// with http.NewRequestWithContext, so the context governs the whole round
+// trip: dialing, writing the request, waiting for headers and reading the
+// response body.
+//
+// Transient failures are retried according to the client's RetryPolicy.
+// Every attempt is re-signed with a fresh timestamp.
+//
+// Errors:
+//   - When ctx is cancelled or its deadline expires, the returned error
+//     wraps ctx.Err(), so errors.Is(err, context.Canceled) and
+//     errors.Is(err, context.DeadlineExceeded) work as expected.
+//   - When all attempts fail, the error of the last attempt is returned.
+//   - *RateLimitError when the client-side rate limiter refuses the request.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//
+//	var res t.OrderStatusResponse
+//	err := client.RequestCtx(ctx, "GET", url, true, params, &res)
+func (c *Client) RequestCtx(ctx context.Context, method string, url string, auth bool, body interface{}, result interface{}) error {
+	return c.withRetry(ctx, c.RetryPolicy.retryable(method, url, body), func(int) error {
+		return c.doRequest(ctx, method, url, auth, body, result)
+	})
+}
 
-		if err != nil {
-			return &RequestError{
-				GoTabdealError: GoTabdealError{
-					Message: "failed to convert struct to URL params",
-					Err:     err,
-				},
-				Operation: "preparing request parameters",
-			}
-		}
-		url += "?" + urlParams
+// doRequest performs a single attempt of RequestCtx.
+func (c *Client) doRequest(ctx context.Context, method string, url string, auth bool, body interface{}, result interface{}) error {
+	// Wait for the limiter before stamping and signing, so a long wait
+	// cannot push the timestamp out of recvWindow. The weight depends
+	// only on the method, the endpoint and the body.
+	if err := c.acquire(ctx, method, url, auth, body); err != nil {
+		return err
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
+	return c.sendRequest(ctx, method, url, auth, body, result)
+}
+
+// acquire waits for the rate limiter, if any, to admit a request.
+func (c *Client) acquire(ctx context.Context, method string, url string, auth bool, body interface{}) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.acquire(ctx, method, url, auth, body)
+}
+
+// sendRequest is doRequest after the rate limiter has admitted the
+// request: it encodes, signs and sends it and decodes the response.
+func (c *Client) sendRequest(ctx context.Context, method string, url string, auth bool, body interface{}, result interface{}) error {
+	var reqBody []byte
+
+	url, err := c.encodeRequest(ctx, url, auth, body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return &RequestError{
 			GoTabdealError: GoTabdealError{
@@ -310,13 +465,6 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 	req.Header.Set("Content-Type", "application/json")
 
 	if auth {
-		if err := assertAuth(c); err != nil {
-			return &GoTabdealError{
-				Message: "authentication validation failed",
-				Err:     err,
-			}
-		}
-
 		req.Header.Set("X-MBX-APIKEY", c.ApiKey)
 	}
 
@@ -334,8 +482,15 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 		_ = Body.Close()
 	}(resp.Body)
 
+	if c.limiter != nil {
+		c.limiter.observe(auth, resp)
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
 		return &RequestError{
 			GoTabdealError: GoTabdealError{
 				Message: "failed to read response body",
@@ -346,7 +501,9 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return parseErrorResponse(resp.StatusCode, respBody)
+		apiErr := parseErrorResponse(resp.StatusCode, respBody)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return apiErr
 	}
 
 	if result != nil {
@@ -364,6 +521,48 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 	return nil
 }
 
+// encodeRequest appends the encoded body to url as a query string. Signed
+// requests additionally get recvWindow, timestamp and signature.
+func (c *Client) encodeRequest(ctx context.Context, url string, auth bool, body interface{}) (string, error) {
+	var params u.Params
+	var err error
+
+	if auth {
+		if err := assertAuth(c); err != nil {
+			return "", &GoTabdealError{
+				Message: "authentication validation failed",
+				Err:     err,
+			}
+		}
+
+		params, err = u.EncodeParams(body)
+		if err == nil {
+			if window := c.recvWindow(ctx); window > 0 && params.Get("recvWindow") == "" {
+				params = params.Add("recvWindow", strconv.FormatInt(window.Milliseconds(), 10))
+			}
+			params, err = u.WrapWithSignature(params, c.ApiSecret, c.timestamp())
+		}
+	} else {
+		params, err = u.EncodeParams(body)
+	}
+
+	if err != nil {
+		return "", &RequestError{
+			GoTabdealError: GoTabdealError{
+				Message: "failed to convert struct to URL params",
+				Err:     err,
+			},
+			Operation: "preparing request parameters",
+		}
+	}
+
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	return url, nil
+}
+
 // ApiRequest is a convenience wrapper that builds a Tabdeal API URL using
 // createApiURI() and delegates the actual HTTP call to Request().
 //
@@ -389,21 +588,51 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 //	var stats t.Tickers
 //	err := client.ApiRequest("GET", "/market/stats", "", false, false, params, &stats)
 func (c *Client) ApiRequest(method, endpoint string, auth bool, body interface{}, result interface{}) error {
+	return c.ApiRequestCtx(context.Background(), method, endpoint, auth, body, result)
+}
+
+// ApiRequestCtx is the context-aware variant of ApiRequest. It builds the
+// endpoint URL with createApiURI() and delegates to RequestCtx().
+//
+// Example:
+//
+//	var book t.OrderBook
+//	err := client.ApiRequestCtx(ctx, "GET", "/depth", false, params, &book)
+func (c *Client) ApiRequestCtx(ctx context.Context, method, endpoint string, auth bool, body interface{}, result interface{}) error {
 	url := c.createApiURI(method, endpoint)
-	return c.Request(method, url, auth, body, result)
+	return c.RequestCtx(ctx, method, url, auth, body, result)
 }
 
-func (c *Client) ping() (bool, error) {
-	err := c.ApiRequest("GET", "/ping", false, nil, nil)
+func (c *Client) ping(ctx context.Context) (bool, error) {
+	err := c.ApiRequestCtx(ctx, "GET", "/ping", false, nil, nil)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+// GetServerTime returns Tabdeal's current server time.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/time
+//
+// Returns:
+//   - *t.ServerTime holding the server clock in milliseconds since epoch.
+//   - error on network or API failure.
+//
+// Example:
+//
+//	st, _ := client.GetServerTime()
+//	fmt.Println(st.ServerTime)
 func (c *Client) GetServerTime() (*t.ServerTime, error) {
+	return c.GetServerTimeCtx(context.Background())
+}
+
+// GetServerTimeCtx is GetServerTime with a caller-supplied context; see RequestCtx.
+func (c *Client) GetServerTimeCtx(ctx context.Context) (*t.ServerTime, error) {
 	var serverTime *t.ServerTime
-	err := c.ApiRequest("GET", "/time", false, nil, &serverTime)
+	err := c.ApiRequestCtx(ctx, "GET", "/time", false, nil, &serverTime)
 	if err != nil {
 		return nil, err
 	}
@@ -432,8 +661,13 @@ func (c *Client) GetServerTime() (*t.ServerTime, error) {
 //	if err != nil { panic(err) }
 //	fmt.Println(info[0].Symbol)
 func (c *Client) GetMarketInformation() (*[]*t.MarketInformation, error) {
+	return c.GetMarketInformationCtx(context.Background())
+}
+
+// GetMarketInformationCtx is GetMarketInformation with a caller-supplied context; see RequestCtx.
+func (c *Client) GetMarketInformationCtx(ctx context.Context) (*[]*t.MarketInformation, error) {
 	var marketInfo *[]*t.MarketInformation
-	err := c.ApiRequest("GET", "/exchangeInfo", false, nil, &marketInfo)
+	err := c.ApiRequestCtx(ctx, "GET", "/exchangeInfo", false, nil, &marketInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -450,20 +684,27 @@ func (c *Client) GetMarketInformation() (*[]*t.MarketInformation, error) {
 //   - params.Symbol: market symbol (BTCUSDT, BTCIRT, etc.)
 //
 // Returns:
-//   - *t.OrderBook including raw bids and asks.
+//   - *t.OrderBook with bids and asks as t.PriceLevel values.
 //   - error on failure.
 //
 // Behavior:
 //   - No authentication required.
-//   - Depth is returned in aggregated form: [][]string.
+//   - Depth is returned in aggregated form, best levels first.
 //
 // Example:
 //
 //	book, _ := client.GetOrderBook(t.GetOrderBookParams{Symbol: "BTCUSDT"})
-//	fmt.Println(book.Bids[0])
+//	if bid, ok := book.BestBid(); ok {
+//	    fmt.Println(bid.Price, bid.Quantity)
+//	}
 func (c *Client) GetOrderBook(params t.GetOrderBookParams) (*t.OrderBook, error) {
+	return c.GetOrderBookCtx(context.Background(), params)
+}
+
+// GetOrderBookCtx is GetOrderBook with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOrderBookCtx(ctx context.Context, params t.GetOrderBookParams) (*t.OrderBook, error) {
 	var orderBook *t.OrderBook
-	err := c.ApiRequest("GET", "/depth", false, params, &orderBook)
+	err := c.ApiRequestCtx(ctx, "GET", "/depth", false, params, &orderBook)
 	if err != nil {
 		return nil, err
 	}
@@ -492,14 +733,337 @@ func (c *Client) GetOrderBook(params t.GetOrderBookParams) (*t.OrderBook, error)
 //	trades, _ := client.GetRecentTrades(t.GetRecentTradesParams{Symbol: "BTCUSDT"})
 //	fmt.Println(trades[0].Price)
 func (c *Client) GetRecentTrades(params t.GetRecentTradesParams) (*[]*t.Trade, error) {
+	return c.GetRecentTradesCtx(context.Background(), params)
+}
+
+// GetRecentTradesCtx is GetRecentTrades with a caller-supplied context; see RequestCtx.
+func (c *Client) GetRecentTradesCtx(ctx context.Context, params t.GetRecentTradesParams) (*[]*t.Trade, error) {
 	var trades *[]*t.Trade
-	err := c.ApiRequest("GET", "/trades", false, params, &trades)
+	err := c.ApiRequestCtx(ctx, "GET", "/trades", false, params, &trades)
 	if err != nil {
 		return nil, err
 	}
 	return trades, nil
 }
 
+// GetHistoricalTrades retrieves older public trades of a market, starting
+// from a trade id.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/historicalTrades?symbol=SYMBOL&limit=...&fromId=...
+//
+// Params:
+//   - Symbol (required)
+//   - FromId (optional): first trade id; the latest trades when zero.
+//   - Limit (optional): at most 1000.
+//
+// Returns:
+//   - []*t.Trade sorted oldest → newest.
+//   - error on API or network failure.
+//
+// Behavior:
+//   - No authentication required.
+//   - Use BackfillTrades to fetch every trade between two timestamps.
+//
+// Example:
+//
+//	trades, _ := client.GetHistoricalTrades(t.GetHistoricalTradesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    FromId:           1200000,
+//	    Limit:            1000,
+//	})
+func (c *Client) GetHistoricalTrades(params t.GetHistoricalTradesParams) (*[]*t.Trade, error) {
+	return c.GetHistoricalTradesCtx(context.Background(), params)
+}
+
+// GetHistoricalTradesCtx is GetHistoricalTrades with a caller-supplied context; see RequestCtx.
+func (c *Client) GetHistoricalTradesCtx(ctx context.Context, params t.GetHistoricalTradesParams) (*[]*t.Trade, error) {
+	var trades *[]*t.Trade
+	err := c.ApiRequestCtx(ctx, "GET", "/historicalTrades", false, params, &trades)
+	if err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// GetAggTrades retrieves compressed public trades of a market by aggregate
+// id or time range.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/aggTrades?symbol=SYMBOL&fromId=...&startTime=...&endTime=...&limit=...
+//
+// Params:
+//   - Symbol (required)
+//   - FromId (optional): first aggregate trade id.
+//   - StartTime, EndTime (optional): Unix ms, at most one hour apart.
+//   - Limit (optional): at most 1000.
+//
+// Returns:
+//   - []*t.AggTrade sorted oldest → newest.
+//   - error on API or network failure.
+//
+// Behavior:
+//   - No authentication required.
+//   - Each AggTrade covers the individual trades FirstTradeId through
+//     LastTradeId, which GetHistoricalTrades can fetch.
+//
+// Example:
+//
+//	aggs, _ := client.GetAggTrades(t.GetAggTradesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    StartTime:        time.Now().Add(-time.Hour).UnixMilli(),
+//	    EndTime:          time.Now().UnixMilli(),
+//	})
+func (c *Client) GetAggTrades(params t.GetAggTradesParams) (*[]*t.AggTrade, error) {
+	return c.GetAggTradesCtx(context.Background(), params)
+}
+
+// GetAggTradesCtx is GetAggTrades with a caller-supplied context; see RequestCtx.
+func (c *Client) GetAggTradesCtx(ctx context.Context, params t.GetAggTradesParams) (*[]*t.AggTrade, error) {
+	var trades *[]*t.AggTrade
+	err := c.ApiRequestCtx(ctx, "GET", "/aggTrades", false, params, &trades)
+	if err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// GetKlines retrieves OHLCV candles for a market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/klines?symbol=SYMBOL&interval=1m&startTime=...&endTime=...&limit=...
+//
+// Params:
+//   - Symbol (required)
+//   - Interval (required): one of the t.KlineInterval constants.
+//   - StartTime, EndTime (optional): Unix milliseconds.
+//   - Limit (optional): at most 1000 candles per call.
+//
+// Returns:
+//   - []*t.Kline sorted oldest → newest.
+//   - error on API or network failure.
+//
+// Behavior:
+//   - No authentication required.
+//   - Use GetKlinesRange for histories longer than one page, and
+//     GetCandles for intervals or pairs the endpoint does not serve.
+//
+// Example:
+//
+//	klines, _ := client.GetKlines(t.GetKlinesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Interval:         t.KlineInterval1h,
+//	    Limit:            24,
+//	})
+//	fmt.Println((*klines)[0].Close)
+func (c *Client) GetKlines(params t.GetKlinesParams) (*[]*t.Kline, error) {
+	return c.GetKlinesCtx(context.Background(), params)
+}
+
+// GetKlinesCtx is GetKlines with a caller-supplied context; see RequestCtx.
+func (c *Client) GetKlinesCtx(ctx context.Context, params t.GetKlinesParams) (*[]*t.Kline, error) {
+	var klines *[]*t.Kline
+	err := c.ApiRequestCtx(ctx, "GET", "/klines", false, params, &klines)
+	if err != nil {
+		return nil, err
+	}
+	return klines, nil
+}
+
+// Get24hrTicker retrieves rolling 24-hour statistics for one market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/ticker/24hr?symbol=SYMBOL
+//
+// Params:
+//   - Symbol (required)
+//
+// Returns:
+//   - *t.Ticker24hr with price change, last price, best bid/ask, high,
+//     low and volumes.
+//   - error on API or network failure, or when no symbol is given.
+//
+// Behavior:
+//   - No authentication required.
+//   - Use GetAll24hrTickers for every market in one call.
+//
+// Example:
+//
+//	ticker, _ := client.Get24hrTicker(t.GetTickerParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	})
+//	fmt.Println(ticker.LastPrice, ticker.PriceChangePercent)
+func (c *Client) Get24hrTicker(params t.GetTickerParams) (*t.Ticker24hr, error) {
+	return c.Get24hrTickerCtx(context.Background(), params)
+}
+
+// Get24hrTickerCtx is Get24hrTicker with a caller-supplied context; see RequestCtx.
+func (c *Client) Get24hrTickerCtx(ctx context.Context, params t.GetTickerParams) (*t.Ticker24hr, error) {
+	if err := requireSymbol(params.BaseSymbolParams, "GetAll24hrTickers"); err != nil {
+		return nil, err
+	}
+
+	var ticker *t.Ticker24hr
+	err := c.ApiRequestCtx(ctx, "GET", "/ticker/24hr", false, params, &ticker)
+	if err != nil {
+		return nil, err
+	}
+	return ticker, nil
+}
+
+// GetAll24hrTickers retrieves rolling 24-hour statistics for every market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/ticker/24hr
+//
+// Returns:
+//   - []*t.Ticker24hr, one entry per market.
+//   - error on API or network failure.
+//
+// Behavior:
+//   - No authentication required.
+//   - Much heavier than Get24hrTicker (weight 80 instead of 2).
+func (c *Client) GetAll24hrTickers() (*[]*t.Ticker24hr, error) {
+	return c.GetAll24hrTickersCtx(context.Background())
+}
+
+// GetAll24hrTickersCtx is GetAll24hrTickers with a caller-supplied context; see RequestCtx.
+func (c *Client) GetAll24hrTickersCtx(ctx context.Context) (*[]*t.Ticker24hr, error) {
+	var tickers *[]*t.Ticker24hr
+	err := c.ApiRequestCtx(ctx, "GET", "/ticker/24hr", false, nil, &tickers)
+	if err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// GetAveragePrice retrieves the current average price of a market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/avgPrice?symbol=SYMBOL
+//
+// Params:
+//   - Symbol (required)
+//
+// Returns:
+//   - *t.AveragePrice with the averaging window in minutes and the price.
+//   - error on API or network failure, or when no symbol is given.
+//
+// Behavior:
+//   - No authentication required.
+//   - The price is the reference of the PERCENT_PRICE filter, so it can
+//     be passed to ValidateOrder.
+//
+// Example:
+//
+//	avg, _ := client.GetAveragePrice(t.GetTickerParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	})
+//	violations := tabdeal.ValidateOrder(params, market, avg.Price)
+func (c *Client) GetAveragePrice(params t.GetTickerParams) (*t.AveragePrice, error) {
+	return c.GetAveragePriceCtx(context.Background(), params)
+}
+
+// GetAveragePriceCtx is GetAveragePrice with a caller-supplied context; see RequestCtx.
+func (c *Client) GetAveragePriceCtx(ctx context.Context, params t.GetTickerParams) (*t.AveragePrice, error) {
+	if err := requireSymbol(params.BaseSymbolParams, ""); err != nil {
+		return nil, err
+	}
+
+	var price *t.AveragePrice
+	err := c.ApiRequestCtx(ctx, "GET", "/avgPrice", false, params, &price)
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// GetBookTicker retrieves the best bid and ask of one market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/ticker/bookTicker?symbol=SYMBOL
+//
+// Params:
+//   - Symbol (required)
+//
+// Returns:
+//   - *t.BookTicker with best bid/ask prices and quantities.
+//   - error on API or network failure, or when no symbol is given.
+//
+// Behavior:
+//   - No authentication required.
+//   - Far cheaper than GetOrderBook when only the top of book is needed.
+//   - Use GetAllBookTickers for every market in one call.
+//
+// Example:
+//
+//	top, _ := client.GetBookTicker(t.GetTickerParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	})
+//	fmt.Println(top.BidPrice, top.AskPrice)
+func (c *Client) GetBookTicker(params t.GetTickerParams) (*t.BookTicker, error) {
+	return c.GetBookTickerCtx(context.Background(), params)
+}
+
+// GetBookTickerCtx is GetBookTicker with a caller-supplied context; see RequestCtx.
+func (c *Client) GetBookTickerCtx(ctx context.Context, params t.GetTickerParams) (*t.BookTicker, error) {
+	if err := requireSymbol(params.BaseSymbolParams, "GetAllBookTickers"); err != nil {
+		return nil, err
+	}
+
+	var ticker *t.BookTicker
+	err := c.ApiRequestCtx(ctx, "GET", "/ticker/bookTicker", false, params, &ticker)
+	if err != nil {
+		return nil, err
+	}
+	return ticker, nil
+}
+
+// GetAllBookTickers retrieves the best bid and ask of every market.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/ticker/bookTicker
+//
+// Returns:
+//   - []*t.BookTicker, one entry per market.
+//   - error on API or network failure.
+func (c *Client) GetAllBookTickers() (*[]*t.BookTicker, error) {
+	return c.GetAllBookTickersCtx(context.Background())
+}
+
+// GetAllBookTickersCtx is GetAllBookTickers with a caller-supplied context; see RequestCtx.
+func (c *Client) GetAllBookTickersCtx(ctx context.Context) (*[]*t.BookTicker, error) {
+	var tickers *[]*t.BookTicker
+	err := c.ApiRequestCtx(ctx, "GET", "/ticker/bookTicker", false, nil, &tickers)
+	if err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// requireSymbol rejects single-market calls without a symbol, which the
+// server would answer with the list for every market instead. all names
+// the method to use for that list, if there is one.
+func requireSymbol(symbol t.BaseSymbolParams, all string) error {
+	if symbol.Symbol != "" || symbol.TabdealSymbol != "" {
+		return nil
+	}
+
+	message := "symbol is required"
+	if all != "" {
+		message += "; use " + all + " for every market"
+	}
+	return &GoTabdealError{Message: message}
+}
+
 // GetWallets retrieves funding wallet balances for the authenticated user.
 //
 // Endpoint:
@@ -521,14 +1085,63 @@ func (c *Client) GetRecentTrades(params t.GetRecentTradesParams) (*[]*t.Trade, e
 //	balances, _ := client.GetWallets(t.GetWalletParams{Asset: "USDT"})
 //	fmt.Println(balances[0].Free)
 func (c *Client) GetWallets(params t.GetWalletParams) (*[]*t.Wallet, error) {
+	return c.GetWalletsCtx(context.Background(), params)
+}
+
+// GetWalletsCtx is GetWallets with a caller-supplied context; see RequestCtx.
+func (c *Client) GetWalletsCtx(ctx context.Context, params t.GetWalletParams) (*[]*t.Wallet, error) {
 	var wallets *[]*t.Wallet
-	err := c.ApiRequest("GET", "/get-funding-asset", true, params, &wallets)
+	err := c.ApiRequestCtx(ctx, "GET", "/get-funding-asset", true, params, &wallets)
 	if err != nil {
 		return nil, err
 	}
 	return wallets, nil
 }
 
+// GetAccount retrieves the spot account of the authenticated user:
+// every balance, the commission rates and the account permissions.
+//
+// Endpoint:
+//
+//	GET /api/v1/account?omitZeroBalances=...
+//
+// Params:
+//   - OmitZeroBalances (optional): leave out assets with no balance.
+//
+// Authentication:
+//   - Required. Uses X-MBX-APIKEY header.
+//
+// Returns:
+//   - *t.Account with balances (free and locked), maker/taker commission
+//     and the canTrade, canWithdraw and canDeposit permissions.
+//   - error if authentication is missing or API responds with error.
+//
+// Behavior:
+//   - Unlike GetWallets, a single call returns every asset together with
+//     the account's trading permissions.
+//   - Account.Free, Account.Locked and Account.Balance look up one asset.
+//
+// Example:
+//
+//	account, _ := client.GetAccount(t.GetAccountParams{OmitZeroBalances: true})
+//	if !account.CanTrade {
+//	    return errors.New("trading is disabled for this account")
+//	}
+//	fmt.Println(account.Free("USDT"), account.TakerRate())
+func (c *Client) GetAccount(params t.GetAccountParams) (*t.Account, error) {
+	return c.GetAccountCtx(context.Background(), params)
+}
+
+// GetAccountCtx is GetAccount with a caller-supplied context; see RequestCtx.
+func (c *Client) GetAccountCtx(ctx context.Context, params t.GetAccountParams) (*t.Account, error) {
+	var account *t.Account
+	err := c.ApiRequestCtx(ctx, "GET", "/account", true, params, &account)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
 // CreateOrder submits a new spot order to Tabdeal.
 //
 // Endpoint:
@@ -536,8 +1149,9 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*[]*t.Wallet, error) {
 //	POST /api/v1/order
 //
 // Params (t.CreateOrderParams):
-//   - Side ("BUY"/"SELL")
-//   - Type ("LIMIT", "MARKET")
+//   - Side (t.OrderSideBuy / t.OrderSideSell)
+//   - Type (t.OrderTypeLimit, t.OrderTypeMarket, ...)
+//   - TimeInForce (t.TimeInForceGTC / IOC / FOK, for limit orders)
 //   - Quantity (required)
 //   - Price (for limit orders)
 //   - StopPrice (for stop orders)
@@ -554,20 +1168,179 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*[]*t.Wallet, error) {
 //
 //	resp, _ := client.CreateOrder(t.CreateOrderParams{
 //	    Symbol: "BTCUSDT",
-//	    Side: "BUY",
-//	    Type: "LIMIT",
-//	    Quantity: 0.01,
-//	    Price: 950000000,
+//	    Side: t.OrderSideBuy,
+//	    Type: t.OrderTypeLimit,
+//	    Quantity: t.MustParseDecimal("0.01"),
+//	    Price: t.MustParseDecimal("950000000"),
 //	})
 func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.CreateOrderResponse, error) {
+	return c.CreateOrderCtx(context.Background(), params)
+}
+
+// CreateOrderCtx is CreateOrder with a caller-supplied context; see RequestCtx.
+//
+// When NewClientOrderId is set, transient failures are retried according to
+// the client's RetryPolicy. Before each retry the order is looked up with
+// GetOrderStatus by OrigClientOrderId; if it already exists on the exchange
+// it is returned (without fills) instead of being placed a second time. If
+// the lookup fails, the order is not sent again and an
+// *OrderUncertainError is returned.
+//
+// When Quantize is set, price, stopPrice and quantity are first rounded to
+// legal values with QuantizeOrder and the changes are reported through
+// QuantizeOptions.OnQuantize.
+//
+// When ValidateOrders is enabled, the order is then checked with
+// ValidateOrder, against the average price where the market's filters
+// need one, and an *OrderValidationError is returned without sending the
+// order if it breaks a market rule.
+//
+// In dry-run mode (see DryRunOptions) the order is validated and signed
+// but not sent, and a synthetic response is returned.
+//
+// When the client has a ClientOrderIdGenerator and NewClientOrderId is
+// empty, an id is generated first, tagged with the strategy set by
+// WithStrategy. The order is then retried and reconciled like any named
+// order.
+func (c *Client) CreateOrderCtx(ctx context.Context, params t.CreateOrderParams) (*t.CreateOrderResponse, error) {
+	if params.NewClientOrderId == "" {
+		id, err := c.nextClientOrderId(ctx)
+		if err != nil {
+			return nil, err
+		}
+		params.NewClientOrderId = id
+	}
+
+	params, err := c.prepareOrder(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.DryRun != nil {
+		return c.dryRunCreateOrder(ctx, params)
+	}
+
 	var createOrderResponse *t.CreateOrderResponse
-	err := c.ApiRequest("POST", "/order", true, params, &createOrderResponse)
+	url := c.createApiURI("POST", "/order")
+	err = c.withRetry(ctx, c.RetryPolicy.retryable("POST", url, params), func(attempt int) error {
+		if attempt > 1 && params.NewClientOrderId != "" {
+			existing, err := c.reconcileOrder(ctx, params)
+			if err != nil {
+				return &OrderUncertainError{
+					GoTabdealError: GoTabdealError{
+						Message: "order " + params.NewClientOrderId + " may have been placed and could not be looked up",
+						Err:     err,
+					},
+					ClientOrderId: params.NewClientOrderId,
+				}
+			}
+			if existing != nil {
+				createOrderResponse = existing
+				return nil
+			}
+		}
+		return c.doRequest(ctx, "POST", url, true, params, &createOrderResponse)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return createOrderResponse, nil
 }
 
+// prepareOrder applies the client's Quantize and ValidateOrders settings
+// to an order before it is sent.
+func (c *Client) prepareOrder(ctx context.Context, params t.CreateOrderParams) (t.CreateOrderParams, error) {
+	if c.Quantize != nil {
+		quantized, err := c.quantizeOrder(ctx, params)
+		if err != nil {
+			return params, err
+		}
+		params = quantized
+	}
+
+	if c.ValidateOrders {
+		if err := c.validateOrder(ctx, params); err != nil {
+			return params, err
+		}
+	}
+
+	return params, nil
+}
+
+// TestOrder sends an order to Tabdeal's validation-only endpoint. The
+// exchange checks the signature, parameters and market rules exactly as
+// for CreateOrder, but the order never reaches the matching engine.
+//
+// Endpoint:
+//
+//	POST /api/v1/order/test
+//
+// Params:
+//   - Same as CreateOrder.
+//
+// Authentication:
+//   - Required. Signs parameters using API secret.
+//
+// Returns:
+//   - nil when the exchange would accept the order.
+//   - *APIError describing the rejection otherwise.
+//
+// Behavior:
+//   - Quantize and ValidateOrders are applied as in CreateOrder.
+//   - TestOrder is sent even in dry-run mode, since it changes nothing.
+//
+// Example:
+//
+//	err := client.TestOrder(t.CreateOrderParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Side:             t.OrderSideBuy,
+//	    Type:             t.OrderTypeLimit,
+//	    TimeInForce:      t.TimeInForceGTC,
+//	    Quantity:         t.MustParseDecimal("0.01"),
+//	    Price:            t.MustParseDecimal("3350000000"),
+//	})
+func (c *Client) TestOrder(params t.CreateOrderParams) error {
+	return c.TestOrderCtx(context.Background(), params)
+}
+
+// TestOrderCtx is TestOrder with a caller-supplied context; see RequestCtx.
+func (c *Client) TestOrderCtx(ctx context.Context, params t.CreateOrderParams) error {
+	params, err := c.prepareOrder(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return c.ApiRequestCtx(ctx, "POST", "/order/test", true, params, nil)
+}
+
+// reconcileOrder checks whether an order placed by an earlier, failed
+// CreateOrder attempt reached the exchange. It returns the order when it
+// exists, nil when Tabdeal reports it unknown (CodeOrderDoesNotExist), and
+// an error for any other failure, such as a rejected signature or stale
+// timestamp, since the outcome is then still uncertain.
+func (c *Client) reconcileOrder(ctx context.Context, params t.CreateOrderParams) (*t.CreateOrderResponse, error) {
+	var status *t.OrderStatusResponse
+	lookup := t.GetOrderStatusParams{
+		BaseSymbolParams:  params.BaseSymbolParams,
+		OrigClientOrderId: params.NewClientOrderId,
+	}
+
+	err := c.doRequest(ctx, "GET", c.createApiURI("GET", "/order"), true, lookup, &status)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == CodeOrderDoesNotExist {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if status == nil {
+		return nil, nil
+	}
+
+	return &t.CreateOrderResponse{BaseOrderResponse: status.BaseOrderResponse}, nil
+}
+
 // CancelOrder cancels a single active order.
 //
 // Endpoint:
@@ -592,8 +1365,19 @@ func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.CreateOrderResponse
 //	    OrderId: 1234567,
 //	})
 func (c *Client) CancelOrder(params t.CancelOrderParams) (*t.CancelOrderResponse, error) {
+	return c.CancelOrderCtx(context.Background(), params)
+}
+
+// CancelOrderCtx is CancelOrder with a caller-supplied context; see RequestCtx.
+//
+// In dry-run mode the request is signed but not sent; see DryRunOptions.
+func (c *Client) CancelOrderCtx(ctx context.Context, params t.CancelOrderParams) (*t.CancelOrderResponse, error) {
+	if c.DryRun != nil {
+		return c.dryRunCancelOrder(ctx, params)
+	}
+
 	var cancelOrderStatus *t.CancelOrderResponse
-	err := c.ApiRequest("DELETE", "/order", true, params, &cancelOrderStatus)
+	err := c.ApiRequestCtx(ctx, "DELETE", "/order", true, params, &cancelOrderStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -622,14 +1406,219 @@ func (c *Client) CancelOrder(params t.CancelOrderParams) (*t.CancelOrderResponse
 //	    Symbol: "BTCUSDT",
 //	})
 func (c *Client) CancelOrderBulk(params t.CancelOrderBulkParams) (*[]*t.CancelOrderResponse, error) {
+	return c.CancelOrderBulkCtx(context.Background(), params)
+}
+
+// CancelOrderBulkCtx is CancelOrderBulk with a caller-supplied context; see RequestCtx.
+//
+// In dry-run mode the request is signed but not sent; see DryRunOptions.
+func (c *Client) CancelOrderBulkCtx(ctx context.Context, params t.CancelOrderBulkParams) (*[]*t.CancelOrderResponse, error) {
+	if c.DryRun != nil {
+		return c.dryRunCancelOrderBulk(ctx, params)
+	}
+
 	var cancelOrderBulkStatus *[]*t.CancelOrderResponse
-	err := c.ApiRequest("DELETE", "/openOrders", true, params, &cancelOrderBulkStatus)
+	err := c.ApiRequestCtx(ctx, "DELETE", "/openOrders", true, params, &cancelOrderBulkStatus)
 	if err != nil {
 		return nil, err
 	}
 	return cancelOrderBulkStatus, nil
 }
 
+// CreateOCOOrder places a one-cancels-the-other order list: a limit
+// (take-profit) order and a stop-loss order for the same quantity. When
+// either executes, the exchange cancels the other.
+//
+// Endpoint:
+//
+//	POST /api/v1/order/oco
+//
+// Params (t.CreateOCOOrderParams):
+//   - Symbol, Side, Quantity (required)
+//   - Price (required): the LIMIT_MAKER leg.
+//   - StopPrice (required): the stop trigger.
+//   - StopLimitPrice and StopLimitTimeInForce (optional): make the stop
+//     leg STOP_LOSS_LIMIT instead of STOP_LOSS.
+//   - ListClientOrderId, LimitClientOrderId, StopClientOrderId (optional)
+//
+// Authentication:
+//   - Required. Signs parameters using API secret.
+//
+// Returns:
+//   - *t.OrderListResponse with the list and a report per leg; LimitLeg
+//     and StopLeg pick them out.
+//   - error on failure.
+//
+// Behavior:
+//   - Quantize rounds both legs (see QuantizeOCOOrder); ValidateOrders
+//     checks them with ValidateOCOOrder, including the market's
+//     OcoAllowed flag.
+//   - Not retried, since a repeated placement could open a second list.
+//   - In dry-run mode the order is signed but not sent; see DryRunOptions.
+//
+// Example:
+//
+//	// Exit 0.01 BTC at +5% or -3%.
+//	list, _ := client.CreateOCOOrder(t.CreateOCOOrderParams{
+//	    BaseSymbolParams:     t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Side:                 t.OrderSideSell,
+//	    Quantity:             t.MustParseDecimal("0.01"),
+//	    Price:                t.MustParseDecimal("3517500000"),
+//	    StopPrice:            t.MustParseDecimal("3249500000"),
+//	    StopLimitPrice:       t.MustParseDecimal("3240000000"),
+//	    StopLimitTimeInForce: t.TimeInForceGTC,
+//	})
+//	fmt.Println(list.OrderListId, list.ListOrderStatus)
+func (c *Client) CreateOCOOrder(params t.CreateOCOOrderParams) (*t.OrderListResponse, error) {
+	return c.CreateOCOOrderCtx(context.Background(), params)
+}
+
+// CreateOCOOrderCtx is CreateOCOOrder with a caller-supplied context; see RequestCtx.
+func (c *Client) CreateOCOOrderCtx(ctx context.Context, params t.CreateOCOOrderParams) (*t.OrderListResponse, error) {
+	if c.Quantize != nil {
+		quantized, err := c.quantizeOCOOrder(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		params = quantized
+	}
+
+	if c.ValidateOrders {
+		if err := c.validateOCOOrder(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.DryRun != nil {
+		return c.dryRunCreateOCOOrder(ctx, params)
+	}
+
+	var orderList *t.OrderListResponse
+	err := c.ApiRequestCtx(ctx, "POST", "/order/oco", true, params, &orderList)
+	if err != nil {
+		return nil, err
+	}
+	return orderList, nil
+}
+
+// CancelOrderList cancels every open order of an order list.
+//
+// Endpoint:
+//
+//	DELETE /api/v1/orderList?symbol=...&orderListId=...
+//
+// Params:
+//   - Symbol (required)
+//   - OrderListId OR ListClientOrderId
+//
+// Authentication:
+//   - Required. Signed request.
+//
+// Returns:
+//   - *t.OrderListResponse with the list and the final report per leg.
+//   - error on API or network failure.
+//
+// Behavior:
+//   - Cancelling any single leg with CancelOrder cancels the whole list
+//     as well.
+//   - In dry-run mode the request is signed but not sent; see
+//     DryRunOptions.
+//
+// Example:
+//
+//	client.CancelOrderList(t.CancelOrderListParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    OrderListId:      list.OrderListId,
+//	})
+func (c *Client) CancelOrderList(params t.CancelOrderListParams) (*t.OrderListResponse, error) {
+	return c.CancelOrderListCtx(context.Background(), params)
+}
+
+// CancelOrderListCtx is CancelOrderList with a caller-supplied context; see RequestCtx.
+func (c *Client) CancelOrderListCtx(ctx context.Context, params t.CancelOrderListParams) (*t.OrderListResponse, error) {
+	if c.DryRun != nil {
+		return c.dryRunCancelOrderList(ctx, params)
+	}
+
+	var orderList *t.OrderListResponse
+	err := c.ApiRequestCtx(ctx, "DELETE", "/orderList", true, params, &orderList)
+	if err != nil {
+		return nil, err
+	}
+	return orderList, nil
+}
+
+// GetOrderList retrieves an order list by id.
+//
+// Endpoint:
+//
+//	GET /api/v1/orderList?orderListId=...
+//
+// Params:
+//   - OrderListId OR OrigClientOrderId (the listClientOrderId)
+//
+// Authentication:
+//   - Required. Signed request.
+//
+// Returns:
+//   - *t.OrderList with the list status and the ids of its orders; query
+//     a leg with GetOrderStatus for its details.
+//   - error on API or network failure.
+//
+// Example:
+//
+//	list, _ := client.GetOrderList(t.GetOrderListParams{OrderListId: 4242})
+//	if list.IsDone() {
+//	    fmt.Println("exit completed")
+//	}
+func (c *Client) GetOrderList(params t.GetOrderListParams) (*t.OrderList, error) {
+	return c.GetOrderListCtx(context.Background(), params)
+}
+
+// GetOrderListCtx is GetOrderList with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOrderListCtx(ctx context.Context, params t.GetOrderListParams) (*t.OrderList, error) {
+	var orderList *t.OrderList
+	err := c.ApiRequestCtx(ctx, "GET", "/orderList", true, params, &orderList)
+	if err != nil {
+		return nil, err
+	}
+	return orderList, nil
+}
+
+// GetOpenOrderLists retrieves every order list of the account that is
+// still executing.
+//
+// Endpoint:
+//
+//	GET /api/v1/openOrderList
+//
+// Authentication:
+//   - Required. Signed request.
+//
+// Returns:
+//   - []*t.OrderList, one per open list.
+//   - error on API or network failure.
+//
+// Example:
+//
+//	lists, _ := client.GetOpenOrderLists()
+//	for _, list := range *lists {
+//	    fmt.Println(list.Symbol, list.OrderListId, list.Orders)
+//	}
+func (c *Client) GetOpenOrderLists() (*[]*t.OrderList, error) {
+	return c.GetOpenOrderListsCtx(context.Background())
+}
+
+// GetOpenOrderListsCtx is GetOpenOrderLists with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOpenOrderListsCtx(ctx context.Context) (*[]*t.OrderList, error) {
+	var orderLists *[]*t.OrderList
+	err := c.ApiRequestCtx(ctx, "GET", "/openOrderList", true, nil, &orderLists)
+	if err != nil {
+		return nil, err
+	}
+	return orderLists, nil
+}
+
 // GetOrdersHistory retrieves historical orders for the authenticated user.
 //
 // Endpoint:
@@ -655,8 +1644,13 @@ func (c *Client) CancelOrderBulk(params t.CancelOrderBulkParams) (*[]*t.CancelOr
 //	    Limit: 50,
 //	})
 func (c *Client) GetOrdersHistory(params t.GetUserOrdersHistoryParams) (*[]*t.BaseOrderResponse, error) {
+	return c.GetOrdersHistoryCtx(context.Background(), params)
+}
+
+// GetOrdersHistoryCtx is GetOrdersHistory with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOrdersHistoryCtx(ctx context.Context, params t.GetUserOrdersHistoryParams) (*[]*t.BaseOrderResponse, error) {
 	var orders *[]*t.BaseOrderResponse
-	err := c.ApiRequest("GET", "/allOrders", true, params, &orders)
+	err := c.ApiRequestCtx(ctx, "GET", "/allOrders", true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -682,8 +1676,13 @@ func (c *Client) GetOrdersHistory(params t.GetUserOrdersHistoryParams) (*[]*t.Ba
 //
 //	open, _ := client.GetOpenOrders(t.GetOpenOrdersParams{Symbol: "BTCUSDT"})
 func (c *Client) GetOpenOrders(params t.GetOpenOrdersParams) (*[]*t.BaseOrderResponse, error) {
+	return c.GetOpenOrdersCtx(context.Background(), params)
+}
+
+// GetOpenOrdersCtx is GetOpenOrders with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOpenOrdersCtx(ctx context.Context, params t.GetOpenOrdersParams) (*[]*t.BaseOrderResponse, error) {
 	var orders *[]*t.BaseOrderResponse
-	err := c.ApiRequest("GET", "/openOrders", true, params, &orders)
+	err := c.ApiRequestCtx(ctx, "GET", "/openOrders", true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -705,12 +1704,29 @@ func (c *Client) GetOpenOrders(params t.GetOpenOrdersParams) (*[]*t.BaseOrderRes
 // Returns:
 //   - *t.OrderStatusResponse
 //
+// Behavior:
+//   - When an OrderEmulator is registered, emulated orders are reported
+//     too, by their negative OrderId or their ClientOrderId, as
+//     STOP_LOSS or STOP_LOSS_LIMIT orders (see EmulatedOrder.OrderStatus).
+//     Once an emulated order has triggered, the real order is returned.
+//
 // Example:
 //
 //	st, _ := client.GetOrderStatus(t.GetOrderStatusParams{OrderId: 1234})
 func (c *Client) GetOrderStatus(params t.GetOrderStatusParams) (*t.OrderStatusResponse, error) {
+	return c.GetOrderStatusCtx(context.Background(), params)
+}
+
+// GetOrderStatusCtx is GetOrderStatus with a caller-supplied context; see RequestCtx.
+func (c *Client) GetOrderStatusCtx(ctx context.Context, params t.GetOrderStatusParams) (*t.OrderStatusResponse, error) {
+	if emulator := c.emulator.Load(); emulator != nil {
+		if status, ok := emulator.status(&params); ok {
+			return status, nil
+		}
+	}
+
 	var orders *t.OrderStatusResponse
-	err := c.ApiRequest("GET", "/order", true, params, &orders)
+	err := c.ApiRequestCtx(ctx, "GET", "/order", true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -725,7 +1741,7 @@ func (c *Client) GetOrderStatus(params t.GetOrderStatusParams) (*t.OrderStatusRe
 //
 // Params:
 //   - Symbol
-//   - FromId (pagination, optional)
+//   - FromId (pagination, optional): first trade id; nil for the latest trades.
 //
 // Authentication:
 //   - Required.
@@ -740,8 +1756,13 @@ func (c *Client) GetOrderStatus(params t.GetOrderStatusParams) (*t.OrderStatusRe
 //	    Symbol: "BTCUSDT",
 //	})
 func (c *Client) GetUserTrades(params t.GetUserTradesParams) (*[]*t.UserTradeResponse, error) {
+	return c.GetUserTradesCtx(context.Background(), params)
+}
+
+// GetUserTradesCtx is GetUserTrades with a caller-supplied context; see RequestCtx.
+func (c *Client) GetUserTradesCtx(ctx context.Context, params t.GetUserTradesParams) (*[]*t.UserTradeResponse, error) {
 	var trades *[]*t.UserTradeResponse
-	err := c.ApiRequest("GET", "/myTrades", true, params, &trades)
+	err := c.ApiRequestCtx(ctx, "GET", "/myTrades", true, params, &trades)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+package tabdeal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRequestSignsAfterRateLimitWait(tt *testing.T) {
+	stamps := make(chan int64, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stamp, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+		stamps <- stamp
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	var weighed []string
+	client, err := NewClient(ClientOptions{
+		ApiKey:    "key",
+		ApiSecret: "secret",
+		BaseUrl:   srv.URL,
+		RateLimit: &RateLimitOptions{
+			SignedWeight: 1,
+			Interval:     200 * time.Millisecond,
+			Weight: func(method, path string, body interface{}) int {
+				weighed = append(weighed, method+" "+path)
+				return 1
+			},
+		},
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	url := client.createApiURI(http.MethodGet, "/account")
+	for range 2 {
+		if err := client.Request(http.MethodGet, url, true, nil, nil); err != nil {
+			tt.Fatal(err)
+		}
+	}
+
+	first, second := <-stamps, <-stamps
+	if wait := time.Duration(second-first) * time.Millisecond; wait < 150*time.Millisecond {
+		tt.Errorf("second request was stamped %v after the first, want it stamped after the limiter wait", wait)
+	}
+	if len(weighed) != 2 || weighed[0] != "GET /r/api/v1/account" {
+		tt.Errorf("weighed %q, want the unsigned endpoint path", weighed)
+	}
+}
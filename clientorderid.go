@@ -0,0 +1,250 @@
+package tabdeal
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxClientOrderIdLength is the longest client order id Tabdeal accepts.
+const MaxClientOrderIdLength = 36
+
+// Layout of the ids made by TaggedIdGenerator, after the strategy and a
+// "-" separator: Unix milliseconds, instance and sequence, each as
+// fixed-width lower-case base 36.
+const (
+	clientOrderIdTimeWidth     = 9
+	clientOrderIdInstanceWidth = 2
+	clientOrderIdSequenceWidth = 4
+	clientOrderIdSuffixWidth   = clientOrderIdTimeWidth + clientOrderIdInstanceWidth + clientOrderIdSequenceWidth
+
+	// MaxStrategyLength is the longest strategy tag TaggedIdGenerator
+	// accepts.
+	MaxStrategyLength = MaxClientOrderIdLength - 1 - clientOrderIdSuffixWidth
+)
+
+// ClientOrderIdGenerator creates client order ids for orders placed
+// without one. Implementations must be safe for concurrent use and never
+// return the same id twice.
+type ClientOrderIdGenerator interface {
+	// NextClientOrderId returns a new id for an order of strategy, or of
+	// the generator's default strategy when strategy is empty.
+	NextClientOrderId(strategy string) (string, error)
+}
+
+// ClientOrderIdOptions configures a TaggedIdGenerator.
+type ClientOrderIdOptions struct {
+	// Strategy is the default strategy tag, used when the order's context
+	// carries none (see WithStrategy). Required.
+	Strategy string
+
+	// Instance tells apart processes that generate ids for the same
+	// strategy at the same time: two lower-case base 36 characters.
+	// Defaults to a random value.
+	Instance string
+
+	// Now is the clock ids are stamped with. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// TaggedIdGenerator makes client order ids of the form
+//
+//	<strategy>-<time><instance><sequence>
+//
+// for example "grid-mvapkzcr0k7f0000", where time is the Unix millisecond
+// (9 characters), instance identifies the generator (2 characters) and
+// sequence counts the ids made within that millisecond (4 characters), all
+// in lower-case base 36.
+//
+// Behavior:
+//   - Ids of one generator sort lexically in the order they were made,
+//     even if the clock steps back: the time never decreases, and when a
+//     millisecond's sequence runs out the next millisecond is used.
+//   - A strategy may use letters, digits and "._:/-" and be at most
+//     MaxStrategyLength characters long, so every id fits within
+//     MaxClientOrderIdLength.
+//   - ParseClientOrderId reads the parts back, e.g. to attribute orders
+//     from GetOrdersHistory to strategies.
+//
+// Example:
+//
+//	ids, err := tabdeal.NewTaggedIdGenerator(tabdeal.ClientOrderIdOptions{Strategy: "grid"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client, _ := tabdeal.NewClient(tabdeal.ClientOptions{
+//	    ApiKey:         key,
+//	    ApiSecret:      secret,
+//	    ClientOrderIds: ids,
+//	})
+type TaggedIdGenerator struct {
+	opts ClientOrderIdOptions
+
+	mu       sync.Mutex
+	last     int64
+	sequence int64
+}
+
+// NewTaggedIdGenerator validates opts and returns a generator.
+func NewTaggedIdGenerator(opts ClientOrderIdOptions) (*TaggedIdGenerator, error) {
+	if err := validateStrategy(opts.Strategy); err != nil {
+		return nil, err
+	}
+
+	if opts.Instance == "" {
+		opts.Instance = formatBase36(rand.Int64N(36*36), clientOrderIdInstanceWidth)
+	}
+	if len(opts.Instance) != clientOrderIdInstanceWidth || !isBase36(opts.Instance) {
+		return nil, &GoTabdealError{Message: "instance must be two lower-case base 36 characters"}
+	}
+
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &TaggedIdGenerator{opts: opts}, nil
+}
+
+// NextClientOrderId implements ClientOrderIdGenerator.
+func (g *TaggedIdGenerator) NextClientOrderId(strategy string) (string, error) {
+	if strategy == "" {
+		strategy = g.opts.Strategy
+	} else if err := validateStrategy(strategy); err != nil {
+		return "", err
+	}
+
+	now := g.opts.Now().UnixMilli()
+
+	g.mu.Lock()
+	switch {
+	case now > g.last:
+		g.last, g.sequence = now, 0
+	case g.sequence+1 < maxBase36(clientOrderIdSequenceWidth):
+		g.sequence++
+	default:
+		g.last, g.sequence = g.last+1, 0
+	}
+	at, sequence := g.last, g.sequence
+	g.mu.Unlock()
+
+	return strategy + "-" +
+		formatBase36(at, clientOrderIdTimeWidth) +
+		g.opts.Instance +
+		formatBase36(sequence, clientOrderIdSequenceWidth), nil
+}
+
+// ClientOrderIdInfo holds the parts of an id made by TaggedIdGenerator.
+type ClientOrderIdInfo struct {
+	Strategy string
+	Time     time.Time
+	Instance string
+	Sequence int64
+}
+
+// ParseClientOrderId splits an id made by TaggedIdGenerator into its
+// parts. Ids of any other form return an error, so it can also tell
+// generated ids from hand-made ones.
+//
+// Example:
+//
+//	for _, order := range *history {
+//	    if info, err := tabdeal.ParseClientOrderId(order.ClientOrderId); err == nil {
+//	        byStrategy[info.Strategy] = append(byStrategy[info.Strategy], order)
+//	    }
+//	}
+func ParseClientOrderId(id string) (ClientOrderIdInfo, error) {
+	invalid := &GoTabdealError{Message: "not a generated client order id: " + strconv.Quote(id)}
+
+	sep := strings.LastIndexByte(id, '-')
+	if sep < 0 || len(id) > MaxClientOrderIdLength {
+		return ClientOrderIdInfo{}, invalid
+	}
+
+	strategy, suffix := id[:sep], id[sep+1:]
+	if validateStrategy(strategy) != nil || len(suffix) != clientOrderIdSuffixWidth || !isBase36(suffix) {
+		return ClientOrderIdInfo{}, invalid
+	}
+
+	millis, _ := strconv.ParseInt(suffix[:clientOrderIdTimeWidth], 36, 64)
+	sequence, _ := strconv.ParseInt(suffix[clientOrderIdTimeWidth+clientOrderIdInstanceWidth:], 36, 64)
+
+	return ClientOrderIdInfo{
+		Strategy: strategy,
+		Time:     time.UnixMilli(millis),
+		Instance: suffix[clientOrderIdTimeWidth : clientOrderIdTimeWidth+clientOrderIdInstanceWidth],
+		Sequence: sequence,
+	}, nil
+}
+
+type strategyKey struct{}
+
+// WithStrategy returns a context that tags the orders created with it:
+// CreateOrderCtx passes strategy to the client's ClientOrderIdGenerator
+// when it generates an id.
+//
+// Example:
+//
+//	ctx := tabdeal.WithStrategy(context.Background(), "breakout")
+//	resp, err := client.CreateOrderCtx(ctx, params)
+func WithStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, strategyKey{}, strategy)
+}
+
+// nextClientOrderId generates a client order id with the client's
+// generator, tagged with the strategy of ctx. It returns "" when the
+// client has no generator.
+func (c *Client) nextClientOrderId(ctx context.Context) (string, error) {
+	if c.ClientOrderIds == nil {
+		return "", nil
+	}
+
+	strategy, _ := ctx.Value(strategyKey{}).(string)
+	id, err := c.ClientOrderIds.NextClientOrderId(strategy)
+	if err != nil {
+		return "", err
+	}
+	if id == "" || len(id) > MaxClientOrderIdLength {
+		return "", &GoTabdealError{Message: "generated client order id " + strconv.Quote(id) + " must be 1 to " + strconv.Itoa(MaxClientOrderIdLength) + " characters"}
+	}
+	return id, nil
+}
+
+func validateStrategy(strategy string) error {
+	if strategy == "" || len(strategy) > MaxStrategyLength {
+		return &GoTabdealError{Message: "strategy must be 1 to " + strconv.Itoa(MaxStrategyLength) + " characters"}
+	}
+	for _, r := range strategy {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._:/-", r)) {
+			return &GoTabdealError{Message: "strategy " + strconv.Quote(strategy) + " may only contain letters, digits and ._:/-"}
+		}
+	}
+	return nil
+}
+
+func formatBase36(n int64, width int) string {
+	s := strconv.FormatInt(n, 36)
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
+
+func maxBase36(width int) int64 {
+	n := int64(1)
+	for range width {
+		n *= 36
+	}
+	return n
+}
+
+func isBase36(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return true
+}
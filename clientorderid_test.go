@@ -0,0 +1,234 @@
+package tabdeal
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stepClock is a settable clock for TaggedIdGenerator.
+type stepClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *stepClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func newIdGenerator(tt *testing.T, clock *stepClock) *TaggedIdGenerator {
+	tt.Helper()
+
+	ids, err := NewTaggedIdGenerator(ClientOrderIdOptions{Strategy: "grid", Instance: "k7", Now: clock.Now})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return ids
+}
+
+// nextId returns the next id or fails the test.
+func nextId(tt *testing.T, ids *TaggedIdGenerator, strategy string) string {
+	tt.Helper()
+
+	id, err := ids.NextClientOrderId(strategy)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return id
+}
+
+func TestNextClientOrderIdOrdering(tt *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := &stepClock{now: start}
+	ids := newIdGenerator(tt, clock)
+
+	var made []string
+	for i := range 5 {
+		made = append(made, nextId(tt, ids, ""))
+		if i == 2 {
+			clock.Set(start.Add(time.Millisecond))
+		}
+	}
+
+	if made[0] != "grid-0loyw3v28k70000" {
+		tt.Errorf("first id = %q", made[0])
+	}
+	if !slices.IsSorted(made) || len(slices.Compact(slices.Clone(made))) != len(made) {
+		tt.Errorf("ids out of order or repeated: %q", made)
+	}
+
+	// A new millisecond restarts the sequence.
+	info, err := ParseClientOrderId(made[3])
+	if err != nil || info.Sequence != 0 || !info.Time.Equal(start.Add(time.Millisecond)) {
+		tt.Errorf("ParseClientOrderId(%q) = %+v, %v", made[3], info, err)
+	}
+}
+
+func TestNextClientOrderIdSequenceRollover(tt *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := &stepClock{now: start}
+	ids := newIdGenerator(tt, clock)
+
+	nextId(tt, ids, "")
+	ids.sequence = maxBase36(clientOrderIdSequenceWidth) - 2
+
+	last := nextId(tt, ids, "")
+	rolled := nextId(tt, ids, "")
+	if !strings.HasSuffix(last, "zzzz") || rolled <= last {
+		tt.Fatalf("ids %q then %q, want the last sequence then a later id", last, rolled)
+	}
+
+	// The sequence ran out, so the id borrows the next millisecond.
+	info, _ := ParseClientOrderId(rolled)
+	if !info.Time.Equal(start.Add(time.Millisecond)) || info.Sequence != 0 {
+		tt.Errorf("rolled over to %v sequence %d", info.Time.UnixMilli(), info.Sequence)
+	}
+
+	// When the clock reaches that millisecond, it keeps counting.
+	clock.Set(start.Add(time.Millisecond))
+	if info, _ := ParseClientOrderId(nextId(tt, ids, "")); info.Sequence != 1 {
+		tt.Errorf("sequence %d after the clock caught up, want 1", info.Sequence)
+	}
+}
+
+func TestNextClientOrderIdClockStepsBack(tt *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := &stepClock{now: start}
+	ids := newIdGenerator(tt, clock)
+
+	before := nextId(tt, ids, "")
+	clock.Set(start.Add(-time.Hour))
+	after := nextId(tt, ids, "")
+
+	if after <= before {
+		tt.Fatalf("id %q after the clock stepped back sorts before %q", after, before)
+	}
+	if info, _ := ParseClientOrderId(after); !info.Time.Equal(start) || info.Sequence != 1 {
+		tt.Errorf("stepped back to %v sequence %d, want the last time and the next sequence", info.Time, info.Sequence)
+	}
+}
+
+func TestNextClientOrderIdConcurrent(tt *testing.T) {
+	clock := &stepClock{now: time.UnixMilli(1700000000000)}
+	ids := newIdGenerator(tt, clock)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = map[string]bool{}
+	)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 500 {
+				id, err := ids.NextClientOrderId("")
+				if err != nil {
+					tt.Error(err)
+					return
+				}
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 8*500 {
+		tt.Errorf("%d distinct ids, want %d", len(seen), 8*500)
+	}
+}
+
+func TestNextClientOrderIdLengthLimit(tt *testing.T) {
+	ids := newIdGenerator(tt, &stepClock{now: time.Now()})
+
+	longest := strings.Repeat("s", MaxStrategyLength)
+	if id := nextId(tt, ids, longest); len(id) != MaxClientOrderIdLength {
+		tt.Errorf("id for the longest strategy has %d characters, want %d", len(id), MaxClientOrderIdLength)
+	}
+
+	for _, strategy := range []string{longest + "s", "grid bot", "grid#1", "سبد"} {
+		if id, err := ids.NextClientOrderId(strategy); err == nil {
+			tt.Errorf("NextClientOrderId(%q) = %q, want an error", strategy, id)
+		}
+	}
+
+	for _, opts := range []ClientOrderIdOptions{
+		{},
+		{Strategy: "grid", Instance: "K7"},
+		{Strategy: "grid", Instance: "k"},
+	} {
+		if _, err := NewTaggedIdGenerator(opts); err == nil {
+			tt.Errorf("NewTaggedIdGenerator(%+v) accepted invalid options", opts)
+		}
+	}
+}
+
+func TestParseClientOrderId(tt *testing.T) {
+	at := time.UnixMilli(1700000000123)
+	ids := newIdGenerator(tt, &stepClock{now: at})
+
+	for _, strategy := range []string{"grid", "mean-revert", "a-b-c", "-", "x:y/z.1"} {
+		id := nextId(tt, ids, strategy)
+		info, err := ParseClientOrderId(id)
+		if err != nil {
+			tt.Errorf("ParseClientOrderId(%q): %v", id, err)
+			continue
+		}
+		if info.Strategy != strategy || !info.Time.Equal(at) || info.Instance != "k7" {
+			tt.Errorf("ParseClientOrderId(%q) = %+v, want strategy %q", id, info, strategy)
+		}
+	}
+
+	for _, id := range []string{
+		"",
+		"manual-order",
+		"0loyw3v28k70000",
+		"-0loyw3v28k70000",
+		"grid-0loyw3v28k7000",
+		"grid-0LOYW3V28K70000",
+		"grid bot-0loyw3v28k70000",
+		strings.Repeat("s", MaxStrategyLength+1) + "-0loyw3v28k70000",
+	} {
+		if info, err := ParseClientOrderId(id); err == nil {
+			tt.Errorf("ParseClientOrderId(%q) = %+v, want an error", id, info)
+		}
+	}
+}
+
+func TestWithStrategy(tt *testing.T) {
+	ids := newIdGenerator(tt, &stepClock{now: time.Now()})
+	client, err := NewClient(ClientOptions{ClientOrderIds: ids})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	for strategy, ctx := range map[string]context.Context{
+		"grid":     context.Background(),
+		"breakout": WithStrategy(context.Background(), "breakout"),
+	} {
+		id, err := client.nextClientOrderId(ctx)
+		if err != nil {
+			tt.Fatal(err)
+		}
+		if info, err := ParseClientOrderId(id); err != nil || info.Strategy != strategy {
+			tt.Errorf("id %q has strategy %q, want %q", id, info.Strategy, strategy)
+		}
+	}
+
+	if _, err := client.nextClientOrderId(WithStrategy(context.Background(), "bad strategy")); err == nil {
+		tt.Error("nextClientOrderId accepted an invalid strategy from the context")
+	}
+}
@@ -0,0 +1,288 @@
+package tabdeal
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+	u "github.com/darhelm/go-tabdeal/utils"
+)
+
+// DryRunOptions configures dry-run mode, in which CreateOrder, CancelOrder,
+// CancelOrderBulk, CreateOCOOrder and CancelOrderList go through every
+// client-side step (quantization, validation, signing with the real
+// credentials) but are never sent. Reads, including the exchangeInfo and
+// open-order lookups dry-run mode performs itself, still go to the
+// exchange.
+//
+// Synthetic responses:
+//   - CreateOrder returns the order as NEW, with a negative OrderId that
+//     never collides with a real one and the NewClientOrderId (or a
+//     generated "dryrun-" id) as ClientOrderId. With a
+//     ClientOptions.ClientOrderIds generator, CreateOrder fills in
+//     NewClientOrderId from it before the dry run, so the "dryrun-"
+//     fallback is not used; OCO lists and legs still fall back to it.
+//   - CancelOrder returns the addressed order as CANCELED.
+//   - CancelOrderBulk returns the currently open orders as CANCELED.
+//   - CreateOCOOrder returns an executing list with a negative
+//     OrderListId and both legs as NEW.
+//   - CancelOrderList returns the addressed list as ALL_DONE.
+//
+// Example:
+//
+//	client, _ := tabdeal.NewClient(tabdeal.ClientOptions{
+//	    ApiKey:    key,
+//	    ApiSecret: secret,
+//	    DryRun:    &tabdeal.DryRunOptions{},
+//	})
+type DryRunOptions struct {
+	// OnRequest receives every request that was withheld. When nil, the
+	// method, endpoint and unsigned parameters are logged with the
+	// standard log package; the signed URL is only passed to OnRequest.
+	OnRequest func(DryRunRequest)
+
+	// TestOrders additionally sends every CreateOrder to TestOrder, so the
+	// exchange validates it without placing it.
+	TestOrders bool
+}
+
+// DryRunRequest is a mutating request withheld by dry-run mode.
+type DryRunRequest struct {
+	// Method and URL are exactly what would have been sent, including the
+	// signed query string.
+	Method string
+	URL    string
+
+	// Params is the request's parameter struct, such as
+	// t.CreateOrderParams after quantization.
+	Params interface{}
+
+	// Response is the synthetic response returned to the caller.
+	Response interface{}
+
+	// Time is when the request was withheld.
+	Time time.Time
+}
+
+// dryRunOrderId numbers the synthetic orders and order lists of dry-run
+// mode.
+var dryRunOrderId atomic.Int64
+
+func (c *Client) dryRunCreateOrder(ctx context.Context, params t.CreateOrderParams) (*t.CreateOrderResponse, error) {
+	if !c.ValidateOrders {
+		if err := c.validateOrder(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.DryRun.TestOrders {
+		if err := c.ApiRequestCtx(ctx, "POST", "/order/test", true, params, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	id := -dryRunOrderId.Add(1)
+	clientOrderId := params.NewClientOrderId
+	if clientOrderId == "" {
+		clientOrderId = "dryrun-" + strconv.FormatInt(-id, 10)
+	}
+
+	now := time.Now().UnixMilli()
+	response := &t.CreateOrderResponse{
+		BaseOrderResponse: t.BaseOrderResponse{
+			Symbol:        params.Symbol,
+			TabdealSymbol: params.TabdealSymbol,
+			OrderId:       id,
+			OrderListId:   -1,
+			ClientOrderId: clientOrderId,
+			TransactTime:  now,
+			Time:          now,
+			UpdateTime:    now,
+			Price:         params.Price,
+			OrigQty:       params.Quantity,
+			Status:        t.OrderStatusNew,
+			TimeInForce:   params.TimeInForce,
+			Type:          params.Type,
+			Side:          params.Side,
+			StopPrice:     params.StopPrice,
+			IsWorking:     true,
+		},
+		Fills: []t.Fills{},
+	}
+
+	if err := c.withholdRequest(ctx, "POST", "/order", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) dryRunCancelOrder(ctx context.Context, params t.CancelOrderParams) (*t.CancelOrderResponse, error) {
+	if err := requireSymbol(params.BaseSymbolParams, ""); err != nil {
+		return nil, err
+	}
+	if params.OrderId == 0 && params.OrigClientOrderId == "" {
+		return nil, &GoTabdealError{Message: "orderId or origClientOrderId is required"}
+	}
+
+	now := time.Now().UnixMilli()
+	response := &t.CancelOrderResponse{
+		BaseOrderResponse: t.BaseOrderResponse{
+			Symbol:        params.Symbol,
+			TabdealSymbol: params.TabdealSymbol,
+			OrderId:       params.OrderId,
+			OrderListId:   -1,
+			ClientOrderId: params.OrigClientOrderId,
+			TransactTime:  now,
+			UpdateTime:    now,
+			Status:        t.OrderStatusCanceled,
+		},
+	}
+
+	if err := c.withholdRequest(ctx, "DELETE", "/order", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) dryRunCancelOrderBulk(ctx context.Context, params t.CancelOrderBulkParams) (*[]*t.CancelOrderResponse, error) {
+	open, err := c.GetOpenOrdersCtx(ctx, t.GetOpenOrdersParams{BaseSymbolParams: params.BaseSymbolParams})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	canceled := make([]*t.CancelOrderResponse, 0)
+	for _, order := range derefPage(open) {
+		response := &t.CancelOrderResponse{BaseOrderResponse: *order}
+		response.Status = t.OrderStatusCanceled
+		response.IsWorking = false
+		response.TransactTime = now
+		response.UpdateTime = now
+		canceled = append(canceled, response)
+	}
+
+	if err := c.withholdRequest(ctx, "DELETE", "/openOrders", params, canceled); err != nil {
+		return nil, err
+	}
+	return &canceled, nil
+}
+
+func (c *Client) dryRunCreateOCOOrder(ctx context.Context, params t.CreateOCOOrderParams) (*t.OrderListResponse, error) {
+	if !c.ValidateOrders {
+		if err := c.validateOCOOrder(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+
+	listId := -dryRunOrderId.Add(1)
+	listClientOrderId := params.ListClientOrderId
+	if listClientOrderId == "" {
+		listClientOrderId = "dryrun-" + strconv.FormatInt(-listId, 10)
+	}
+
+	now := time.Now().UnixMilli()
+	response := &t.OrderListResponse{
+		OrderList: t.OrderList{
+			OrderListId:       listId,
+			ContingencyType:   t.ContingencyTypeOCO,
+			ListStatusType:    t.ListStatusTypeExecStarted,
+			ListOrderStatus:   t.ListOrderStatusExecuting,
+			ListClientOrderId: listClientOrderId,
+			TransactionTime:   now,
+			Symbol:            params.Symbol,
+		},
+	}
+
+	// The exchange reports the stop leg first.
+	limit, stop := params.Legs()
+	for _, leg := range []t.CreateOrderParams{stop, limit} {
+		id := -dryRunOrderId.Add(1)
+		if leg.NewClientOrderId == "" {
+			leg.NewClientOrderId = "dryrun-" + strconv.FormatInt(-id, 10)
+		}
+
+		response.Orders = append(response.Orders, t.OrderListOrder{
+			Symbol:        params.Symbol,
+			OrderId:       id,
+			ClientOrderId: leg.NewClientOrderId,
+		})
+		response.OrderReports = append(response.OrderReports, t.BaseOrderResponse{
+			Symbol:        params.Symbol,
+			TabdealSymbol: params.TabdealSymbol,
+			OrderId:       id,
+			OrderListId:   listId,
+			ClientOrderId: leg.NewClientOrderId,
+			TransactTime:  now,
+			Price:         leg.Price,
+			OrigQty:       leg.Quantity,
+			Status:        t.OrderStatusNew,
+			TimeInForce:   leg.TimeInForce,
+			Type:          leg.Type,
+			Side:          leg.Side,
+			StopPrice:     leg.StopPrice,
+		})
+	}
+
+	if err := c.withholdRequest(ctx, "POST", "/order/oco", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) dryRunCancelOrderList(ctx context.Context, params t.CancelOrderListParams) (*t.OrderListResponse, error) {
+	if err := requireSymbol(params.BaseSymbolParams, ""); err != nil {
+		return nil, err
+	}
+	if params.OrderListId == 0 && params.ListClientOrderId == "" {
+		return nil, &GoTabdealError{Message: "orderListId or listClientOrderId is required"}
+	}
+
+	response := &t.OrderListResponse{
+		OrderList: t.OrderList{
+			OrderListId:       params.OrderListId,
+			ContingencyType:   t.ContingencyTypeOCO,
+			ListStatusType:    t.ListStatusTypeAllDone,
+			ListOrderStatus:   t.ListOrderStatusAllDone,
+			ListClientOrderId: params.ListClientOrderId,
+			TransactionTime:   time.Now().UnixMilli(),
+			Symbol:            params.Symbol,
+		},
+	}
+
+	if err := c.withholdRequest(ctx, "DELETE", "/orderList", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// withholdRequest signs a request exactly as doRequest would and reports
+// it through DryRunOptions.OnRequest instead of sending it.
+func (c *Client) withholdRequest(ctx context.Context, method, endpoint string, params, response interface{}) error {
+	url, err := c.encodeRequest(ctx, c.createApiURI(method, endpoint), true, params)
+	if err != nil {
+		return err
+	}
+
+	request := DryRunRequest{
+		Method:   method,
+		URL:      url,
+		Params:   params,
+		Response: response,
+		Time:     time.Now(),
+	}
+
+	if c.DryRun.OnRequest != nil {
+		c.DryRun.OnRequest(request)
+		return nil
+	}
+
+	// The log leaves out the signature, timestamp and recvWindow. params
+	// already encoded once in encodeRequest, so this cannot fail.
+	unsigned, _ := u.EncodeParams(params)
+	log.Printf("tabdeal: dry run: %s %s %s", method, endpoint, unsigned.Encode())
+
+	return nil
+}
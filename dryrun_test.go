@@ -0,0 +1,56 @@
+package tabdeal
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+func TestDryRunLogsUnsignedRequest(tt *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/exchangeInfo") {
+			_, _ = w.Write([]byte(`[{"symbol":"BTCIRT","tabdealSymbol":"BTC_IRT","status":"TRADING"}]`))
+			return
+		}
+		tt.Errorf("dry run sent %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	var logged bytes.Buffer
+	output, flags := log.Writer(), log.Flags()
+	log.SetOutput(&logged)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	}()
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL, DryRun: &DryRunOptions{}})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	_, err = client.CreateOrderCtx(context.Background(), t.CreateOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideBuy,
+		Type:             t.OrderTypeLimit,
+		TimeInForce:      t.TimeInForceGTC,
+		Quantity:         t.MustParseDecimal("1"),
+		Price:            t.MustParseDecimal("100"),
+		NewClientOrderId: "mine",
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	want := "tabdeal: dry run: POST /order symbol=BTCIRT&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&newClientOrderId=mine&price=100\n"
+	if logged.String() != want {
+		tt.Errorf("logged %q\nwant   %q", logged.String(), want)
+	}
+}
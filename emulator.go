@@ -0,0 +1,941 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// EmulatedOrderState is the lifecycle state of an emulated order.
+type EmulatedOrderState string
+
+const (
+	// EmulatedPending means the order is watching prices.
+	EmulatedPending EmulatedOrderState = "PENDING"
+
+	// EmulatedTriggering means the stop condition was met and the real
+	// order is being placed, or its placement could not be confirmed yet.
+	EmulatedTriggering EmulatedOrderState = "TRIGGERING"
+
+	// EmulatedTriggered means the real order was placed; ExchangeOrderId
+	// identifies it.
+	EmulatedTriggered EmulatedOrderState = "TRIGGERED"
+
+	// EmulatedCanceled means the order was cancelled before it triggered.
+	EmulatedCanceled EmulatedOrderState = "CANCELED"
+
+	// EmulatedFailed means the exchange or client-side validation rejected
+	// the real order; Error says why.
+	EmulatedFailed EmulatedOrderState = "FAILED"
+)
+
+// IsFinal reports whether the order can no longer change state.
+func (s EmulatedOrderState) IsFinal() bool {
+	return s == EmulatedTriggered || s == EmulatedCanceled || s == EmulatedFailed
+}
+
+// EmulatedOrderParams describes a stop-loss or trailing-stop order that the
+// client watches itself and places as a real order when it triggers.
+//
+// A plain stop triggers when the price reaches StopPrice: for a SELL, when
+// the best bid (or last trade) falls to StopPrice or below; for a BUY, when
+// the best ask (or last trade) rises to StopPrice or above.
+//
+// A trailing stop (TrailingDelta > 0) follows the best price seen since it
+// was activated: a SELL triggers once the price falls TrailingDelta basis
+// points below the highest price, a BUY once it rises TrailingDelta basis
+// points above the lowest. StopPrice is then the optional activation price:
+// the order starts trailing once the price reaches it (at or above it for a
+// SELL, at or below it for a BUY), or immediately when it is zero.
+//
+// The real order is a MARKET order, or a LIMIT order at LimitPrice with
+// TimeInForce (default GTC) when LimitPrice is set. ClientOrderId is used as
+// its newClientOrderId; when empty, one is made by the client's
+// ClientOrderIdGenerator (tagged with the strategy of Place's context) or,
+// without a generator, as "emu-...".
+type EmulatedOrderParams struct {
+	t.BaseSymbolParams
+	Side          t.OrderSide   `json:"side"`
+	Quantity      t.Decimal     `json:"quantity"`
+	StopPrice     t.Decimal     `json:"stopPrice,omitempty"`
+	TrailingDelta int64         `json:"trailingDelta,omitempty"`
+	LimitPrice    t.Decimal     `json:"limitPrice,omitempty"`
+	TimeInForce   t.TimeInForce `json:"timeInForce,omitempty"`
+	ClientOrderId string        `json:"clientOrderId,omitempty"`
+}
+
+// EmulatedOrder is the state of an emulated order, as persisted by an
+// EmulatedOrderStore.
+type EmulatedOrder struct {
+	// OrderId is negative, so it never collides with an exchange order id.
+	// It is unique within the emulator's store.
+	OrderId       int64               `json:"orderId"`
+	ClientOrderId string              `json:"clientOrderId"`
+	Params        EmulatedOrderParams `json:"params"`
+	State         EmulatedOrderState  `json:"state"`
+
+	// StopPrice is the current trigger level: Params.StopPrice for a
+	// plain stop, the trailed level for an activated trailing stop, zero
+	// for a trailing stop that is not active yet.
+	StopPrice t.Decimal `json:"stopPrice,omitempty"`
+
+	// Activated and Extreme track a trailing stop: whether it has started
+	// trailing and the highest (SELL) or lowest (BUY) price seen since.
+	Activated bool      `json:"activated,omitempty"`
+	Extreme   t.Decimal `json:"extreme,omitempty"`
+
+	// TriggeredPrice is the price that met the stop condition.
+	TriggeredPrice t.Decimal `json:"triggeredPrice,omitempty"`
+
+	// ExchangeOrderId is the OrderId of the real order once placed.
+	ExchangeOrderId int64 `json:"exchangeOrderId,omitempty"`
+
+	// Error is the last placement error: the rejection of a FAILED
+	// order, or why a TRIGGERING order could not be confirmed yet.
+	Error string `json:"error,omitempty"`
+
+	// Times are Unix milliseconds.
+	CreatedAt   int64 `json:"createdAt"`
+	UpdatedAt   int64 `json:"updatedAt"`
+	TriggeredAt int64 `json:"triggeredAt,omitempty"`
+}
+
+// IsTrailing reports whether the order is a trailing stop.
+func (o EmulatedOrder) IsTrailing() bool {
+	return o.Params.TrailingDelta > 0
+}
+
+// OrderParams returns the real order placed when o triggers.
+func (o EmulatedOrder) OrderParams() t.CreateOrderParams {
+	params := t.CreateOrderParams{
+		BaseSymbolParams: o.Params.BaseSymbolParams,
+		Side:             o.Params.Side,
+		Type:             t.OrderTypeMarket,
+		Quantity:         o.Params.Quantity,
+		NewClientOrderId: o.ClientOrderId,
+	}
+	if o.Params.LimitPrice.IsPositive() {
+		params.Type = t.OrderTypeLimit
+		params.Price = o.Params.LimitPrice
+		params.TimeInForce = cmp.Or(o.Params.TimeInForce, t.TimeInForceGTC)
+	}
+	return params
+}
+
+// OrderStatus presents o as a native stop order, the way GetOrderStatus
+// reports it before it triggers: a STOP_LOSS (or STOP_LOSS_LIMIT) order
+// that is NEW while pending or triggering, CANCELED once cancelled and
+// REJECTED once failed.
+func (o EmulatedOrder) OrderStatus() t.OrderStatusResponse {
+	status := t.OrderStatusNew
+	switch o.State {
+	case EmulatedCanceled:
+		status = t.OrderStatusCanceled
+	case EmulatedFailed:
+		status = t.OrderStatusRejected
+	}
+
+	params := o.OrderParams()
+	orderType := t.OrderTypeStopLoss
+	if params.Type == t.OrderTypeLimit {
+		orderType = t.OrderTypeStopLossLimit
+	}
+
+	return t.OrderStatusResponse{
+		BaseOrderResponse: t.BaseOrderResponse{
+			Symbol:               o.Params.Symbol,
+			TabdealSymbol:        o.Params.TabdealSymbol,
+			OrderId:              o.OrderId,
+			OrderListId:          -1,
+			ClientOrderId:        o.ClientOrderId,
+			TransactTime:         o.CreatedAt,
+			Time:                 o.CreatedAt,
+			Price:                params.Price,
+			OrigQty:              params.Quantity,
+			Status:               status,
+			TimeInForce:          params.TimeInForce,
+			Type:                 orderType,
+			Side:                 params.Side,
+			StopPrice:            o.StopPrice,
+			UpdateTime:           o.UpdatedAt,
+			IsStopOrderTriggered: o.State == EmulatedTriggering || o.State == EmulatedTriggered,
+		},
+	}
+}
+
+// EmulatedOrderStore persists emulated orders across restarts. Save
+// receives every order the emulator knows, each time one changes state;
+// moves of trailing levels are saved in batches (see
+// OrderEmulatorOptions.SaveDelay).
+type EmulatedOrderStore interface {
+	Load() ([]EmulatedOrder, error)
+	Save(orders []EmulatedOrder) error
+}
+
+// FileOrderStore is an EmulatedOrderStore backed by a JSON file. Saves
+// write a temporary file next to Path and rename it over Path, so a crash
+// never leaves a half-written file behind.
+type FileOrderStore struct {
+	Path string
+}
+
+// NewFileOrderStore returns a store that keeps orders in the file at path.
+func NewFileOrderStore(path string) *FileOrderStore {
+	return &FileOrderStore{Path: path}
+}
+
+// Load reads the stored orders. A missing file holds no orders.
+func (s *FileOrderStore) Load() ([]EmulatedOrder, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &GoTabdealError{Message: "reading emulated orders", Err: err}
+	}
+
+	var orders []EmulatedOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, &GoTabdealError{Message: "decoding emulated orders", Err: err}
+	}
+	return orders, nil
+}
+
+// Save replaces the stored orders.
+func (s *FileOrderStore) Save(orders []EmulatedOrder) error {
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return &GoTabdealError{Message: "encoding emulated orders", Err: err}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return &GoTabdealError{Message: "writing emulated orders", Err: err}
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return &GoTabdealError{Message: "writing emulated orders", Err: err}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return &GoTabdealError{Message: "writing emulated orders", Err: err}
+	}
+	if err := tmp.Close(); err != nil {
+		return &GoTabdealError{Message: "writing emulated orders", Err: err}
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return &GoTabdealError{Message: "writing emulated orders", Err: err}
+	}
+	return nil
+}
+
+// OrderEmulatorOptions configures an OrderEmulator.
+type OrderEmulatorOptions struct {
+	// Store persists orders across restarts. Nil keeps them in memory
+	// only.
+	Store EmulatedOrderStore
+
+	// SaveDelay is how long a trailing stop's new extreme may wait before
+	// it is saved, so a trending market does not rewrite the store on
+	// every price. State changes are saved at once, together with any
+	// pending trailing levels. After a crash a trailing stop resumes from
+	// its last saved extreme. Defaults to one second.
+	SaveDelay time.Duration
+
+	// PollInterval, when positive, makes Start poll GetBookTicker for
+	// every symbol with pending orders, in addition to any prices fed in
+	// with OnTrade, OnQuote or the Follow methods. It also sets how often
+	// unconfirmed placements are retried.
+	PollInterval time.Duration
+
+	// OnUpdate, when set, receives every order whose state or trigger
+	// level changed. It is called synchronously from the goroutine that
+	// fed the price and must not block.
+	OnUpdate func(EmulatedOrder)
+
+	// OnError, when set, receives failed placements, failed saves and
+	// failed polls. Orders whose placement could not be confirmed stay
+	// TRIGGERING and are reconciled later.
+	OnError func(error)
+}
+
+// OrderEmulator emulates stop-loss and trailing-stop orders on markets
+// that do not support them natively: it watches prices and places a real
+// order through CreateOrder when a stop triggers.
+//
+// Behavior:
+//   - SELL stops are checked against the best bid (or trade price) and
+//     BUY stops against the best ask, whichever feed is used.
+//   - An order triggers at most once. It moves from PENDING to TRIGGERING
+//     under a lock, and the change is saved before the real order is
+//     sent; a price arriving in the meantime finds it no longer pending.
+//   - The real order carries the emulated ClientOrderId as
+//     newClientOrderId, so a placement whose outcome is unknown (a timeout
+//     or 5xx) is looked up with GetOrderStatus instead of being sent
+//     again. Until that lookup succeeds the order stays TRIGGERING; Start
+//     and the poll loop retry it.
+//   - CreateOrder applies the client's quantization, validation and
+//     dry-run settings to the real order as usual.
+//   - Once registered with NewOrderEmulator, GetOrderStatus answers for
+//     emulated orders too; see Client.GetOrderStatus.
+//
+// Example:
+//
+//	emulator, err := client.NewOrderEmulator(tabdeal.OrderEmulatorOptions{
+//	    Store: tabdeal.NewFileOrderStore("stops.json"),
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	emulator.Start(ctx)
+//	go emulator.FollowBookTicker(ctx, tickers)
+//
+//	order, err := emulator.Place(ctx, tabdeal.EmulatedOrderParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    Side:             t.OrderSideSell,
+//	    Quantity:         t.MustParseDecimal("0.01"),
+//	    TrailingDelta:    200, // 2%
+//	})
+type OrderEmulator struct {
+	client *Client
+	opts   OrderEmulatorOptions
+
+	mu       sync.Mutex
+	orders   map[string]*EmulatedOrder
+	inFlight map[string]bool
+	lastId   int64
+	ctx      context.Context
+
+	// dirty is set when trailing levels changed after the last save;
+	// saveTimer is the pending batched save.
+	dirty     bool
+	saveTimer *time.Timer
+
+	fires sync.WaitGroup
+}
+
+// NewOrderEmulator loads the orders saved in opts.Store and registers the
+// emulator with the client, replacing any emulator registered before.
+// Prices are not watched until they are fed in or Start polls them.
+func (c *Client) NewOrderEmulator(opts OrderEmulatorOptions) (*OrderEmulator, error) {
+	if opts.SaveDelay <= 0 {
+		opts.SaveDelay = time.Second
+	}
+
+	e := &OrderEmulator{
+		client:   c,
+		opts:     opts,
+		orders:   make(map[string]*EmulatedOrder),
+		inFlight: make(map[string]bool),
+		ctx:      context.Background(),
+	}
+
+	if opts.Store != nil {
+		orders, err := opts.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			e.orders[order.ClientOrderId] = &order
+			e.lastId = min(e.lastId, order.OrderId)
+		}
+	}
+
+	c.emulator.Store(e)
+	return e, nil
+}
+
+// Start reconciles orders left TRIGGERING by a previous run and, when
+// PollInterval is set, starts polling prices. Placements are sent with ctx
+// from then on, and polling stops when ctx is done.
+//
+// For every TRIGGERING order, the real order is looked up by its client
+// order id: if the exchange has it, the emulated order becomes TRIGGERED;
+// if not, it is placed now. Start returns the errors of lookups that
+// failed; those orders are retried by the poll loop.
+func (e *OrderEmulator) Start(ctx context.Context) error {
+	e.mu.Lock()
+	e.ctx = ctx
+	var pending []EmulatedOrder
+	for _, order := range e.orders {
+		if order.State == EmulatedTriggering && !e.inFlight[order.ClientOrderId] {
+			e.inFlight[order.ClientOrderId] = true
+			pending = append(pending, *order)
+		}
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, order := range pending {
+		e.fires.Add(1)
+		if err := e.fire(ctx, order, true); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if e.opts.PollInterval > 0 {
+		go e.poll(ctx)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close waits for placements in flight to finish and saves trailing
+// levels that are still waiting for a batched save.
+func (e *OrderEmulator) Close() {
+	e.fires.Wait()
+	e.flush()
+}
+
+// Place starts emulating an order. When the client validates orders, the
+// real order is quantized and validated now, so a bad order fails here
+// rather than when it triggers.
+func (e *OrderEmulator) Place(ctx context.Context, params EmulatedOrderParams) (EmulatedOrder, error) {
+	if err := requireSymbol(params.BaseSymbolParams, ""); err != nil {
+		return EmulatedOrder{}, err
+	}
+	if params.Side != t.OrderSideBuy && params.Side != t.OrderSideSell {
+		return EmulatedOrder{}, &GoTabdealError{Message: "side must be BUY or SELL"}
+	}
+	if !params.Quantity.IsPositive() {
+		return EmulatedOrder{}, &GoTabdealError{Message: "quantity must be positive"}
+	}
+	if params.TrailingDelta < 0 || params.TrailingDelta >= 10000 {
+		return EmulatedOrder{}, &GoTabdealError{Message: "trailingDelta must be between 1 and 9999 basis points"}
+	}
+	if params.TrailingDelta == 0 && !params.StopPrice.IsPositive() {
+		return EmulatedOrder{}, &GoTabdealError{Message: "stopPrice or trailingDelta is required"}
+	}
+
+	now := time.Now().UnixMilli()
+	order := EmulatedOrder{
+		ClientOrderId: params.ClientOrderId,
+		Params:        params,
+		State:         EmulatedPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if !order.IsTrailing() {
+		order.StopPrice = params.StopPrice
+	}
+
+	if e.client.Quantize != nil || e.client.ValidateOrders {
+		if _, err := e.client.prepareOrder(ctx, order.OrderParams()); err != nil {
+			return EmulatedOrder{}, err
+		}
+	}
+
+	if order.ClientOrderId == "" {
+		id, err := e.client.nextClientOrderId(ctx)
+		if err != nil {
+			return EmulatedOrder{}, err
+		}
+		order.ClientOrderId = id
+	}
+
+	e.mu.Lock()
+	e.lastId--
+	order.OrderId = e.lastId
+	if order.ClientOrderId == "" {
+		order.ClientOrderId = "emu-" + strconv.FormatInt(now, 36) + "-" + strconv.FormatInt(-order.OrderId, 10)
+	}
+	if _, exists := e.orders[order.ClientOrderId]; exists {
+		e.mu.Unlock()
+		return EmulatedOrder{}, &GoTabdealError{Message: "emulated order " + order.ClientOrderId + " already exists"}
+	}
+
+	e.orders[order.ClientOrderId] = &order
+	if err := e.saveLocked(); err != nil {
+		delete(e.orders, order.ClientOrderId)
+		e.mu.Unlock()
+		return EmulatedOrder{}, err
+	}
+	e.mu.Unlock()
+
+	e.notify(order)
+	return order, nil
+}
+
+// Cancel cancels a pending order. Orders that have already triggered can
+// no longer be cancelled here; cancel the real order with CancelOrder.
+func (e *OrderEmulator) Cancel(clientOrderId string) (EmulatedOrder, error) {
+	e.mu.Lock()
+	order, ok := e.orders[clientOrderId]
+	if !ok {
+		e.mu.Unlock()
+		return EmulatedOrder{}, &GoTabdealError{Message: "unknown emulated order " + clientOrderId}
+	}
+	if order.State != EmulatedPending {
+		state := order.State
+		e.mu.Unlock()
+		return EmulatedOrder{}, &GoTabdealError{Message: "emulated order " + clientOrderId + " is " + string(state)}
+	}
+
+	previous := *order
+	order.State = EmulatedCanceled
+	order.UpdatedAt = time.Now().UnixMilli()
+	if err := e.saveLocked(); err != nil {
+		*order = previous
+		e.mu.Unlock()
+		return EmulatedOrder{}, err
+	}
+	canceled := *order
+	e.mu.Unlock()
+
+	e.notify(canceled)
+	return canceled, nil
+}
+
+// Get returns the order with the given client order id.
+func (e *OrderEmulator) Get(clientOrderId string) (EmulatedOrder, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[clientOrderId]
+	if !ok {
+		return EmulatedOrder{}, false
+	}
+	return *order, true
+}
+
+// Orders returns every known order, oldest first.
+func (e *OrderEmulator) Orders() []EmulatedOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.sortedLocked()
+}
+
+// Prune forgets finished orders last updated before the given time and
+// returns how many were removed.
+func (e *OrderEmulator) Prune(before time.Time) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	removed := make(map[string]*EmulatedOrder)
+	for id, order := range e.orders {
+		if order.State.IsFinal() && order.UpdatedAt < before.UnixMilli() {
+			removed[id] = order
+			delete(e.orders, id)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := e.saveLocked(); err != nil {
+		for id, order := range removed {
+			e.orders[id] = order
+		}
+		return 0, err
+	}
+	return len(removed), nil
+}
+
+// OnTrade feeds a trade price for symbol ("BTCIRT" or "BTC_IRT"). It is
+// used as both bid and ask.
+func (e *OrderEmulator) OnTrade(symbol string, price t.Decimal) {
+	e.observe(symbol, price, price)
+}
+
+// OnQuote feeds the best bid and ask for symbol ("BTCIRT" or "BTC_IRT").
+// A zero price leaves the orders of that side untouched.
+func (e *OrderEmulator) OnQuote(symbol string, bid, ask t.Decimal) {
+	e.observe(symbol, bid, ask)
+}
+
+// FollowTrades feeds trades from a stream subscription into the emulator.
+// It returns when ctx is done or the subscription ends.
+func (e *OrderEmulator) FollowTrades(ctx context.Context, trades *Subscription[t.TradeEvent]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-trades.C():
+			if !ok {
+				return
+			}
+			e.OnTrade(event.Symbol, event.Price)
+		}
+	}
+}
+
+// FollowBookTicker feeds best bid and ask updates from a stream
+// subscription into the emulator. It returns when ctx is done or the
+// subscription ends.
+func (e *OrderEmulator) FollowBookTicker(ctx context.Context, tickers *Subscription[t.BookTickerEvent]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-tickers.C():
+			if !ok {
+				return
+			}
+			e.OnQuote(event.Symbol, event.BidPrice, event.AskPrice)
+		}
+	}
+}
+
+// FollowOrderBook feeds the best bid and ask of a managed order book into
+// the emulator every interval (default 100ms). It returns when ctx is
+// done.
+func (e *OrderEmulator) FollowOrderBook(ctx context.Context, book *OrderBookManager, interval time.Duration) {
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !book.Synced() {
+				continue
+			}
+			bid, _ := book.BestBid()
+			ask, _ := book.BestAsk()
+			e.OnQuote(book.symbol, bid.Price, ask.Price)
+		}
+	}
+}
+
+// observe advances every pending order of symbol and fires those that
+// triggered.
+func (e *OrderEmulator) observe(symbol string, bid, ask t.Decimal) {
+	key := emulatorSymbol(symbol)
+	now := time.Now().UnixMilli()
+
+	e.mu.Lock()
+	var changed []*EmulatedOrder
+	var previous []EmulatedOrder
+	for _, order := range e.orders {
+		if order.State != EmulatedPending || emulatorSymbol(cmp.Or(order.Params.Symbol, order.Params.TabdealSymbol)) != key {
+			continue
+		}
+
+		price := bid
+		if order.Params.Side == t.OrderSideBuy {
+			price = ask
+		}
+		if !price.IsPositive() {
+			continue
+		}
+
+		before := *order
+		moved, triggered := order.advance(price)
+		if triggered {
+			order.State = EmulatedTriggering
+			order.TriggeredPrice = price
+			order.TriggeredAt = now
+		}
+		if moved || triggered {
+			order.UpdatedAt = now
+			changed = append(changed, order)
+			previous = append(previous, before)
+		}
+	}
+
+	if len(changed) == 0 {
+		e.mu.Unlock()
+		return
+	}
+
+	triggered := slices.ContainsFunc(changed, func(order *EmulatedOrder) bool { return order.State == EmulatedTriggering })
+	if !triggered {
+		// Only trailing levels moved; they are saved in a batch.
+		e.scheduleSaveLocked()
+	} else if err := e.saveLocked(); err != nil {
+		// The TRIGGERING state must be on disk before the real order is
+		// sent, or a restart could fire it again.
+		for i, order := range changed {
+			*order = previous[i]
+		}
+		e.mu.Unlock()
+		e.reportError(err)
+		return
+	}
+
+	updates := make([]EmulatedOrder, len(changed))
+	var fire []EmulatedOrder
+	for i, order := range changed {
+		updates[i] = *order
+		if order.State == EmulatedTriggering {
+			e.inFlight[order.ClientOrderId] = true
+			fire = append(fire, *order)
+		}
+	}
+	ctx := e.ctx
+	e.mu.Unlock()
+
+	for _, update := range updates {
+		e.notify(update)
+	}
+	for _, order := range fire {
+		e.fires.Add(1)
+		go e.fire(ctx, order, false)
+	}
+}
+
+// advance applies a price to a pending order. moved reports a change of
+// the trailing state; triggered that the stop condition is met.
+func (o *EmulatedOrder) advance(price t.Decimal) (moved, triggered bool) {
+	sell := o.Params.Side == t.OrderSideSell
+
+	if !o.IsTrailing() {
+		if sell {
+			return false, price.LessThanOrEqual(o.StopPrice)
+		}
+		return false, price.GreaterThanOrEqual(o.StopPrice)
+	}
+
+	if !o.Activated {
+		activation := o.Params.StopPrice
+		if activation.IsPositive() && (sell && price.LessThan(activation) || !sell && price.GreaterThan(activation)) {
+			return false, false
+		}
+		o.Activated = true
+		o.Extreme = price
+		moved = true
+	} else if sell && price.GreaterThan(o.Extreme) || !sell && price.LessThan(o.Extreme) {
+		o.Extreme = price
+		moved = true
+	}
+
+	if moved {
+		factor := 10000 - o.Params.TrailingDelta
+		if !sell {
+			factor = 10000 + o.Params.TrailingDelta
+		}
+		o.StopPrice = o.Extreme.Mul(t.NewDecimal(factor, 4)).Normalize()
+	}
+
+	if sell {
+		return moved, price.LessThanOrEqual(o.StopPrice)
+	}
+	return moved, price.GreaterThanOrEqual(o.StopPrice)
+}
+
+// fire places the real order of a TRIGGERING order, first looking it up
+// when an earlier attempt may have reached the exchange. The caller marks
+// the order in flight and adds it to e.fires.
+func (e *OrderEmulator) fire(ctx context.Context, order EmulatedOrder, lookup bool) error {
+	defer e.fires.Done()
+
+	params := order.OrderParams()
+
+	var response *t.CreateOrderResponse
+	var err error
+	uncertain := false
+	if lookup {
+		// A failed lookup leaves the earlier attempt's outcome open.
+		response, err = e.client.reconcileOrder(ctx, params)
+		uncertain = err != nil
+	}
+	if err == nil && response == nil {
+		response, err = e.client.CreateOrderCtx(ctx, params)
+		if uncertainPlacement(err) {
+			if existing, lookupErr := e.client.reconcileOrder(ctx, params); lookupErr == nil && existing != nil {
+				response, err = existing, nil
+			}
+		}
+	}
+
+	e.mu.Lock()
+	delete(e.inFlight, order.ClientOrderId)
+	current, ok := e.orders[order.ClientOrderId]
+	if !ok {
+		e.mu.Unlock()
+		return err
+	}
+
+	switch {
+	case err == nil:
+		current.State = EmulatedTriggered
+		current.ExchangeOrderId = response.OrderId
+		current.Error = ""
+	case uncertain || uncertainPlacement(err):
+		current.Error = err.Error()
+	default:
+		current.State = EmulatedFailed
+		current.Error = err.Error()
+	}
+	current.UpdatedAt = time.Now().UnixMilli()
+	saveErr := e.saveLocked()
+	update := *current
+	e.mu.Unlock()
+
+	e.notify(update)
+	if err != nil {
+		err = &GoTabdealError{Message: "placing emulated order " + order.ClientOrderId, Err: err}
+		e.reportError(err)
+	}
+	e.reportError(saveErr)
+	return err
+}
+
+// poll fetches book tickers for the symbols with pending orders and
+// retries unconfirmed placements every PollInterval until ctx is done.
+func (e *OrderEmulator) poll(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.mu.Lock()
+		symbols := make(map[string]t.BaseSymbolParams)
+		var retry []EmulatedOrder
+		for _, order := range e.orders {
+			switch {
+			case order.State == EmulatedPending:
+				symbols[emulatorSymbol(cmp.Or(order.Params.Symbol, order.Params.TabdealSymbol))] = order.Params.BaseSymbolParams
+			case order.State == EmulatedTriggering && !e.inFlight[order.ClientOrderId]:
+				e.inFlight[order.ClientOrderId] = true
+				retry = append(retry, *order)
+			}
+		}
+		e.mu.Unlock()
+
+		for _, order := range retry {
+			e.fires.Add(1)
+			go e.fire(ctx, order, true)
+		}
+
+		for key, symbol := range symbols {
+			ticker, err := e.client.GetBookTickerCtx(ctx, t.GetTickerParams{BaseSymbolParams: symbol})
+			if err != nil {
+				e.reportError(err)
+				continue
+			}
+			e.observe(key, ticker.BidPrice, ticker.AskPrice)
+		}
+	}
+}
+
+// status answers GetOrderStatus for emulated orders. It reports false when
+// params address no emulated order, or one that has been placed on the
+// exchange; in the latter case params are rewritten to the real order.
+func (e *OrderEmulator) status(params *t.GetOrderStatusParams) (*t.OrderStatusResponse, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var order *EmulatedOrder
+	if params.OrderId < 0 {
+		for _, candidate := range e.orders {
+			if candidate.OrderId == params.OrderId {
+				order = candidate
+				break
+			}
+		}
+	} else if params.OrderId == 0 && params.OrigClientOrderId != "" {
+		order = e.orders[params.OrigClientOrderId]
+	}
+	if order == nil {
+		return nil, false
+	}
+
+	// The real order of a triggered emulated order is the exchange's to
+	// report, unless dry-run mode withheld it.
+	if order.State == EmulatedTriggered && order.ExchangeOrderId > 0 {
+		params.OrderId = order.ExchangeOrderId
+		params.OrigClientOrderId = ""
+		return nil, false
+	}
+
+	status := order.OrderStatus()
+	return &status, true
+}
+
+func (e *OrderEmulator) sortedLocked() []EmulatedOrder {
+	orders := make([]EmulatedOrder, 0, len(e.orders))
+	for _, order := range e.orders {
+		orders = append(orders, *order)
+	}
+	slices.SortFunc(orders, func(a, b EmulatedOrder) int {
+		return cmp.Or(cmp.Compare(a.CreatedAt, b.CreatedAt), cmp.Compare(b.OrderId, a.OrderId))
+	})
+	return orders
+}
+
+func (e *OrderEmulator) saveLocked() error {
+	if e.opts.Store == nil {
+		return nil
+	}
+	if err := e.opts.Store.Save(e.sortedLocked()); err != nil {
+		return err
+	}
+	e.dirty = false
+	return nil
+}
+
+// scheduleSaveLocked marks the orders dirty and saves them after
+// SaveDelay, unless a save is already scheduled.
+func (e *OrderEmulator) scheduleSaveLocked() {
+	if e.opts.Store == nil {
+		return
+	}
+	e.dirty = true
+	if e.saveTimer == nil {
+		e.saveTimer = time.AfterFunc(e.opts.SaveDelay, e.flush)
+	}
+}
+
+// flush saves the orders if they changed since the last save.
+func (e *OrderEmulator) flush() {
+	e.mu.Lock()
+	if e.saveTimer != nil {
+		e.saveTimer.Stop()
+		e.saveTimer = nil
+	}
+	var err error
+	if e.dirty {
+		err = e.saveLocked()
+	}
+	e.mu.Unlock()
+
+	e.reportError(err)
+}
+
+func (e *OrderEmulator) notify(order EmulatedOrder) {
+	if e.opts.OnUpdate != nil {
+		e.opts.OnUpdate(order)
+	}
+}
+
+func (e *OrderEmulator) reportError(err error) {
+	if e.opts.OnError != nil && err != nil {
+		e.opts.OnError(err)
+	}
+}
+
+// uncertainPlacement reports whether a CreateOrder error leaves open
+// whether the order reached the exchange.
+func uncertainPlacement(err error) bool {
+	var uncertainErr *OrderUncertainError
+	return errors.As(err, &uncertainErr) || isTransient(err) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// emulatorSymbol normalizes "BTC_IRT" and "btcirt" to "BTCIRT".
+func emulatorSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "_", ""))
+}
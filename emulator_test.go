@@ -0,0 +1,335 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// orderServer places orders and answers order lookups by client order id
+// or order id the way the exchange does, and counts placements.
+type orderServer struct {
+	mu      sync.Mutex
+	orders  map[string]int64 // client order id → order id
+	created []string         // client order ids, in placement order
+}
+
+func (s *orderServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.orders == nil {
+		s.orders = make(map[string]int64)
+	}
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/order"):
+		clientOrderId := query.Get("newClientOrderId")
+		s.created = append(s.created, clientOrderId)
+		s.orders[clientOrderId] = int64(100 + len(s.created))
+		_ = json.NewEncoder(w).Encode(t.BaseOrderResponse{OrderId: s.orders[clientOrderId], ClientOrderId: clientOrderId, Status: t.OrderStatusNew})
+
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/order"):
+		orderId, _ := strconv.ParseInt(query.Get("orderId"), 10, 64)
+		for clientOrderId, id := range s.orders {
+			if clientOrderId == query.Get("origClientOrderId") || id == orderId {
+				_ = json.NewEncoder(w).Encode(t.BaseOrderResponse{OrderId: id, ClientOrderId: clientOrderId, Status: t.OrderStatusFilled})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *orderServer) placed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.created...)
+}
+
+// memoryStore is an EmulatedOrderStore that keeps the last save and
+// counts saves.
+type memoryStore struct {
+	mu     sync.Mutex
+	orders []EmulatedOrder
+	saves  int
+}
+
+func (s *memoryStore) Load() ([]EmulatedOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]EmulatedOrder(nil), s.orders...), nil
+}
+
+func (s *memoryStore) Save(orders []EmulatedOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders = append([]EmulatedOrder(nil), orders...)
+	s.saves++
+	return nil
+}
+
+func (s *memoryStore) saved() ([]EmulatedOrder, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]EmulatedOrder(nil), s.orders...), s.saves
+}
+
+func newEmulator(tt *testing.T, exchange *orderServer, opts OrderEmulatorOptions) (*Client, *OrderEmulator) {
+	tt.Helper()
+
+	srv := httptest.NewServer(exchange)
+	tt.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	emulator, err := client.NewOrderEmulator(opts)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	tt.Cleanup(emulator.Close)
+	return client, emulator
+}
+
+func TestEmulatorTriggers(tt *testing.T) {
+	tests := []struct {
+		name      string
+		params    EmulatedOrderParams
+		prices    []string
+		triggered string // the price expected to trigger, after all earlier ones did not
+	}{
+		{
+			name:      "sell stop",
+			params:    EmulatedOrderParams{Side: t.OrderSideSell, StopPrice: t.MustParseDecimal("95")},
+			prices:    []string{"100", "96"},
+			triggered: "95",
+		},
+		{
+			name:      "buy stop",
+			params:    EmulatedOrderParams{Side: t.OrderSideBuy, StopPrice: t.MustParseDecimal("105")},
+			prices:    []string{"100", "104.99"},
+			triggered: "106",
+		},
+		{
+			name:      "sell trailing stop follows the high",
+			params:    EmulatedOrderParams{Side: t.OrderSideSell, TrailingDelta: 1000},
+			prices:    []string{"100", "120", "110", "108.01"},
+			triggered: "108",
+		},
+		{
+			name:      "buy trailing stop waits for its activation price",
+			params:    EmulatedOrderParams{Side: t.OrderSideBuy, TrailingDelta: 500, StopPrice: t.MustParseDecimal("90")},
+			prices:    []string{"100", "95", "90", "80", "83.99"},
+			triggered: "84",
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			exchange := &orderServer{}
+			_, emulator := newEmulator(tt, exchange, OrderEmulatorOptions{})
+
+			params := tc.params
+			params.Symbol = "BTCIRT"
+			params.Quantity = t.MustParseDecimal("1")
+			params.ClientOrderId = "stop-1"
+			if _, err := emulator.Place(context.Background(), params); err != nil {
+				tt.Fatal(err)
+			}
+
+			for _, price := range tc.prices {
+				emulator.OnTrade("BTC_IRT", t.MustParseDecimal(price))
+			}
+			if order, _ := emulator.Get("stop-1"); order.State != EmulatedPending {
+				tt.Fatalf("state = %s before the trigger price", order.State)
+			}
+
+			emulator.OnTrade("BTC_IRT", t.MustParseDecimal(tc.triggered))
+			emulator.Close()
+
+			order, _ := emulator.Get("stop-1")
+			if order.State != EmulatedTriggered || order.ExchangeOrderId != 101 || order.TriggeredPrice.String() != tc.triggered {
+				tt.Errorf("order = %s, exchange id %d, triggered at %s", order.State, order.ExchangeOrderId, order.TriggeredPrice)
+			}
+			if placed := exchange.placed(); len(placed) != 1 || placed[0] != "stop-1" {
+				tt.Errorf("placed %v, want [stop-1]", placed)
+			}
+		})
+	}
+}
+
+func TestEmulatorFiresOnce(tt *testing.T) {
+	exchange := &orderServer{}
+	_, emulator := newEmulator(tt, exchange, OrderEmulatorOptions{Store: &memoryStore{}})
+
+	_, err := emulator.Place(context.Background(), EmulatedOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideSell,
+		Quantity:         t.MustParseDecimal("1"),
+		StopPrice:        t.MustParseDecimal("95"),
+		ClientOrderId:    "stop-1",
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			emulator.OnQuote("BTCIRT", t.NewDecimal(int64(90+i%5), 0), t.NewDecimal(100, 0))
+		}()
+	}
+	wg.Wait()
+	emulator.Close()
+
+	if placed := exchange.placed(); len(placed) != 1 {
+		tt.Errorf("placed %d orders, want 1", len(placed))
+	}
+	if order, _ := emulator.Get("stop-1"); order.State != EmulatedTriggered {
+		tt.Errorf("state = %s, want TRIGGERED", order.State)
+	}
+}
+
+func TestEmulatorStartReconcilesTriggeringOrders(tt *testing.T) {
+	triggering := func(clientOrderId string, orderId int64) EmulatedOrder {
+		return EmulatedOrder{
+			OrderId:       orderId,
+			ClientOrderId: clientOrderId,
+			Params: EmulatedOrderParams{
+				BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+				Side:             t.OrderSideSell,
+				Quantity:         t.MustParseDecimal("1"),
+				StopPrice:        t.MustParseDecimal("95"),
+			},
+			State:     EmulatedTriggering,
+			StopPrice: t.MustParseDecimal("95"),
+		}
+	}
+
+	// "reached" was placed before the restart; "lost" never arrived.
+	exchange := &orderServer{orders: map[string]int64{"reached": 7}}
+	store := &memoryStore{orders: []EmulatedOrder{triggering("reached", -1), triggering("lost", -2)}}
+	_, emulator := newEmulator(tt, exchange, OrderEmulatorOptions{Store: store})
+
+	if err := emulator.Start(context.Background()); err != nil {
+		tt.Fatal(err)
+	}
+
+	for _, want := range []struct {
+		clientOrderId string
+		orderId       int64
+	}{{"reached", 7}, {"lost", 101}} {
+		order, _ := emulator.Get(want.clientOrderId)
+		if order.State != EmulatedTriggered || order.ExchangeOrderId != want.orderId {
+			tt.Errorf("%s: state %s, exchange id %d, want TRIGGERED as %d", want.clientOrderId, order.State, order.ExchangeOrderId, want.orderId)
+		}
+	}
+	if placed := exchange.placed(); len(placed) != 1 || placed[0] != "lost" {
+		tt.Errorf("placed %v, want only the lost order", placed)
+	}
+
+	saved, _ := store.saved()
+	for _, order := range saved {
+		if order.State != EmulatedTriggered {
+			tt.Errorf("%s saved as %s", order.ClientOrderId, order.State)
+		}
+	}
+}
+
+func TestEmulatorOrderStatus(tt *testing.T) {
+	exchange := &orderServer{}
+	client, emulator := newEmulator(tt, exchange, OrderEmulatorOptions{})
+
+	placed, err := emulator.Place(context.Background(), EmulatedOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideSell,
+		Quantity:         t.MustParseDecimal("1"),
+		StopPrice:        t.MustParseDecimal("95"),
+		LimitPrice:       t.MustParseDecimal("94"),
+		ClientOrderId:    "stop-1",
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if placed.OrderId >= 0 {
+		tt.Fatalf("OrderId = %d, want a negative id", placed.OrderId)
+	}
+
+	lookup := t.GetOrderStatusParams{BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"}, OrderId: placed.OrderId}
+	status, err := client.GetOrderStatus(lookup)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if status.OrderId != placed.OrderId || status.Type != t.OrderTypeStopLossLimit || status.Status != t.OrderStatusNew ||
+		status.StopPrice.String() != "95" || status.Price.String() != "94" || status.IsStopOrderTriggered {
+		tt.Errorf("pending status = %+v", status.BaseOrderResponse)
+	}
+
+	// Once placed, the exchange reports the real order.
+	emulator.OnTrade("BTCIRT", t.MustParseDecimal("95"))
+	emulator.Close()
+
+	status, err = client.GetOrderStatus(lookup)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if status.OrderId != 101 || status.Status != t.OrderStatusFilled {
+		tt.Errorf("triggered status = order %d %s, want the exchange's order 101", status.OrderId, status.Status)
+	}
+
+	if _, err := emulator.Cancel("stop-1"); err == nil {
+		tt.Error("Cancel() succeeded on a triggered order")
+	}
+}
+
+func TestEmulatorBatchesTrailingSaves(tt *testing.T) {
+	store := &memoryStore{}
+	_, emulator := newEmulator(tt, &orderServer{}, OrderEmulatorOptions{Store: store, SaveDelay: time.Hour})
+
+	_, err := emulator.Place(context.Background(), EmulatedOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideSell,
+		Quantity:         t.MustParseDecimal("1"),
+		TrailingDelta:    100,
+		ClientOrderId:    "trail-1",
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	// A trending market moves the extreme on every price.
+	for price := int64(100); price < 200; price++ {
+		emulator.OnTrade("BTCIRT", t.NewDecimal(price, 0))
+	}
+	if _, saves := store.saved(); saves != 1 {
+		tt.Errorf("%d saves while trailing, want only the one of Place", saves)
+	}
+
+	emulator.Close()
+	saved, saves := store.saved()
+	if saves != 2 || len(saved) != 1 || saved[0].Extreme.String() != "199" {
+		tt.Errorf("after Close: %d saves, orders %+v, want the last extreme saved once", saves, saved)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	t "github.com/darhelm/go-tabdeal/types"
 )
@@ -28,6 +30,58 @@ type RequestError struct {
 	Operation string
 }
 
+// RateLimitError is returned when the client-side rate limiter refuses a
+// request: immediately in RateLimitFailFast mode, or when the context ends
+// while waiting for budget in RateLimitBlock mode.
+type RateLimitError struct {
+	GoTabdealError
+
+	// Bucket is "public" or "signed".
+	Bucket string
+
+	// RetryAfter is the estimated wait until the request would fit.
+	RetryAfter time.Duration
+}
+
+// OrderValidationError is returned by CreateOrder when client-side order
+// validation is enabled and the order breaks one or more market rules.
+// No request is sent in that case.
+type OrderValidationError struct {
+	GoTabdealError
+
+	// Violations lists every rule the order breaks.
+	Violations []OrderViolation
+}
+
+func newOrderValidationError(violations []OrderViolation) *OrderValidationError {
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+
+	return &OrderValidationError{
+		GoTabdealError: GoTabdealError{
+			Message: "order rejected by client-side validation: " + strings.Join(messages, "; "),
+		},
+		Violations: violations,
+	}
+}
+
+// OrderUncertainError is returned by CreateOrder when an attempt failed in
+// a way that may have left the order placed, and looking it up by its
+// client order id failed too. The order was not sent again; check it with
+// GetOrderStatus before placing it anew.
+type OrderUncertainError struct {
+	GoTabdealError
+
+	// ClientOrderId is the NewClientOrderId of the order in question.
+	ClientOrderId string
+}
+
+// CodeOrderDoesNotExist is the APIError.Code Tabdeal returns when a
+// queried order is unknown.
+const CodeOrderDoesNotExist int16 = -2013
+
 // APIError represents an error response returned by Tabdeal's REST API.
 // Tabdeal does not enforce a uniform error schema across endpoints, but
 // error payloads commonly include the following fields:
@@ -47,6 +101,10 @@ type APIError struct {
 	Detail     string
 	StatusCode int
 
+	// RetryAfter is the wait requested by the server through the
+	// Retry-After header, typically on HTTP 429. Zero when absent.
+	RetryAfter time.Duration
+
 	// Fields collects all key–value pairs extracted from the error payload,
 	// including fields not explicitly modeled in this struct.
 	Fields map[string][]string
@@ -0,0 +1,464 @@
+// Package websocket is a minimal RFC 6455 implementation used by the
+// streaming client and the streamtest stand-in server.
+//
+// It supports the client and server handshakes, masked and unmasked
+// frames, fragmented messages, ping/pong and the closing handshake. It
+// does not implement extensions such as permessage-deflate.
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message opcodes.
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// Close codes used by this package.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseMessageTooLarge = 1009
+
+	// CloseNoStatus is the CloseError code of a close frame that carried
+	// no status. It is never sent.
+	CloseNoStatus = 1005
+)
+
+// MaxMessageSize caps the size of a single (reassembled) message.
+const MaxMessageSize = 16 << 20
+
+// acceptGUID is the fixed GUID of the opening handshake (RFC 6455 §1.3).
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// CloseError is returned by ReadMessage when the peer sent a close frame.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("websocket closed (%d): %s", e.Code, e.Reason)
+	}
+	return fmt.Sprintf("websocket closed (%d)", e.Code)
+}
+
+// Conn is a WebSocket connection. ReadMessage must be called from a single
+// goroutine; writes are safe for concurrent use.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	// PongHandler, when set, is called from ReadMessage for every pong.
+	PongHandler func(payload []byte)
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// Dial opens a client connection to a ws:// or wss:// URL.
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	case "wss":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	default:
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+
+	var nc net.Conn
+	if u.Scheme == "wss" {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: u.Hostname()}}
+		nc, err = dialer.DialContext(ctx, "tcp", host)
+	} else {
+		var dialer net.Dialer
+		nc, err = dialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = nc.SetDeadline(deadline)
+	}
+
+	conn, err := clientHandshake(nc, u, header)
+	if err != nil {
+		_ = nc.Close()
+		return nil, err
+	}
+
+	_ = nc.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func clientHandshake(nc net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.EscapedPath(), RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	if req.URL.Path == "" {
+		req.URL.Path = "/"
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(nc); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket: handshake failed with status %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("websocket: invalid handshake response")
+	}
+
+	return &Conn{conn: nc, br: br, client: true}, nil
+}
+
+// Accept upgrades an HTTP request to a server-side connection.
+func Accept(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: missing Sec-WebSocket-Key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+
+	nc, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := nc.Write([]byte(response)); err != nil {
+		_ = nc.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: nc, br: rw.Reader}, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetReadDeadline sets the deadline for future ReadMessage calls.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// RemoteAddr returns the address of the peer.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// WriteMessage sends a single unfragmented frame. Client frames are masked.
+func (c *Conn) WriteMessage(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	return c.writeFrame(opcode, payload)
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | byte(opcode)
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	frame := payload
+	if c.client {
+		header[1] |= 0x80
+		mask := make([]byte, 4)
+		if _, err := rand.Read(mask); err != nil {
+			return err
+		}
+		header = append(header, mask...)
+
+		frame = make([]byte, length)
+		for i := range payload {
+			frame[i] = payload[i] ^ mask[i%4]
+		}
+	}
+
+	if _, err := c.conn.Write(append(header, frame...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// automatically, pongs are passed to PongHandler, and a close frame is
+// acknowledged and returned as *CloseError.
+func (c *Conn) ReadMessage() (opcode int, payload []byte, err error) {
+	var message []byte
+	messageOp := -1
+
+	for {
+		fin, op, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, data); err != nil {
+				return 0, nil, err
+			}
+			continue
+
+		case OpPong:
+			if c.PongHandler != nil {
+				c.PongHandler(data)
+			}
+			continue
+
+		case OpClose:
+			// A close frame without a status is answered with an empty
+			// one: 1005 only reports the missing status locally and must
+			// never be sent (RFC 6455 §7.4.1).
+			closeErr := &CloseError{Code: CloseNoStatus}
+			if len(data) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(data))
+				closeErr.Reason = string(data[2:])
+				_ = c.CloseWithCode(closeErr.Code, "")
+			} else {
+				_ = c.closeWith(nil)
+			}
+			return 0, nil, closeErr
+
+		case OpContinuation:
+			if messageOp < 0 {
+				return 0, nil, c.fail("unexpected continuation frame")
+			}
+
+		case OpText, OpBinary:
+			if messageOp >= 0 {
+				return 0, nil, c.fail("expected continuation frame")
+			}
+			messageOp = op
+
+		default:
+			return 0, nil, c.fail(fmt.Sprintf("unknown opcode %d", op))
+		}
+
+		if len(message)+len(data) > MaxMessageSize {
+			_ = c.CloseWithCode(CloseMessageTooLarge, "message too large")
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		message = append(message, data...)
+
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0F)
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, c.fail("reserved bits set")
+	}
+
+	masked := head[1]&0x80 != 0
+	if masked == c.client {
+		return false, 0, nil, c.fail("invalid frame masking")
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if opcode >= OpClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail("invalid control frame")
+	}
+	if length > MaxMessageSize {
+		_ = c.CloseWithCode(CloseMessageTooLarge, "message too large")
+		return false, 0, nil, errors.New("websocket: message too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *Conn) fail(reason string) error {
+	_ = c.CloseWithCode(CloseProtocolError, reason)
+	return errors.New("websocket: " + reason)
+}
+
+// CloseWithCode sends a close frame and closes the underlying connection.
+// It is safe to call more than once.
+func (c *Conn) CloseWithCode(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return c.closeWith(append(payload, reason...))
+}
+
+// closeWith sends a close frame with payload, which may be empty, and
+// closes the underlying connection.
+func (c *Conn) closeWith(payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = c.writeFrame(OpClose, payload)
+
+	return c.conn.Close()
+}
+
+// Close closes the connection with a normal closure.
+func (c *Conn) Close() error {
+	return c.CloseWithCode(CloseNormal, "")
+}
+
+// Abort closes the underlying connection without a closing handshake,
+// simulating a dropped connection.
+func (c *Conn) Abort() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.closed = true
+	return c.conn.Close()
+}
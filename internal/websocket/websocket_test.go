@@ -0,0 +1,352 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pair returns the two ends of a loopback TCP connection wrapped as a
+// client and a server Conn. Unlike net.Pipe, writes are buffered by the
+// kernel, so one side can send several frames before the other reads.
+func pair(t *testing.T) (client, server *Conn) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		nc, _ := ln.Accept()
+		accepted <- nc
+	}()
+
+	cc, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := <-accepted
+	if sc == nil {
+		t.Fatal("accept failed")
+	}
+
+	t.Cleanup(func() {
+		_ = cc.Close()
+		_ = sc.Close()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	_ = cc.SetDeadline(deadline)
+	_ = sc.SetDeadline(deadline)
+
+	return &Conn{conn: cc, br: bufio.NewReader(cc), client: true},
+		&Conn{conn: sc, br: bufio.NewReader(sc)}
+}
+
+// writeRaw writes frame bytes to c's socket as is.
+func writeRaw(t *testing.T, c *Conn, frames ...[]byte) {
+	t.Helper()
+
+	for _, frame := range frames {
+		if _, err := c.conn.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	// Frames from the examples of RFC 6455 §5.7.
+	tests := []struct {
+		name   string
+		toward string // "client" or "server"
+		frames [][]byte
+		op     int
+		want   string
+	}{
+		{
+			name:   "unmasked text",
+			toward: "client",
+			frames: [][]byte{{0x81, 0x05, 'H', 'e', 'l', 'l', 'o'}},
+			op:     OpText,
+			want:   "Hello",
+		},
+		{
+			name:   "masked text",
+			toward: "server",
+			frames: [][]byte{{0x81, 0x85, 0x37, 0xfa, 0x21, 0x3d, 0x7f, 0x9f, 0x4d, 0x51, 0x58}},
+			op:     OpText,
+			want:   "Hello",
+		},
+		{
+			name:   "fragmented text",
+			toward: "client",
+			frames: [][]byte{{0x01, 0x03, 'H', 'e', 'l'}, {0x80, 0x02, 'l', 'o'}},
+			op:     OpText,
+			want:   "Hello",
+		},
+		{
+			name:   "fragmented masked binary",
+			toward: "server",
+			frames: [][]byte{
+				{0x02, 0x82, 0x01, 0x02, 0x03, 0x04, 'a' ^ 0x01, 'b' ^ 0x02},
+				{0x00, 0x81, 0x10, 0x20, 0x30, 0x40, 'c' ^ 0x10},
+				{0x80, 0x81, 0x00, 0x00, 0x00, 0x00, 'd'},
+			},
+			op:   OpBinary,
+			want: "abcd",
+		},
+		{
+			name:   "16-bit length",
+			toward: "client",
+			frames: [][]byte{append([]byte{0x82, 126, 0x01, 0x00}, bytes.Repeat([]byte{'x'}, 256)...)},
+			op:     OpBinary,
+			want:   strings.Repeat("x", 256),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := pair(t)
+			reader, writer := client, server
+			if tt.toward == "server" {
+				reader, writer = server, client
+			}
+
+			writeRaw(t, writer, tt.frames...)
+
+			op, payload, err := reader.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if op != tt.op || string(payload) != tt.want {
+				t.Errorf("ReadMessage() = %d %q, want %d %q", op, payload, tt.op, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMessageProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		toward string
+		frame  []byte
+		want   string
+	}{
+		{"unmasked frame to server", "server", []byte{0x81, 0x01, 'a'}, "invalid frame masking"},
+		{"masked frame to client", "client", []byte{0x81, 0x81, 0, 0, 0, 0, 'a'}, "invalid frame masking"},
+		{"reserved bits", "client", []byte{0xC1, 0x01, 'a'}, "reserved bits set"},
+		{"fragmented ping", "client", []byte{0x09, 0x00}, "invalid control frame"},
+		{"stray continuation", "client", []byte{0x80, 0x01, 'a'}, "unexpected continuation frame"},
+		{"unknown opcode", "client", []byte{0x83, 0x00}, "unknown opcode 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := pair(t)
+			reader, writer := client, server
+			if tt.toward == "server" {
+				reader, writer = server, client
+			}
+
+			writeRaw(t, writer, tt.frame)
+
+			_, _, err := reader.ReadMessage()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ReadMessage() error = %v, want %q", err, tt.want)
+			}
+
+			// The peer is told why with a 1002 close frame.
+			_, op, payload, err := writer.readFrame()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if op != OpClose || len(payload) < 2 || int(payload[0])<<8|int(payload[1]) != CloseProtocolError {
+				t.Errorf("peer got opcode %d payload %v, want a %d close", op, payload, CloseProtocolError)
+			}
+		})
+	}
+}
+
+func TestWriteMessageMasking(t *testing.T) {
+	client, server := pair(t)
+
+	if err := client.WriteMessage(OpText, []byte("Hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	var head [11]byte
+	if _, err := io.ReadFull(server.br, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	if head[0] != 0x81 || head[1] != 0x85 {
+		t.Fatalf("header = % x, want 81 85", head[:2])
+	}
+	mask, body := head[2:6], head[6:]
+	for i := range body {
+		body[i] ^= mask[i%4]
+	}
+	if string(body) != "Hello" {
+		t.Errorf("unmasked payload = %q", body)
+	}
+
+	if err := server.WriteMessage(OpText, []byte("Hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(client.br, head[:7]); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(head[:7]); got != "\x81\x05Hello" {
+		t.Errorf("server frame = %q, want it unmasked", got)
+	}
+}
+
+func TestPingPong(t *testing.T) {
+	client, server := pair(t)
+
+	var pongs []string
+	client.PongHandler = func(payload []byte) { pongs = append(pongs, string(payload)) }
+
+	// A ping between two fragments is answered without breaking up the
+	// message, and pongs go to PongHandler.
+	writeRaw(t, server,
+		[]byte{0x01, 0x02, 'a', 'b'},
+		[]byte{0x89, 0x04, 'p', 'i', 'n', 'g'},
+		[]byte{0x8A, 0x02, 'h', 'i'},
+		[]byte{0x80, 0x01, 'c'},
+	)
+
+	op, payload, err := client.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op != OpText || string(payload) != "abc" {
+		t.Errorf("ReadMessage() = %d %q, want text \"abc\"", op, payload)
+	}
+	if len(pongs) != 1 || pongs[0] != "hi" {
+		t.Errorf("PongHandler got %q, want [hi]", pongs)
+	}
+
+	fin, op, payload, err := server.readFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fin || op != OpPong || string(payload) != "ping" {
+		t.Errorf("reply = fin %v opcode %d %q, want a pong echoing \"ping\"", fin, op, payload)
+	}
+}
+
+func TestCloseHandshake(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     []byte
+		wantCode  int
+		wantReply []byte
+	}{
+		{
+			name:      "no status",
+			frame:     []byte{0x88, 0x00},
+			wantCode:  CloseNoStatus,
+			wantReply: []byte{},
+		},
+		{
+			name:      "status and reason",
+			frame:     []byte{0x88, 0x05, 0x03, 0xE9, 'b', 'y', 'e'},
+			wantCode:  CloseGoingAway,
+			wantReply: []byte{0x03, 0xE9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := pair(t)
+
+			writeRaw(t, server, tt.frame)
+
+			_, _, err := client.ReadMessage()
+			var closeErr *CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != tt.wantCode {
+				t.Fatalf("ReadMessage() error = %v, want close code %d", err, tt.wantCode)
+			}
+
+			_, op, payload, err := server.readFrame()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if op != OpClose || !bytes.Equal(payload, tt.wantReply) {
+				t.Errorf("reply = opcode %d payload % x, want close % x", op, payload, tt.wantReply)
+			}
+
+			if err := client.WriteMessage(OpText, nil); !errors.Is(err, net.ErrClosed) {
+				t.Errorf("WriteMessage() after close = %v, want net.ErrClosed", err)
+			}
+		})
+	}
+}
+
+func TestDialAccept(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Accept(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		received <- r.URL.RequestURI() + " " + string(payload)
+		_ = conn.WriteMessage(OpText, payload)
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/stream?x=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteMessage(OpText, []byte("echo")); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; got != "/stream?x=1 echo" {
+		t.Errorf("server received %q", got)
+	}
+
+	_, payload, err := conn.ReadMessage()
+	if err != nil || string(payload) != "echo" {
+		t.Errorf("ReadMessage() = %q, %v", payload, err)
+	}
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("plain GET status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// RFC 6455 §1.3.
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("acceptKey() = %s", got)
+	}
+}
@@ -0,0 +1,338 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"strconv"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+const (
+	// historyPageSize is the largest page the history endpoints return.
+	historyPageSize = 1000
+
+	// historyWindow is the widest startTime–endTime span the history
+	// endpoints accept in one request.
+	historyWindow = 24 * time.Hour
+)
+
+// IterUserTrades returns an iterator over every trade of the authenticated
+// user matching params, oldest first, fetching pages on demand.
+//
+// Paging:
+//   - With StartTime set (and no FromId), the range StartTime–EndTime
+//     (default now) is searched in windows of at most 24 hours. Once a
+//     window returns a full page, trades are walked by id from there.
+//   - Otherwise trades are walked by id from FromId (default 0, the first
+//     trade), stopping after EndTime when it is set.
+//   - params.Limit sets the page size, at most 1000 (the default).
+//
+// Behavior:
+//   - Every trade is yielded once, however many share a millisecond.
+//   - Every page goes through the client's retry policy and rate limiter.
+//   - Iteration stops after yielding the first error, including ctx
+//     errors, and whenever the loop body breaks.
+//
+// Example:
+//
+//	params := t.GetUserTradesParams{}
+//	params.Symbol = "BTCIRT"
+//	params.StartTime = time.Now().AddDate(-1, 0, 0).UnixMilli()
+//
+//	for trade, err := range client.IterUserTrades(ctx, params) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(trade.Id, trade.Price, trade.Qty)
+//	}
+func (c *Client) IterUserTrades(ctx context.Context, params t.GetUserTradesParams) iter.Seq2[*t.UserTradeResponse, error] {
+	params.Limit = historyLimit(params.Limit)
+
+	fetch := func(p t.GetUserTradesParams) ([]*t.UserTradeResponse, error) {
+		page, err := c.GetUserTradesCtx(ctx, p)
+		trades := derefPage(page)
+		slices.SortFunc(trades, func(a, b *t.UserTradeResponse) int { return cmp.Compare(a.Id, b.Id) })
+		return trades, err
+	}
+
+	// walkIds yields the trades from id from up to end, if set. It
+	// reports whether iteration should go on.
+	walkIds := func(yield func(*t.UserTradeResponse, error) bool, from, end int64) bool {
+		p := params
+		p.StartTime, p.EndTime = 0, 0
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return false
+			}
+
+			p.FromId = &from
+			trades, err := fetch(p)
+			if err != nil {
+				yield(nil, err)
+				return false
+			}
+
+			for _, trade := range trades {
+				if trade.Id < from {
+					continue
+				}
+				if end > 0 && trade.Time > end {
+					return false
+				}
+				if !yield(trade, nil) {
+					return false
+				}
+				from = trade.Id + 1
+			}
+
+			if int64(len(trades)) < p.Limit {
+				return true
+			}
+		}
+	}
+
+	if params.StartTime <= 0 || params.FromId != nil {
+		return func(yield func(*t.UserTradeResponse, error) bool) {
+			walkIds(yield, derefId(params.FromId), params.EndTime)
+		}
+	}
+
+	return func(yield func(*t.UserTradeResponse, error) bool) {
+		end := params.EndTime
+		if end <= 0 {
+			end = time.Now().UnixMilli()
+		}
+
+		for start := params.StartTime; start <= end; {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			windowEnd := min(start+historyWindow.Milliseconds()-1, end)
+
+			p := params
+			p.StartTime, p.EndTime = start, windowEnd
+			trades, err := fetch(p)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, trade := range trades {
+				if !yield(trade, nil) {
+					return
+				}
+			}
+
+			// A full page may stop in the middle of a millisecond; trade
+			// ids keep the rest of the range in order.
+			if int64(len(trades)) == p.Limit {
+				walkIds(yield, trades[len(trades)-1].Id+1, end)
+				return
+			}
+			start = windowEnd + 1
+		}
+	}
+}
+
+// IterOrdersHistory returns an iterator over every order of the
+// authenticated user placed between params.StartTime and params.EndTime
+// (default now), oldest first.
+//
+// The range is walked in windows of at most 24 hours; full pages continue
+// from the time of their last order, and orders repeated across page
+// boundaries are yielded once. StartTime is required. Errors and early
+// termination behave as in IterUserTrades; a full page of orders placed
+// in the same millisecond ends iteration with an error.
+//
+// Example:
+//
+//	params := t.GetUserOrdersHistoryParams{}
+//	params.Symbol = "BTCIRT"
+//	params.StartTime = time.Now().AddDate(0, -3, 0).UnixMilli()
+//
+//	for order, err := range client.IterOrdersHistory(ctx, params) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(order.OrderId, order.Status)
+//	}
+func (c *Client) IterOrdersHistory(ctx context.Context, params t.GetUserOrdersHistoryParams) iter.Seq2[*t.BaseOrderResponse, error] {
+	if params.StartTime <= 0 {
+		return func(yield func(*t.BaseOrderResponse, error) bool) {
+			yield(nil, &GoTabdealError{Message: "IterOrdersHistory requires a StartTime"})
+		}
+	}
+
+	params.Limit = historyLimit(params.Limit)
+
+	fetch := func(start, end int64) ([]*t.BaseOrderResponse, error) {
+		p := params
+		p.StartTime, p.EndTime = start, end
+		page, err := c.GetOrdersHistoryCtx(ctx, p)
+		return derefPage(page), err
+	}
+	key := func(order *t.BaseOrderResponse) (int64, int64) {
+		return order.OrderId, cmp.Or(order.Time, order.TransactTime, order.UpdateTime)
+	}
+
+	return walkTimeWindows(ctx, params.StartTime, params.EndTime, params.Limit, fetch, key)
+}
+
+// IterRecentTrades returns an endless iterator that tails the public trades
+// of a market by polling GetRecentTrades every interval (default one
+// second). The first poll yields the current page; later polls yield only
+// trades with a higher id than any seen before, oldest first.
+//
+// Trades that scroll out of the recent-trades window between two polls are
+// missed; lower the interval or raise params.Limit on busy markets.
+// Iteration ends on the first error, when ctx is done, or when the loop
+// body breaks.
+//
+// Example:
+//
+//	for trade, err := range client.IterRecentTrades(ctx, params, 500*time.Millisecond) {
+//	    if err != nil {
+//	        break
+//	    }
+//	    fmt.Println(trade.Id, trade.Price)
+//	}
+func (c *Client) IterRecentTrades(ctx context.Context, params t.GetRecentTradesParams, interval time.Duration) iter.Seq2[*t.Trade, error] {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return func(yield func(*t.Trade, error) bool) {
+		lastId := int64(-1)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			page, err := c.GetRecentTradesCtx(ctx, params)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			trades := derefPage(page)
+			slices.SortFunc(trades, func(a, b *t.Trade) int { return cmp.Compare(a.Id, b.Id) })
+
+			for _, trade := range trades {
+				if trade.Id <= lastId {
+					continue
+				}
+				if !yield(trade, nil) {
+					return
+				}
+				lastId = trade.Id
+			}
+
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// walkTimeWindows iterates over records between start and end (default
+// now), fetching 24-hour windows and following full pages from the time
+// of their last record. key returns a record's id and time; records whose
+// id appeared on the previous page are skipped. A full page whose records
+// all share one millisecond ends the walk with an error, since the rest
+// of that millisecond cannot be reached by time.
+func walkTimeWindows[T any](ctx context.Context, start, end, limit int64, fetch func(start, end int64) ([]*T, error), key func(*T) (id, at int64)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		if end <= 0 {
+			end = time.Now().UnixMilli()
+		}
+
+		var seen map[int64]struct{}
+
+		for start <= end {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			windowEnd := min(start+historyWindow.Milliseconds()-1, end)
+
+			records, err := fetch(start, windowEnd)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			slices.SortStableFunc(records, func(a, b *T) int {
+				_, ta := key(a)
+				_, tb := key(b)
+				return cmp.Compare(ta, tb)
+			})
+
+			page := make(map[int64]struct{}, len(records))
+			for _, record := range records {
+				id, _ := key(record)
+				page[id] = struct{}{}
+				if _, dup := seen[id]; dup {
+					continue
+				}
+				if !yield(record, nil) {
+					return
+				}
+			}
+			seen = page
+
+			if int64(len(records)) < limit {
+				start = windowEnd + 1
+				continue
+			}
+
+			// A full page may be cut in the middle of a millisecond, so the
+			// next page starts at the last record's time; seen drops the
+			// overlap. A page filled by one millisecond cannot be followed
+			// by time at all.
+			_, last := key(records[len(records)-1])
+			if last <= start {
+				yield(nil, &GoTabdealError{Message: "more than " + strconv.FormatInt(limit, 10) +
+					" records at " + time.UnixMilli(start).UTC().Format(time.RFC3339Nano) + "; raise the page limit"})
+				return
+			}
+			start = last
+		}
+	}
+}
+
+// historyLimit clamps a page size to the endpoints' maximum.
+func historyLimit(limit int64) int64 {
+	if limit <= 0 || limit > historyPageSize {
+		return historyPageSize
+	}
+	return limit
+}
+
+// derefId returns *id, or 0 when id is nil.
+func derefId(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// derefPage turns an optional response page into a slice without nil
+// records.
+func derefPage[T any](page *[]*T) []*T {
+	if page == nil {
+		return nil
+	}
+	return slices.DeleteFunc(*page, func(record *T) bool { return record == nil })
+}
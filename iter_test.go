@@ -0,0 +1,187 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// historyServer serves /myTrades and /allOrders from records the way the
+// exchange pages them, and logs every query.
+type historyServer struct {
+	records []map[string]int64 // "id" and "time", sorted by id
+
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (h *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	h.mu.Lock()
+	h.queries = append(h.queries, query)
+	h.mu.Unlock()
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	param := func(name string) (int64, bool) {
+		value, err := strconv.ParseInt(query.Get(name), 10, 64)
+		return value, err == nil
+	}
+
+	var page []map[string]int64
+	if fromId, ok := param("fromId"); ok {
+		for _, record := range h.records {
+			if record["id"] >= fromId {
+				page = append(page, record)
+			}
+		}
+	} else if start, ok := param("startTime"); ok {
+		end, _ := param("endTime")
+		for _, record := range h.records {
+			if record["time"] >= start && record["time"] <= end {
+				page = append(page, record)
+			}
+		}
+	} else {
+		page = h.records[max(len(h.records)-limit, 0):]
+	}
+	page = page[:min(len(page), limit)]
+
+	var body []map[string]any
+	for _, record := range page {
+		if strings.HasSuffix(r.URL.Path, "/allOrders") {
+			body = append(body, map[string]any{"orderId": record["id"], "time": record["time"]})
+		} else {
+			body = append(body, map[string]any{"id": record["id"], "time": record["time"]})
+		}
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newHistoryClient(tt *testing.T, history *historyServer) *Client {
+	tt.Helper()
+
+	srv := httptest.NewServer(history)
+	tt.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return client
+}
+
+func TestIterUserTrades(tt *testing.T) {
+	// Ids 0–9; ids 2–7 share one millisecond.
+	var records []map[string]int64
+	for id := int64(0); id < 10; id++ {
+		at := 1000 + id
+		if id >= 2 && id <= 7 {
+			at = 1002
+		}
+		records = append(records, map[string]int64{"id": id, "time": at})
+	}
+
+	three := int64(3)
+	tests := []struct {
+		name      string
+		fromId    *int64
+		startTime int64
+		endTime   int64
+		want      []int64
+		firstPage string
+	}{
+		{
+			name:      "from the first trade",
+			want:      []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			firstPage: "fromId=0&limit=3",
+		},
+		{
+			name:      "from an id until a time",
+			fromId:    &three,
+			endTime:   1008,
+			want:      []int64{3, 4, 5, 6, 7, 8},
+			firstPage: "fromId=3&limit=3",
+		},
+		{
+			name:      "by time through a crowded millisecond",
+			startTime: 1001,
+			endTime:   1009,
+			want:      []int64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			firstPage: "endTime=1009&limit=3&startTime=1001",
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			history := &historyServer{records: records}
+			client := newHistoryClient(tt, history)
+
+			params := t.GetUserTradesParams{FromId: tc.fromId}
+			params.Symbol = "BTCIRT"
+			params.StartTime = tc.startTime
+			params.EndTime = tc.endTime
+			params.Limit = 3
+
+			var got []int64
+			for trade, err := range client.IterUserTrades(context.Background(), params) {
+				if err != nil {
+					tt.Fatal(err)
+				}
+				got = append(got, trade.Id)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				tt.Errorf("trades = %v, want %v", got, tc.want)
+			}
+
+			first := history.queries[0]
+			for _, name := range []string{"recvWindow", "signature", "symbol", "timestamp"} {
+				first.Del(name)
+			}
+			if first.Encode() != tc.firstPage {
+				tt.Errorf("first page query = %s, want %s", first.Encode(), tc.firstPage)
+			}
+		})
+	}
+}
+
+func TestIterOrdersHistoryCrowdedMillisecond(tt *testing.T) {
+	var records []map[string]int64
+	for id := int64(1); id <= 4; id++ {
+		records = append(records, map[string]int64{"id": id, "time": 5000})
+	}
+
+	params := t.GetUserOrdersHistoryParams{}
+	params.Symbol = "BTCIRT"
+	params.StartTime = 5000
+	params.EndTime = 6000
+	params.Limit = 3
+
+	client := newHistoryClient(tt, &historyServer{records: records})
+
+	var got []int64
+	var failed error
+	for order, err := range client.IterOrdersHistory(context.Background(), params) {
+		if err != nil {
+			failed = err
+			break
+		}
+		got = append(got, order.OrderId)
+	}
+
+	if !slices.Equal(got, []int64{1, 2, 3}) {
+		tt.Errorf("orders = %v, want [1 2 3]", got)
+	}
+	if failed == nil || !strings.Contains(failed.Error(), "more than 3 records") {
+		tt.Errorf("error = %v, want the crowded millisecond reported", failed)
+	}
+}
@@ -0,0 +1,436 @@
+package tabdeal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// OrderBookSnapshot is a consistent, point-in-time copy of a managed book.
+// The embedded OrderBook holds LastUpdateId and both sides, sorted best
+// first, so Spread, MidPrice, ImpactPrice, VWAPForQuantity and DepthWithin
+// are available on the snapshot.
+type OrderBookSnapshot struct {
+	t.OrderBook
+
+	// Symbol is the market as passed to NewOrderBookManager.
+	Symbol string
+
+	// UpdatedAt is when the book last changed.
+	UpdatedAt time.Time
+}
+
+// OrderBookOptions configures an OrderBookManager.
+type OrderBookOptions struct {
+	// Limit is the depth requested from GetOrderBook when seeding and
+	// polling. Defaults to 1000.
+	Limit int64
+
+	// Stream, when set, supplies depth updates. The book is kept in sync
+	// from diffs between REST snapshots. When nil, or when the depth
+	// subscription ends, the book is refreshed by polling instead.
+	Stream *Stream
+
+	// PollInterval is the refresh period in polling mode. Defaults to one
+	// second.
+	PollInterval time.Duration
+
+	// OnError, when set, receives failed snapshots, sequence gaps and a
+	// fallback to polling. The manager recovers from all of them on its own.
+	OnError func(error)
+}
+
+// OrderBookManager maintains a local copy of one market's order book.
+//
+// The book is seeded from GetOrderBook and then kept current either from
+// depth stream updates or, without a stream, by polling snapshots.
+//
+// Behavior:
+//   - Stream updates already contained in the snapshot (FinalUpdateId ≤
+//     LastUpdateId) are skipped.
+//   - An update whose FirstUpdateId is past LastUpdateId+1 reveals a gap;
+//     the book is discarded and seeded again. Resyncs counts these.
+//   - A zero quantity removes a level; any other quantity replaces it.
+//   - When the snapshot carries no LastUpdateId, updates are applied
+//     without gap detection.
+//   - Polled snapshots replace the book unless they are older than the
+//     current state.
+//   - All read methods are safe for concurrent use and see the book
+//     between updates, never half-applied.
+//
+// Example:
+//
+//	book := client.NewOrderBookManager("BTCIRT", tabdeal.OrderBookOptions{Stream: stream})
+//	if err := book.Start(ctx); err != nil {
+//	    log.Println("initial snapshot failed, retrying in background:", err)
+//	}
+//	<-book.Ready()
+//
+//	bid, _ := book.BestBid()
+//	ask, _ := book.BestAsk()
+//	fmt.Println(bid.Price, ask.Price)
+type OrderBookManager struct {
+	client *Client
+	symbol string
+	opts   OrderBookOptions
+
+	mu           sync.RWMutex
+	bids         []t.PriceLevel
+	asks         []t.PriceLevel
+	lastUpdateId int64
+	updatedAt    time.Time
+	synced       bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+	resyncs   atomic.Uint64
+	streaming atomic.Bool
+}
+
+// NewOrderBookManager creates a manager for symbol ("BTCIRT" or
+// "BTC_IRT"). Nothing is fetched until Start.
+func (c *Client) NewOrderBookManager(symbol string, opts OrderBookOptions) *OrderBookManager {
+	if opts.Limit <= 0 {
+		opts.Limit = 1000
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	return &OrderBookManager{
+		client: c,
+		symbol: symbol,
+		opts:   opts,
+		ready:  make(chan struct{}),
+	}
+}
+
+// Start subscribes to depth updates (when a Stream is configured), seeds
+// the book and keeps it in sync in the background until ctx is done.
+//
+// It returns the error of the initial snapshot; the background loop keeps
+// trying regardless, so Ready is closed once a later attempt succeeds.
+func (m *OrderBookManager) Start(ctx context.Context) error {
+	var sub *Subscription[t.DepthUpdateEvent]
+	if m.opts.Stream != nil {
+		var err error
+		if sub, err = m.opts.Stream.SubscribeDepth(ctx, m.symbol); err != nil {
+			m.reportError(&GoTabdealError{Message: "depth stream unavailable, polling instead", Err: err})
+			sub = nil
+		}
+	}
+
+	err := m.seed(ctx)
+
+	go m.run(ctx, sub, err == nil)
+
+	return err
+}
+
+// Ready returns a channel that is closed once the book has been seeded.
+func (m *OrderBookManager) Ready() <-chan struct{} {
+	return m.ready
+}
+
+// Synced reports whether the book currently reflects a consistent state.
+// It is false before seeding and while recovering from a gap.
+func (m *OrderBookManager) Synced() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.synced
+}
+
+// Streaming reports whether the book is fed by the depth stream rather
+// than by polling.
+func (m *OrderBookManager) Streaming() bool {
+	return m.streaming.Load()
+}
+
+// Resyncs returns how many times a gap forced the book to be seeded again.
+func (m *OrderBookManager) Resyncs() uint64 {
+	return m.resyncs.Load()
+}
+
+// LastUpdateId returns the sequence number of the last applied update.
+func (m *OrderBookManager) LastUpdateId() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.lastUpdateId
+}
+
+// Snapshot returns a copy of the whole book.
+func (m *OrderBookManager) Snapshot() OrderBookSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return OrderBookSnapshot{
+		OrderBook: t.OrderBook{
+			LastUpdateId: m.lastUpdateId,
+			Bids:         slices.Clone(m.bids),
+			Asks:         slices.Clone(m.asks),
+		},
+		Symbol:    m.symbol,
+		UpdatedAt: m.updatedAt,
+	}
+}
+
+// BestBid returns the highest bid, or false when there are no bids.
+func (m *OrderBookManager) BestBid() (t.PriceLevel, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.bids) == 0 {
+		return t.PriceLevel{}, false
+	}
+	return m.bids[0], true
+}
+
+// BestAsk returns the lowest ask, or false when there are no asks.
+func (m *OrderBookManager) BestAsk() (t.PriceLevel, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.asks) == 0 {
+		return t.PriceLevel{}, false
+	}
+	return m.asks[0], true
+}
+
+// DepthAt returns the quantity resting at exactly price on either side of
+// the book, or zero when there is no such level.
+func (m *OrderBookManager) DepthAt(price t.Decimal) t.Decimal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if i, ok := slices.BinarySearchFunc(m.bids, price, compareBid); ok {
+		return m.bids[i].Quantity
+	}
+	if i, ok := slices.BinarySearchFunc(m.asks, price, compareAsk); ok {
+		return m.asks[i].Quantity
+	}
+	return t.Decimal{}
+}
+
+// QuantityForNotional walks the book as a taker on side would and returns
+// the base quantity that notional (in quote currency) buys or sells:
+// buys consume asks, sells consume bids.
+//
+// complete is false when the visible book is too thin to absorb the whole
+// notional; quantity then covers every level.
+//
+// Example:
+//
+//	// How much BTC do 1,000,000,000 IRT buy right now?
+//	qty, ok := book.QuantityForNotional(types.OrderSideBuy, types.MustParseDecimal("1000000000"))
+func (m *OrderBookManager) QuantityForNotional(side t.OrderSide, notional t.Decimal) (quantity t.Decimal, complete bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := m.asks
+	if side == t.OrderSideSell {
+		levels = m.bids
+	}
+
+	remaining := notional
+	for _, level := range levels {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		levelNotional := level.Price.Mul(level.Quantity)
+		if remaining.GreaterThanOrEqual(levelNotional) {
+			quantity = quantity.Add(level.Quantity)
+			remaining = remaining.Sub(levelNotional)
+			continue
+		}
+
+		quantity = quantity.Add(remaining.DivRound(level.Price, max(level.Quantity.Scale(), 8), t.RoundDown))
+		remaining = t.Decimal{}
+	}
+
+	return quantity, !remaining.IsPositive()
+}
+
+// run keeps the book in sync until ctx is done.
+func (m *OrderBookManager) run(ctx context.Context, sub *Subscription[t.DepthUpdateEvent], seeded bool) {
+	if sub != nil {
+		m.streaming.Store(true)
+		m.follow(ctx, sub, seeded)
+		m.streaming.Store(false)
+
+		if ctx.Err() != nil {
+			unsubscribeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			_ = sub.Unsubscribe(unsubscribeCtx)
+			cancel()
+			return
+		}
+
+		m.reportError(&GoTabdealError{Message: "depth stream ended, polling instead", Err: sub.Err()})
+	}
+
+	m.poll(ctx)
+}
+
+// follow applies depth updates, reseeding on gaps, until the subscription
+// ends or ctx is done.
+func (m *OrderBookManager) follow(ctx context.Context, sub *Subscription[t.DepthUpdateEvent], seeded bool) {
+	backoff := DefaultReconnectPolicy()
+
+	for failures := 0; ; {
+		if !seeded {
+			if err := m.seed(ctx); err != nil {
+				m.reportError(err)
+				failures++
+
+				timer := time.NewTimer(backoff.backoff(failures))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				continue
+			}
+			seeded, failures = true, 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C():
+			if !ok {
+				return
+			}
+			if err := m.apply(event); err != nil {
+				m.reportError(err)
+				m.resyncs.Add(1)
+				seeded = false
+			}
+		}
+	}
+}
+
+// poll refreshes the book from snapshots until ctx is done.
+func (m *OrderBookManager) poll(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.seed(ctx); err != nil && ctx.Err() == nil {
+				m.reportError(err)
+			}
+		}
+	}
+}
+
+// seed replaces the book with a REST snapshot, unless the snapshot is
+// older than the current synced state.
+func (m *OrderBookManager) seed(ctx context.Context) error {
+	book, err := m.client.GetOrderBookCtx(ctx, t.GetOrderBookParams{
+		BaseSymbolParams: orderBookSymbol(m.symbol),
+		Limit:            m.opts.Limit,
+	})
+	if err != nil {
+		return &GoTabdealError{Message: "failed to fetch order book snapshot", Err: err}
+	}
+
+	bids, asks := slices.Clone(book.Bids), slices.Clone(book.Asks)
+	slices.SortFunc(bids, func(a, b t.PriceLevel) int { return b.Price.Cmp(a.Price) })
+	slices.SortFunc(asks, func(a, b t.PriceLevel) int { return a.Price.Cmp(b.Price) })
+
+	m.mu.Lock()
+	if m.synced && book.LastUpdateId != 0 && book.LastUpdateId < m.lastUpdateId {
+		m.mu.Unlock()
+		return nil
+	}
+	m.bids, m.asks = bids, asks
+	m.lastUpdateId = book.LastUpdateId
+	m.updatedAt = time.Now()
+	m.synced = true
+	m.mu.Unlock()
+
+	m.readyOnce.Do(func() { close(m.ready) })
+	return nil
+}
+
+// apply merges a depth update into the book. It returns an error, and
+// marks the book unsynced, when the update does not follow the current
+// sequence number.
+func (m *OrderBookManager) apply(event t.DepthUpdateEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if event.FinalUpdateId <= m.lastUpdateId {
+		return nil
+	}
+
+	if m.lastUpdateId != 0 && event.FirstUpdateId > m.lastUpdateId+1 {
+		m.synced = false
+		return &GoTabdealError{Message: fmt.Sprintf("order book gap on %s: expected update %d, got %d", m.symbol, m.lastUpdateId+1, event.FirstUpdateId)}
+	}
+
+	for _, level := range event.Bids {
+		m.bids = setBookLevel(m.bids, level, compareBid)
+	}
+	for _, level := range event.Asks {
+		m.asks = setBookLevel(m.asks, level, compareAsk)
+	}
+
+	m.lastUpdateId = event.FinalUpdateId
+	m.updatedAt = time.Now()
+	return nil
+}
+
+func (m *OrderBookManager) reportError(err error) {
+	if m.opts.OnError != nil && err != nil {
+		m.opts.OnError(err)
+	}
+}
+
+// compareBid orders bids from the highest price down.
+func compareBid(level t.PriceLevel, price t.Decimal) int {
+	return price.Cmp(level.Price)
+}
+
+// compareAsk orders asks from the lowest price up.
+func compareAsk(level t.PriceLevel, price t.Decimal) int {
+	return level.Price.Cmp(price)
+}
+
+// setBookLevel inserts, replaces or (for a zero quantity) removes a level
+// in a sorted side of the book.
+func setBookLevel(levels []t.PriceLevel, level t.PriceLevel, cmp func(t.PriceLevel, t.Decimal) int) []t.PriceLevel {
+	i, found := slices.BinarySearchFunc(levels, level.Price, cmp)
+
+	switch {
+	case level.Quantity.IsZero() && found:
+		return slices.Delete(levels, i, i+1)
+	case level.Quantity.IsZero():
+		return levels
+	case found:
+		levels[i] = level
+		return levels
+	}
+
+	return slices.Insert(levels, i, level)
+}
+
+// orderBookSymbol addresses symbol by whichever field matches its format.
+func orderBookSymbol(symbol string) t.BaseSymbolParams {
+	if strings.Contains(symbol, "_") {
+		return t.BaseSymbolParams{TabdealSymbol: symbol}
+	}
+	return t.BaseSymbolParams{Symbol: symbol}
+}
@@ -0,0 +1,209 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-tabdeal/streamtest"
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// depthServer serves GET /depth from a snapshot the test can replace.
+type depthServer struct {
+	mu       sync.Mutex
+	book     t.OrderBook
+	requests atomic.Int32
+}
+
+func (s *depthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/depth") {
+		http.NotFound(w, r)
+		return
+	}
+	s.requests.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(s.book)
+}
+
+func (s *depthServer) set(lastUpdateId int64, bids, asks []t.PriceLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.book = t.OrderBook{LastUpdateId: lastUpdateId, Bids: bids, Asks: asks}
+}
+
+// levels builds price levels from price, quantity pairs.
+func levels(pairs ...string) []t.PriceLevel {
+	var out []t.PriceLevel
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = append(out, t.PriceLevel{Price: t.MustParseDecimal(pairs[i]), Quantity: t.MustParseDecimal(pairs[i+1])})
+	}
+	return out
+}
+
+// sides formats both sides of a snapshot for comparison.
+func sides(book OrderBookSnapshot) string {
+	format := func(levels []t.PriceLevel) string {
+		var parts []string
+		for _, level := range levels {
+			parts = append(parts, level.Price.String()+"x"+level.Quantity.String())
+		}
+		return strings.Join(parts, " ")
+	}
+	return format(book.Bids) + " | " + format(book.Asks)
+}
+
+func newDepthClient(tt *testing.T, exchange *depthServer) *Client {
+	tt.Helper()
+
+	srv := httptest.NewServer(exchange)
+	tt.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return client
+}
+
+func TestOrderBookManagerApply(tt *testing.T) {
+	exchange := &depthServer{}
+	exchange.set(100, levels("99", "1", "101", "3", "100", "2"), levels("103", "1", "102", "2"))
+	book := newDepthClient(tt, exchange).NewOrderBookManager("BTCIRT", OrderBookOptions{})
+
+	if err := book.seed(context.Background()); err != nil {
+		tt.Fatal(err)
+	}
+	if got := sides(book.Snapshot()); got != "101x3 100x2 99x1 | 102x2 103x1" {
+		tt.Fatalf("seeded book = %s", got)
+	}
+
+	// Updates the snapshot already contains are skipped.
+	if err := book.apply(t.DepthUpdateEvent{FirstUpdateId: 95, FinalUpdateId: 100, Bids: levels("101", "9")}); err != nil {
+		tt.Fatal(err)
+	}
+
+	// An update may overlap the snapshot; it replaces, removes and adds
+	// levels on both sides.
+	err := book.apply(t.DepthUpdateEvent{
+		FirstUpdateId: 99,
+		FinalUpdateId: 102,
+		Bids:          levels("100", "0", "101", "4", "100.5", "1", "98", "0"),
+		Asks:          levels("102", "0", "101.5", "5"),
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if got := sides(book.Snapshot()); got != "101x4 100.5x1 99x1 | 101.5x5 103x1" {
+		tt.Errorf("book after the update = %s", got)
+	}
+	if book.LastUpdateId() != 102 || !book.Synced() {
+		tt.Errorf("LastUpdateId() = %d, Synced() = %v", book.LastUpdateId(), book.Synced())
+	}
+	if bid, _ := book.BestBid(); bid.Price.String() != "101" {
+		tt.Errorf("BestBid() = %s", bid.Price)
+	}
+	if ask, _ := book.BestAsk(); ask.Price.String() != "101.5" {
+		tt.Errorf("BestAsk() = %s", ask.Price)
+	}
+	if depth := book.DepthAt(t.MustParseDecimal("100.5")); depth.String() != "1" {
+		tt.Errorf("DepthAt(100.5) = %s", depth)
+	}
+
+	// Update 103 is missing.
+	if err := book.apply(t.DepthUpdateEvent{FirstUpdateId: 104, FinalUpdateId: 105, Bids: levels("1", "1")}); err == nil {
+		tt.Fatal("apply() accepted an update past a gap")
+	}
+	if book.Synced() || book.LastUpdateId() != 102 || sides(book.Snapshot()) != "101x4 100.5x1 99x1 | 101.5x5 103x1" {
+		tt.Errorf("book after the gap: synced %v at %d, %s", book.Synced(), book.LastUpdateId(), sides(book.Snapshot()))
+	}
+}
+
+func TestOrderBookManagerReseedsOnGap(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	exchange := &depthServer{}
+	exchange.set(100, levels("100", "1"), levels("101", "1"))
+
+	var mu sync.Mutex
+	var errs []string
+	ctx := testContext(tt)
+	book := newDepthClient(tt, exchange).NewOrderBookManager("BTC_IRT", OrderBookOptions{
+		Stream: connectStream(tt, srv.URL, StreamOptions{}),
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err.Error())
+		},
+	})
+	if err := book.Start(ctx); err != nil {
+		tt.Fatal(err)
+	}
+	<-book.Ready()
+	if err := srv.WaitForSubscription(ctx, "btcirt@depth"); err != nil {
+		tt.Fatal(err)
+	}
+	waitFor(tt, "streaming mode", book.Streaming)
+
+	srv.PublishDepth(t.DepthUpdateEvent{Symbol: "BTCIRT", FirstUpdateId: 101, FinalUpdateId: 101, Bids: levels("100", "2")})
+	waitFor(tt, "update 101", func() bool { return book.LastUpdateId() == 101 })
+
+	// The exchange moves on to 200 while updates 102-149 are lost.
+	exchange.set(200, levels("90", "7"), levels("110", "7"))
+	srv.PublishDepth(t.DepthUpdateEvent{Symbol: "BTCIRT", FirstUpdateId: 150, FinalUpdateId: 150, Bids: levels("1", "1")})
+	waitFor(tt, "the reseed", func() bool { return book.LastUpdateId() == 200 && book.Synced() })
+
+	if got := sides(book.Snapshot()); got != "90x7 | 110x7" {
+		tt.Errorf("book after the reseed = %s", got)
+	}
+	if book.Resyncs() != 1 || exchange.requests.Load() != 2 {
+		tt.Errorf("%d resyncs and %d snapshots, want 1 and 2", book.Resyncs(), exchange.requests.Load())
+	}
+	mu.Lock()
+	if len(errs) != 1 || !strings.Contains(errs[0], "expected update 102, got 150") {
+		tt.Errorf("OnError got %q, want the gap", errs)
+	}
+	mu.Unlock()
+
+	// Updates continue from the new snapshot.
+	srv.PublishDepth(t.DepthUpdateEvent{Symbol: "BTCIRT", FirstUpdateId: 201, FinalUpdateId: 201, Asks: levels("110", "0", "111", "3")})
+	waitFor(tt, "update 201", func() bool { return book.LastUpdateId() == 201 })
+	if got := sides(book.Snapshot()); got != "90x7 | 111x3" {
+		tt.Errorf("book after update 201 = %s", got)
+	}
+}
+
+func TestOrderBookManagerPolls(tt *testing.T) {
+	exchange := &depthServer{}
+	exchange.set(10, levels("100", "1"), levels("101", "1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	book := newDepthClient(tt, exchange).NewOrderBookManager("BTCIRT", OrderBookOptions{PollInterval: 10 * time.Millisecond})
+	if err := book.Start(ctx); err != nil {
+		tt.Fatal(err)
+	}
+
+	exchange.set(11, levels("100", "5"), levels("101", "1"))
+	waitFor(tt, "the polled snapshot", func() bool { return book.LastUpdateId() == 11 })
+
+	// An older snapshot does not roll the book back.
+	exchange.set(9, levels("1", "1"), nil)
+	polled := exchange.requests.Load()
+	waitFor(tt, "another poll", func() bool { return exchange.requests.Load() > polled+1 })
+	if got := sides(book.Snapshot()); book.LastUpdateId() != 11 || got != "100x5 | 101x1" {
+		tt.Errorf("book at %d after an older snapshot = %s", book.LastUpdateId(), got)
+	}
+	if book.Streaming() {
+		tt.Error("Streaming() = true without a stream")
+	}
+}
@@ -0,0 +1,340 @@
+package tabdeal
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// portfolioScale is the number of decimal places kept for converted prices
+// and values.
+const portfolioScale = 18
+
+// PriceSource selects the price ValuePortfolio converts assets at.
+type PriceSource int
+
+const (
+	// PriceSourceMid values at the middle of the best bid and ask.
+	PriceSourceMid PriceSource = iota
+
+	// PriceSourceBid values at what selling immediately would fetch: the
+	// best bid when the asset is the base of a market and the inverse of
+	// the best ask when it is the quote.
+	PriceSourceBid
+
+	// PriceSourceLast values at the last traded price of each market.
+	PriceSourceLast
+)
+
+// String returns "mid", "bid" or "last".
+func (s PriceSource) String() string {
+	switch s {
+	case PriceSourceMid:
+		return "mid"
+	case PriceSourceBid:
+		return "bid"
+	case PriceSourceLast:
+		return "last"
+	}
+	return "unknown"
+}
+
+// PortfolioOptions configures ValuePortfolio. The zero value values every
+// non-empty wallet in IRT and USDT at mid prices.
+type PortfolioOptions struct {
+	// Quotes are the assets the portfolio is valued in. Default: IRT, USDT.
+	Quotes []string
+
+	// PriceSource selects the conversion price. Default: PriceSourceMid.
+	PriceSource PriceSource
+
+	// Intermediates are the assets a conversion may pass through when an
+	// asset has no direct market in a quote, tried in order. Default:
+	// USDT, IRT, BTC, ETH.
+	Intermediates []string
+
+	// IncludeZero keeps wallets whose free and frozen balances are both
+	// zero.
+	IncludeZero bool
+}
+
+// Portfolio is a valuation of the user's wallets.
+type Portfolio struct {
+	// Time is when the valuation was taken.
+	Time time.Time
+
+	// PriceSource is the price the assets were converted at.
+	PriceSource PriceSource
+
+	// Assets holds one entry per wallet, sorted by asset.
+	Assets []PortfolioAsset
+
+	// Totals is the value of all priced assets, keyed by quote asset.
+	Totals map[string]t.Decimal
+}
+
+// PortfolioAsset is the balance and value of one asset.
+type PortfolioAsset struct {
+	Asset  string
+	Free   t.Decimal
+	Frozen t.Decimal
+	Total  t.Decimal
+
+	// Values is keyed by quote asset. A quote is missing when no route
+	// of markets with prices connects the asset to it.
+	Values map[string]AssetValue
+}
+
+// AssetValue is the value of one asset in one quote asset.
+type AssetValue struct {
+	// Price is the value of one unit of the asset.
+	Price  t.Decimal
+	Free   t.Decimal
+	Frozen t.Decimal
+	Total  t.Decimal
+
+	// Route lists the assets the conversion passed through, from the
+	// asset to the quote, e.g. [DOGE USDT IRT]. It is just [IRT] when
+	// valuing IRT in IRT.
+	Route []string
+}
+
+// Unpriced returns the assets that could not be valued in quote.
+func (p *Portfolio) Unpriced(quote string) []string {
+	quote = strings.ToUpper(quote)
+
+	var assets []string
+	for _, asset := range p.Assets {
+		if _, ok := asset.Values[quote]; !ok {
+			assets = append(assets, asset.Asset)
+		}
+	}
+	return assets
+}
+
+// ValuePortfolio values every wallet of the authenticated user in IRT and
+// USDT (or opts.Quotes).
+//
+// Behavior:
+//   - Balances come from GetWallets; prices from GetAllBookTickers for
+//     PriceSourceMid and PriceSourceBid, or GetAll24hrTickers for
+//     PriceSourceLast. Symbols are split into base and quote with
+//     Client.Symbols.
+//   - Assets without a direct market in a quote are converted through
+//     opts.Intermediates, using the route with the fewest hops.
+//   - Tickers whose symbol cannot be split into base and quote are
+//     skipped.
+//   - Assets that cannot be routed to a quote are left out of that
+//     quote's total; see Portfolio.Unpriced.
+//
+// Example:
+//
+//	portfolio, err := client.ValuePortfolio(tabdeal.PortfolioOptions{
+//	    PriceSource: tabdeal.PriceSourceBid,
+//	})
+//	fmt.Println(portfolio.Totals["IRT"], portfolio.Totals["USDT"])
+//	for _, asset := range portfolio.Assets {
+//	    fmt.Println(asset.Asset, asset.Free, asset.Frozen, asset.Values["IRT"].Total)
+//	}
+func (c *Client) ValuePortfolio(opts PortfolioOptions) (*Portfolio, error) {
+	return c.ValuePortfolioCtx(context.Background(), opts)
+}
+
+// ValuePortfolioCtx is ValuePortfolio with a caller-supplied context; see RequestCtx.
+func (c *Client) ValuePortfolioCtx(ctx context.Context, opts PortfolioOptions) (*Portfolio, error) {
+	quotes := upperAssets(opts.Quotes, "IRT", "USDT")
+	intermediates := upperAssets(opts.Intermediates, "USDT", "IRT", "BTC", "ETH")
+
+	wallets, err := c.GetWalletsCtx(ctx, t.GetWalletParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := c.conversionPrices(ctx, opts.PriceSource)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &Portfolio{
+		Time:        time.Now(),
+		PriceSource: opts.PriceSource,
+		Totals:      make(map[string]t.Decimal, len(quotes)),
+	}
+	for _, quote := range quotes {
+		portfolio.Totals[quote] = t.Decimal{}
+	}
+
+	for _, wallet := range derefPage(wallets) {
+		if !opts.IncludeZero && wallet.Free.IsZero() && wallet.Freeze.IsZero() {
+			continue
+		}
+
+		asset := PortfolioAsset{
+			Asset:  strings.ToUpper(wallet.Asset),
+			Free:   wallet.Free,
+			Frozen: wallet.Freeze,
+			Total:  wallet.Free.Add(wallet.Freeze),
+			Values: make(map[string]AssetValue, len(quotes)),
+		}
+
+		for _, quote := range quotes {
+			price, route, ok := prices.convert(asset.Asset, quote, intermediates)
+			if !ok {
+				continue
+			}
+
+			value := AssetValue{
+				Price:  price,
+				Free:   portfolioRound(asset.Free.Mul(price)),
+				Frozen: portfolioRound(asset.Frozen.Mul(price)),
+				Total:  portfolioRound(asset.Total.Mul(price)),
+				Route:  route,
+			}
+			asset.Values[quote] = value
+			portfolio.Totals[quote] = portfolio.Totals[quote].Add(value.Total)
+		}
+
+		portfolio.Assets = append(portfolio.Assets, asset)
+	}
+
+	slices.SortFunc(portfolio.Assets, func(a, b PortfolioAsset) int { return strings.Compare(a.Asset, b.Asset) })
+
+	return portfolio, nil
+}
+
+// conversionGraph holds the price of one unit of a "from" asset in a "to"
+// asset for every pair of assets with a priced market.
+type conversionGraph map[[2]string]t.Decimal
+
+// conversionPrices builds the conversion graph from the tickers that match
+// source, skipping tickers whose symbol cannot be resolved.
+func (c *Client) conversionPrices(ctx context.Context, source PriceSource) (conversionGraph, error) {
+	graph := make(conversionGraph)
+
+	add := func(symbol string, forward, reverse t.Decimal) error {
+		market, err := c.Symbols.ResolveMarket(ctx, symbol)
+		if err != nil {
+			// A symbol that cannot be split only leaves out its own
+			// prices; without exchangeInfo nothing can be split.
+			if c.Symbols.LoadedAt().IsZero() {
+				return err
+			}
+			return nil
+		}
+		if forward.IsPositive() {
+			graph[[2]string{market.Base, market.Quote}] = forward
+		}
+		if reverse.IsPositive() {
+			graph[[2]string{market.Quote, market.Base}] = portfolioInverse(reverse)
+		}
+		return nil
+	}
+
+	if source == PriceSourceLast {
+		tickers, err := c.GetAll24hrTickersCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ticker := range derefPage(tickers) {
+			if err := add(ticker.Symbol, ticker.LastPrice, ticker.LastPrice); err != nil {
+				return nil, err
+			}
+		}
+		return graph, nil
+	}
+
+	tickers, err := c.GetAllBookTickersCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticker := range derefPage(tickers) {
+		forward, reverse := ticker.BidPrice, ticker.AskPrice
+		if source == PriceSourceMid {
+			// A one-sided book is valued at its remaining side.
+			mid := ticker.BidPrice
+			switch {
+			case ticker.BidPrice.IsPositive() && ticker.AskPrice.IsPositive():
+				mid = portfolioRound(ticker.BidPrice.Add(ticker.AskPrice).DivRound(t.NewDecimalFromInt(2), portfolioScale, t.RoundNearest))
+			case !ticker.BidPrice.IsPositive():
+				mid = ticker.AskPrice
+			}
+			forward, reverse = mid, mid
+		}
+
+		if err := add(ticker.Symbol, forward, reverse); err != nil {
+			return nil, err
+		}
+	}
+
+	return graph, nil
+}
+
+// convert returns the price of one unit of from in to and the route it
+// took. Routes may only pass through intermediates; among the shortest
+// routes, the one through the earliest intermediates wins.
+func (g conversionGraph) convert(from, to string, intermediates []string) (t.Decimal, []string, bool) {
+	if from == to {
+		return t.NewDecimalFromInt(1), []string{to}, true
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range append([]string{to}, intermediates...) {
+			if _, visited := previous[next]; visited {
+				continue
+			}
+			if _, ok := g[[2]string{node, next}]; !ok {
+				continue
+			}
+
+			previous[next] = node
+			if next != to {
+				queue = append(queue, next)
+				continue
+			}
+
+			route := []string{to}
+			for at := node; at != ""; at = previous[at] {
+				route = append(route, at)
+			}
+			slices.Reverse(route)
+
+			price := t.NewDecimalFromInt(1)
+			for i := 1; i < len(route); i++ {
+				price = portfolioRound(price.Mul(g[[2]string{route[i-1], route[i]}]))
+			}
+			return price, route, true
+		}
+	}
+
+	return t.Decimal{}, nil, false
+}
+
+// upperAssets upper-cases assets, or returns defaults when assets is empty.
+func upperAssets(assets []string, defaults ...string) []string {
+	if len(assets) == 0 {
+		return defaults
+	}
+
+	upper := make([]string, len(assets))
+	for i, asset := range assets {
+		upper[i] = strings.ToUpper(asset)
+	}
+	return upper
+}
+
+func portfolioInverse(price t.Decimal) t.Decimal {
+	return t.NewDecimalFromInt(1).DivRound(price, portfolioScale, t.RoundNearest).Normalize()
+}
+
+func portfolioRound(value t.Decimal) t.Decimal {
+	return value.RoundMode(portfolioScale, t.RoundNearest).Normalize()
+}
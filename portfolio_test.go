@@ -0,0 +1,69 @@
+package tabdeal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValuePortfolioSkipsUnresolvableSymbols(tt *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/exchangeInfo"):
+			_, _ = w.Write([]byte(`[{"symbol":"BTCIRT","tabdealSymbol":"BTC_IRT","baseAsset":"BTC","quoteAsset":"IRT"}]`))
+		case strings.HasSuffix(r.URL.Path, "/get-funding-asset"):
+			_, _ = w.Write([]byte(`[{"asset":"BTC","free":"2","freeze":"0"},{"asset":"XYZ","free":"5","freeze":"0"}]`))
+		case strings.HasSuffix(r.URL.Path, "/ticker/bookTicker"):
+			// "XYZ" has no quote asset to split on.
+			_, _ = w.Write([]byte(`[
+				{"symbol":"BTCIRT","bidPrice":"100","askPrice":"100"},
+				{"symbol":"XYZ","bidPrice":"3","askPrice":"3"}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	portfolio, err := client.ValuePortfolio(PortfolioOptions{Quotes: []string{"IRT"}})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	if total := portfolio.Totals["IRT"]; total.String() != "200" {
+		tt.Errorf("IRT total = %s, want 200", total)
+	}
+	if unpriced := portfolio.Unpriced("IRT"); !slices.Equal(unpriced, []string{"XYZ"}) {
+		tt.Errorf("Unpriced() = %v, want [XYZ]", unpriced)
+	}
+}
+
+func TestValuePortfolioFailsWithoutExchangeInfo(tt *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/get-funding-asset"):
+			_, _ = w.Write([]byte(`[{"asset":"BTC","free":"2","freeze":"0"}]`))
+		case strings.HasSuffix(r.URL.Path, "/ticker/bookTicker"):
+			_, _ = w.Write([]byte(`[{"symbol":"BTCIRT","bidPrice":"100","askPrice":"100"}]`))
+		default:
+			http.Error(w, `{"code":-1000,"msg":"unavailable"}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	if _, err := client.ValuePortfolio(PortfolioOptions{}); err == nil {
+		tt.Error("ValuePortfolio() succeeded without exchangeInfo")
+	}
+}
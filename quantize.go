@@ -0,0 +1,170 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// QuantizeOptions enables automatic price and quantity quantization in
+// CreateOrder and CreateOCOOrder and selects the rounding directions.
+type QuantizeOptions struct {
+	// PriceRounding returns the rounding mode for price and stopPrice.
+	// When nil, types.SideRounding is used: buys round up, sells round down.
+	PriceRounding func(side t.OrderSide) t.RoundingMode
+
+	// QuantityRounding returns the rounding mode for quantity. When nil,
+	// quantities round down so an order never exceeds the intended size.
+	QuantityRounding func(side t.OrderSide) t.RoundingMode
+
+	// OnQuantize, when set, receives a report for every quantized order,
+	// including orders that needed no change.
+	OnQuantize func(QuantizationReport)
+}
+
+// QuantizationChange records how a single order field was adjusted.
+type QuantizationChange struct {
+	// Field is "price", "stopPrice" or "quantity", or "stopLimitPrice"
+	// for OCO orders.
+	Field string
+
+	// From is the value that was passed in.
+	From t.Decimal
+
+	// To is the value that is sent.
+	To t.Decimal
+}
+
+// QuantizationReport describes what QuantizeOrder changed.
+type QuantizationReport struct {
+	// Symbol is the market of the order.
+	Symbol string
+
+	// Changes lists every field whose numeric value changed. Fields that
+	// were only reformatted (for example "1.5" to "1.50") are not listed.
+	Changes []QuantizationChange
+}
+
+// Changed reports whether any field value was adjusted.
+func (r QuantizationReport) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+// QuantizeOrder rounds the price, stopPrice and quantity of an order to
+// legal values for market, using tickSize, stepSize and the asset
+// precisions. Every value is formatted with the exact number of decimals
+// the market expects.
+//
+// Parameters:
+//   - params: the order to quantize. It is not modified.
+//   - market: the market definition from exchangeInfo. When nil, params
+//     are returned unchanged with a report listing no changes.
+//   - opts: rounding directions. The zero value uses side-based price
+//     rounding and floor for quantities.
+//
+// Returns:
+//   - the quantized order.
+//   - a report listing every field whose value changed.
+//
+// Example:
+//
+//	params, report := tabdeal.QuantizeOrder(params, market, tabdeal.QuantizeOptions{})
+//	for _, ch := range report.Changes {
+//	    log.Printf("%s: %s -> %s", ch.Field, ch.From, ch.To)
+//	}
+func QuantizeOrder(params t.CreateOrderParams, market *t.MarketInformation, opts QuantizeOptions) (t.CreateOrderParams, QuantizationReport) {
+	if market == nil {
+		return params, QuantizationReport{Symbol: cmp.Or(params.Symbol, params.TabdealSymbol)}
+	}
+
+	priceMode := t.SideRounding(params.Side)
+	if opts.PriceRounding != nil {
+		priceMode = opts.PriceRounding(params.Side)
+	}
+
+	quantityMode := t.RoundFloor
+	if opts.QuantityRounding != nil {
+		quantityMode = opts.QuantityRounding(params.Side)
+	}
+
+	report := QuantizationReport{Symbol: market.Symbol}
+	apply := func(field string, value *t.Decimal, quantized t.Decimal) {
+		if !quantized.Equal(*value) {
+			report.Changes = append(report.Changes, QuantizationChange{Field: field, From: *value, To: quantized})
+		}
+		*value = quantized
+	}
+
+	if params.Price.IsPositive() {
+		apply("price", &params.Price, market.QuantizePrice(params.Price, priceMode))
+	}
+
+	if params.StopPrice.IsPositive() {
+		apply("stopPrice", &params.StopPrice, market.QuantizePrice(params.StopPrice, priceMode))
+	}
+
+	if params.Quantity.IsPositive() {
+		apply("quantity", &params.Quantity, market.QuantizeQuantity(params.Quantity, params.Type, quantityMode))
+	}
+
+	return params, report
+}
+
+// QuantizeOCOOrder is QuantizeOrder for both legs of an OCO order (see
+// CreateOCOOrderParams.Legs). The quantity follows the limit leg; a change
+// of the stop leg's limit price is reported as "stopLimitPrice".
+func QuantizeOCOOrder(params t.CreateOCOOrderParams, market *t.MarketInformation, opts QuantizeOptions) (t.CreateOCOOrderParams, QuantizationReport) {
+	limit, stop := params.Legs()
+	limit, report := QuantizeOrder(limit, market, opts)
+	stop, stopReport := QuantizeOrder(stop, market, opts)
+
+	params.Price = limit.Price
+	params.Quantity = limit.Quantity
+	params.StopPrice = stop.StopPrice
+	if params.StopLimitPrice.IsPositive() {
+		params.StopLimitPrice = stop.Price
+	}
+
+	for _, change := range stopReport.Changes {
+		switch change.Field {
+		case "stopPrice":
+			report.Changes = append(report.Changes, change)
+		case "price":
+			change.Field = "stopLimitPrice"
+			report.Changes = append(report.Changes, change)
+		}
+	}
+
+	return params, report
+}
+
+// quantizeOrder applies the client's QuantizeOptions to an order.
+func (c *Client) quantizeOrder(ctx context.Context, params t.CreateOrderParams) (t.CreateOrderParams, error) {
+	market, err := c.lookupMarket(ctx, params.BaseSymbolParams)
+	if err != nil {
+		return params, &GoTabdealError{Message: "failed to load market rules for quantization", Err: err}
+	}
+
+	quantized, report := QuantizeOrder(params, market, *c.Quantize)
+	if c.Quantize.OnQuantize != nil {
+		c.Quantize.OnQuantize(report)
+	}
+
+	return quantized, nil
+}
+
+// quantizeOCOOrder applies the client's QuantizeOptions to an OCO order.
+func (c *Client) quantizeOCOOrder(ctx context.Context, params t.CreateOCOOrderParams) (t.CreateOCOOrderParams, error) {
+	market, err := c.lookupMarket(ctx, params.BaseSymbolParams)
+	if err != nil {
+		return params, &GoTabdealError{Message: "failed to load market rules for quantization", Err: err}
+	}
+
+	quantized, report := QuantizeOCOOrder(params, market, *c.Quantize)
+	if c.Quantize.OnQuantize != nil {
+		c.Quantize.OnQuantize(report)
+	}
+
+	return quantized, nil
+}
@@ -0,0 +1,72 @@
+package tabdeal
+
+import (
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// offGridMarket has minimums that are not multiples of their steps, so the
+// legal values are minPrice + k·tickSize and minQty + k·stepSize.
+func offGridMarket() *t.MarketInformation {
+	return &t.MarketInformation{
+		Symbol:        "BTCIRT",
+		TabdealSymbol: "BTC_IRT",
+		Status:        t.MarketStatusTrading,
+		Filters: []t.Filter{
+			{FilterType: t.FilterTypePriceFilter, MinPrice: t.MustParseDecimal("0.00500000"), MaxPrice: t.MustParseDecimal("1000"), TickSize: t.MustParseDecimal("0.01000000")},
+			{FilterType: t.FilterTypeLotSize, MinQty: t.MustParseDecimal("0.25"), MaxQty: t.MustParseDecimal("100"), StepSize: t.MustParseDecimal("0.5")},
+		},
+	}
+}
+
+func TestQuantizeOrderPassesValidation(tt *testing.T) {
+	tests := []struct {
+		name     string
+		side     t.OrderSide
+		price    string
+		quantity string
+		want     [2]string
+	}{
+		{"buy rounds the price up", t.OrderSideBuy, "10.001", "3.9", [2]string{"10.005", "3.75"}},
+		{"sell rounds the price down", t.OrderSideSell, "10.014", "3.9", [2]string{"10.005", "3.75"}},
+		{"values on the grid are kept", t.OrderSideBuy, "10.015", "0.75", [2]string{"10.015", "0.75"}},
+		{"round values are moved onto the grid", t.OrderSideSell, "10", "4", [2]string{"9.995", "3.75"}},
+	}
+
+	market := offGridMarket()
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			params := t.CreateOrderParams{
+				BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+				Side:             tc.side,
+				Type:             t.OrderTypeLimit,
+				TimeInForce:      t.TimeInForceGTC,
+				Price:            t.MustParseDecimal(tc.price),
+				Quantity:         t.MustParseDecimal(tc.quantity),
+			}
+
+			quantized, _ := QuantizeOrder(params, market, QuantizeOptions{})
+			if got := [2]string{quantized.Price.String(), quantized.Quantity.String()}; got != tc.want {
+				tt.Errorf("price, quantity = %v, want %v", got, tc.want)
+			}
+			if violations := ValidateOrder(quantized, market, t.Decimal{}); len(violations) != 0 {
+				tt.Errorf("quantized order fails validation: %v", violations)
+			}
+		})
+	}
+}
+
+func TestQuantizeOrderNilMarket(tt *testing.T) {
+	params := t.CreateOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{TabdealSymbol: "BTC_IRT"},
+		Side:             t.OrderSideBuy,
+		Price:            t.MustParseDecimal("10.001"),
+		Quantity:         t.MustParseDecimal("3.9"),
+	}
+
+	quantized, report := QuantizeOrder(params, nil, QuantizeOptions{})
+	if quantized != params || report.Changed() || report.Symbol != "BTC_IRT" {
+		tt.Errorf("QuantizeOrder() = %+v, %+v, want params unchanged", quantized, report)
+	}
+}
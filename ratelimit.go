@@ -0,0 +1,399 @@
+package tabdeal
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// RateLimitMode selects what happens when a request does not fit into the
+// remaining rate-limit budget.
+type RateLimitMode int
+
+const (
+	// RateLimitBlock waits until enough budget is available, or until the
+	// request context is done.
+	RateLimitBlock RateLimitMode = iota
+
+	// RateLimitFailFast returns a *RateLimitError immediately instead of
+	// waiting.
+	RateLimitFailFast
+)
+
+// RateLimitOptions configures the client-side rate limiter.
+//
+// The limiter keeps two token buckets, one for public endpoints and one
+// for signed endpoints. Each request consumes tokens equal to its request
+// weight (see EndpointWeight), and each bucket refills continuously at
+// PublicWeight (or SignedWeight) tokens per Interval.
+//
+// The limiter also adapts to the server's view of the budget:
+//   - X-MBX-USED-WEIGHT-* headers lower the available tokens to what the
+//     server reports as still unused.
+//   - X-RateLimit-Limit / X-RateLimit-Remaining headers adjust capacity
+//     and available tokens.
+//   - A 429 or 418 response with Retry-After pauses the bucket until the
+//     requested time.
+type RateLimitOptions struct {
+	// Mode selects blocking or fail-fast behavior. Defaults to RateLimitBlock.
+	Mode RateLimitMode
+
+	// PublicWeight is the weight budget of unsigned endpoints per Interval.
+	// Defaults to 1200.
+	PublicWeight int
+
+	// SignedWeight is the weight budget of signed endpoints per Interval.
+	// Defaults to 1200.
+	SignedWeight int
+
+	// Interval is the window the budgets refer to. Defaults to one minute.
+	Interval time.Duration
+
+	// Weight overrides the per-endpoint weight table. When nil,
+	// EndpointWeight is used.
+	Weight func(method, path string, body interface{}) int
+}
+
+// RateLimitBucketStats describes the state of a single rate-limit bucket.
+type RateLimitBucketStats struct {
+	// Capacity is the maximum weight per interval.
+	Capacity float64
+
+	// Available is the weight that can be spent right now.
+	Available float64
+
+	// Utilization is the consumed share of the budget, in [0, 1].
+	Utilization float64
+
+	// PausedUntil is set while the bucket is paused by a Retry-After.
+	PausedUntil time.Time
+}
+
+// RateLimitStats is a point-in-time snapshot of the client rate limiter.
+type RateLimitStats struct {
+	Public RateLimitBucketStats
+	Signed RateLimitBucketStats
+}
+
+// EndpointWeight returns the request weight of an endpoint. It mirrors the
+// Binance-style weight table that Tabdeal's API follows.
+//
+// Weights:
+//   - /depth: 5 up to limit 100, 25 up to 500, 50 up to 1000, 250 above.
+//   - /allOrders, /myTrades, /exchangeInfo, /account: 20.
+//   - GET /order: 4. POST and DELETE /order: 1.
+//   - GET /openOrders: 6 with a symbol, 80 without. DELETE /openOrders: 1.
+//   - GET /orderList: 4. DELETE /orderList: 1. /openOrderList: 6.
+//   - /trades, /historicalTrades: 25.
+//   - /klines, /avgPrice, /aggTrades: 2.
+//   - /ticker/24hr: 2 with a symbol, 80 without.
+//   - /ticker/bookTicker: 2 with a symbol, 4 without.
+//   - Everything else: 1.
+func EndpointWeight(method, path string, body interface{}) int {
+	endpoint := path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		endpoint = path[i:]
+	}
+
+	switch endpoint {
+	case "/depth":
+		var limit int64
+		switch p := body.(type) {
+		case t.GetOrderBookParams:
+			limit = p.Limit
+		case *t.GetOrderBookParams:
+			if p != nil {
+				limit = p.Limit
+			}
+		}
+		switch {
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 25
+		case limit <= 1000:
+			return 50
+		default:
+			return 250
+		}
+	case "/allOrders", "/myTrades", "/exchangeInfo", "/account":
+		return 20
+	case "/order":
+		if method == http.MethodGet {
+			return 4
+		}
+		return 1
+	case "/orderList":
+		if method == http.MethodGet {
+			return 4
+		}
+		return 1
+	case "/openOrderList":
+		return 6
+	case "/openOrders":
+		if method != http.MethodGet {
+			return 1
+		}
+		if hasSymbol(body) {
+			return 6
+		}
+		return 80
+	case "/trades", "/historicalTrades":
+		return 25
+	case "/klines", "/avgPrice", "/aggTrades":
+		return 2
+	case "/24hr":
+		if hasSymbol(body) {
+			return 2
+		}
+		return 80
+	case "/bookTicker":
+		if hasSymbol(body) {
+			return 2
+		}
+		return 4
+	}
+
+	return 1
+}
+
+// hasSymbol reports whether body addresses a single market.
+func hasSymbol(body interface{}) bool {
+	switch p := body.(type) {
+	case t.GetTickerParams:
+		return p.Symbol != "" || p.TabdealSymbol != ""
+	case *t.GetTickerParams:
+		return p != nil && (p.Symbol != "" || p.TabdealSymbol != "")
+	case t.GetOpenOrdersParams:
+		return p.Symbol != "" || p.TabdealSymbol != ""
+	case *t.GetOpenOrdersParams:
+		return p != nil && (p.Symbol != "" || p.TabdealSymbol != "")
+	}
+	return false
+}
+
+// rateLimiter paces requests with one token bucket per security class.
+type rateLimiter struct {
+	mode   RateLimitMode
+	weight func(method, path string, body interface{}) int
+	public *tokenBucket
+	signed *tokenBucket
+}
+
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	publicWeight := opts.PublicWeight
+	if publicWeight <= 0 {
+		publicWeight = 1200
+	}
+
+	signedWeight := opts.SignedWeight
+	if signedWeight <= 0 {
+		signedWeight = 1200
+	}
+
+	weight := opts.Weight
+	if weight == nil {
+		weight = EndpointWeight
+	}
+
+	return &rateLimiter{
+		mode:   opts.Mode,
+		weight: weight,
+		public: newTokenBucket("public", float64(publicWeight), interval),
+		signed: newTokenBucket("signed", float64(signedWeight), interval),
+	}
+}
+
+func (l *rateLimiter) bucket(auth bool) *tokenBucket {
+	if auth {
+		return l.signed
+	}
+	return l.public
+}
+
+// acquire takes the weight of the request from the matching bucket.
+// endpoint is the request URL before parameters are encoded and signed.
+func (l *rateLimiter) acquire(ctx context.Context, method, endpoint string, auth bool, body interface{}) error {
+	path := endpoint
+	if parsed, err := url.Parse(endpoint); err == nil {
+		path = parsed.Path
+	}
+
+	return l.bucket(auth).take(ctx, float64(l.weight(method, path, body)), l.mode == RateLimitFailFast)
+}
+
+// observe adapts the bucket of a completed request to the rate-limit
+// headers of its response.
+func (l *rateLimiter) observe(auth bool, resp *http.Response) {
+	b := l.bucket(auth)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+
+	for name, values := range resp.Header {
+		if len(values) == 0 {
+			continue
+		}
+		lower := strings.ToLower(name)
+		value, err := strconv.ParseFloat(strings.TrimSpace(values[0]), 64)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(lower, "x-mbx-used-weight"):
+			if remaining := b.capacity - value; remaining < b.tokens {
+				b.tokens = max(remaining, 0)
+			}
+		case lower == "x-ratelimit-limit":
+			if value > 0 {
+				b.capacity = value
+				b.rate = value / float64(b.interval)
+				b.tokens = min(b.tokens, b.capacity)
+			}
+		case lower == "x-ratelimit-remaining":
+			if value < b.tokens {
+				b.tokens = max(value, 0)
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if until := time.Now().Add(wait); until.After(b.pausedUntil) {
+			b.pausedUntil = until
+		}
+		b.tokens = 0
+	}
+}
+
+func (l *rateLimiter) stats() RateLimitStats {
+	return RateLimitStats{
+		Public: l.public.stats(),
+		Signed: l.signed.stats(),
+	}
+}
+
+// tokenBucket is a continuously refilling token bucket.
+type tokenBucket struct {
+	mu          sync.Mutex
+	name        string
+	capacity    float64
+	tokens      float64
+	rate        float64 // tokens per nanosecond
+	interval    time.Duration
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(name string, capacity float64, interval time.Duration) *tokenBucket {
+	return &tokenBucket{
+		name:     name,
+		capacity: capacity,
+		tokens:   capacity,
+		rate:     capacity / float64(interval),
+		interval: interval,
+		last:     time.Now(),
+	}
+}
+
+// refill adds the tokens accrued since the last refill. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = min(b.capacity, b.tokens+float64(now.Sub(b.last))*b.rate)
+		b.last = now
+	}
+}
+
+// take removes n tokens, waiting for them unless failFast is set.
+func (b *tokenBucket) take(ctx context.Context, n float64, failFast bool) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.refill(now)
+
+		need := min(n, b.capacity)
+		var wait time.Duration
+		switch {
+		case now.Before(b.pausedUntil):
+			wait = b.pausedUntil.Sub(now)
+		case b.tokens >= need:
+			b.tokens -= need
+			b.mu.Unlock()
+			return nil
+		default:
+			wait = time.Duration((need - b.tokens) / b.rate)
+		}
+		b.mu.Unlock()
+
+		if failFast {
+			return &RateLimitError{
+				GoTabdealError: GoTabdealError{
+					Message: "rate limit budget exhausted for " + b.name + " endpoints",
+				},
+				Bucket:     b.name,
+				RetryAfter: wait,
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &RateLimitError{
+				GoTabdealError: GoTabdealError{
+					Message: "waiting for rate limit budget",
+					Err:     ctx.Err(),
+				},
+				Bucket:     b.name,
+				RetryAfter: wait,
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *tokenBucket) stats() RateLimitBucketStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+
+	return RateLimitBucketStats{
+		Capacity:    b.capacity,
+		Available:   b.tokens,
+		Utilization: 1 - b.tokens/b.capacity,
+		PausedUntil: b.pausedUntil,
+	}
+}
+
+// RateLimitStats returns the current state of the client-side rate limiter.
+// The second return value is false when no limiter is configured.
+//
+// Example:
+//
+//	if stats, ok := client.RateLimitStats(); ok {
+//	    fmt.Printf("signed budget used: %.0f%%\n", stats.Signed.Utilization*100)
+//	}
+func (c *Client) RateLimitStats() (RateLimitStats, bool) {
+	if c.limiter == nil {
+		return RateLimitStats{}, false
+	}
+	return c.limiter.stats(), true
+}
@@ -0,0 +1,46 @@
+package tabdeal
+
+import (
+	"net/http"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+func TestEndpointWeight(tt *testing.T) {
+	btc := t.BaseSymbolParams{Symbol: "BTCIRT"}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"depth default", http.MethodGet, "/r/api/v1/depth", t.GetOrderBookParams{BaseSymbolParams: btc}, 5},
+		{"depth 500", http.MethodGet, "/r/api/v1/depth", &t.GetOrderBookParams{Limit: 500}, 25},
+		{"depth 1000", http.MethodGet, "/r/api/v1/depth", t.GetOrderBookParams{Limit: 1000}, 50},
+		{"depth 5000", http.MethodGet, "/r/api/v1/depth", t.GetOrderBookParams{Limit: 5000}, 250},
+		{"nil depth pointer", http.MethodGet, "/r/api/v1/depth", (*t.GetOrderBookParams)(nil), 5},
+		{"open orders for a symbol", http.MethodGet, "/r/api/v1/openOrders", t.GetOpenOrdersParams{BaseSymbolParams: btc}, 6},
+		{"open orders pointer for a symbol", http.MethodGet, "/r/api/v1/openOrders", &t.GetOpenOrdersParams{BaseSymbolParams: t.BaseSymbolParams{TabdealSymbol: "BTC_IRT"}}, 6},
+		{"open orders for every symbol", http.MethodGet, "/r/api/v1/openOrders", t.GetOpenOrdersParams{}, 80},
+		{"open orders pointer for every symbol", http.MethodGet, "/r/api/v1/openOrders", &t.GetOpenOrdersParams{}, 80},
+		{"nil open orders pointer", http.MethodGet, "/r/api/v1/openOrders", (*t.GetOpenOrdersParams)(nil), 80},
+		{"cancel open orders", http.MethodDelete, "/api/v1/openOrders", t.CancelOrderParams{BaseSymbolParams: btc}, 1},
+		{"order status", http.MethodGet, "/r/api/v1/order", nil, 4},
+		{"place order", http.MethodPost, "/api/v1/order", nil, 1},
+		{"account", http.MethodGet, "/r/api/v1/account", nil, 20},
+		{"24hr ticker for a symbol", http.MethodGet, "/r/api/v1/ticker/24hr", &t.GetTickerParams{BaseSymbolParams: btc}, 2},
+		{"24hr ticker for every symbol", http.MethodGet, "/r/api/v1/ticker/24hr", t.GetTickerParams{}, 80},
+		{"book ticker for every symbol", http.MethodGet, "/r/api/v1/ticker/bookTicker", nil, 4},
+		{"klines", http.MethodGet, "/r/api/v1/klines", nil, 2},
+		{"unlisted", http.MethodGet, "/r/api/v1/time", nil, 1},
+	}
+
+	for _, tc := range tests {
+		if got := EndpointWeight(tc.method, tc.path, tc.body); got != tc.want {
+			tt.Errorf("EndpointWeight(%s) = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
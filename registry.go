@@ -0,0 +1,461 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// SymbolRegistryOptions configures a SymbolRegistry.
+type SymbolRegistryOptions struct {
+	// RefreshInterval is how old the cached exchangeInfo may get. Lookups
+	// on a stale registry start a background refresh, and Start refreshes
+	// on this interval in the background. Defaults to ten minutes.
+	RefreshInterval time.Duration
+
+	// RetryInterval is how long stale Lookups wait after a failed refresh
+	// before starting another one, so an exchangeInfo outage is not
+	// retried on every lookup. Defaults to 30 seconds.
+	RetryInterval time.Duration
+
+	// RefreshTimeout bounds every exchangeInfo request. A refresh is
+	// shared by all callers waiting for it, so it runs apart from their
+	// contexts and ends only on success, failure or this timeout.
+	// Defaults to 30 seconds.
+	RefreshTimeout time.Duration
+}
+
+// MarketChangeKind is a bit set describing what changed about a market.
+type MarketChangeKind int
+
+const (
+	// MarketAdded is set when a market appears for the first time.
+	MarketAdded MarketChangeKind = 1 << iota
+
+	// MarketRemoved is set when a market disappears from exchangeInfo.
+	MarketRemoved
+
+	// MarketStatusChanged is set when Status changes, e.g. TRADING → BREAK.
+	MarketStatusChanged
+
+	// MarketFiltersChanged is set when any filter changes.
+	MarketFiltersChanged
+
+	// MarketPermissionsChanged is set when Permissions, OrderTypes or the
+	// trading flags change.
+	MarketPermissionsChanged
+)
+
+// Has reports whether k contains kind.
+func (k MarketChangeKind) Has(kind MarketChangeKind) bool {
+	return k&kind != 0
+}
+
+// MarketChange is emitted by a SymbolRegistry when a refresh detects a
+// difference in a market's definition.
+type MarketChange struct {
+	// Symbol is the compact symbol of the market, e.g. "BTCIRT".
+	Symbol string
+
+	// Kind describes what changed.
+	Kind MarketChangeKind
+
+	// Old is the previous definition; nil for MarketAdded.
+	Old *t.MarketInformation
+
+	// New is the current definition; nil for MarketRemoved.
+	New *t.MarketInformation
+}
+
+// SymbolRegistry is a cached, concurrency-safe index of exchangeInfo.
+//
+// It loads GET /exchangeInfo once and indexes every market by both Symbol
+// ("BTCIRT") and TabdealSymbol ("BTC_IRT"), case-insensitively. Reads never
+// block on the network once loaded: a stale registry keeps serving its
+// snapshot while it refreshes in the background. Concurrent refreshes are
+// collapsed into a single request.
+//
+// Every refresh after the first compares the new definitions with the
+// cached ones and emits a MarketChange to subscribers for each market
+// that was added, removed, or changed status, filters or permissions.
+type SymbolRegistry struct {
+	client *Client
+	opts   SymbolRegistryOptions
+
+	mu       sync.RWMutex
+	markets  []*t.MarketInformation
+	index    map[string]*t.MarketInformation
+	loadedAt time.Time
+
+	flightMu sync.Mutex
+	flight   *registryFlight
+	failedAt time.Time
+
+	subsMu sync.Mutex
+	subs   map[chan MarketChange]struct{}
+}
+
+// registryFlight is a refresh in progress that other callers can join.
+type registryFlight struct {
+	done chan struct{}
+	err  error
+}
+
+// NewSymbolRegistry creates an empty registry backed by client. Nothing is
+// loaded until the first Lookup, Refresh or Start.
+//
+// Every Client already owns a registry as Client.Symbols; create separate
+// registries only when different refresh settings are needed.
+func NewSymbolRegistry(client *Client, opts SymbolRegistryOptions) *SymbolRegistry {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 10 * time.Minute
+	}
+	if opts.RefreshTimeout <= 0 {
+		opts.RefreshTimeout = 30 * time.Second
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 30 * time.Second
+	}
+
+	return &SymbolRegistry{
+		client: client,
+		opts:   opts,
+		subs:   make(map[chan MarketChange]struct{}),
+	}
+}
+
+// Get returns the cached market for a symbol in either "BTCIRT" or
+// "BTC_IRT" form. It never performs I/O and reports false when the
+// registry is not loaded or the symbol is unknown.
+func (r *SymbolRegistry) Get(symbol string) (*t.MarketInformation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	market, ok := r.index[strings.ToUpper(symbol)]
+	return market, ok
+}
+
+// Lookup returns the market for a symbol in either format. An empty
+// registry is loaded first; one older than RefreshInterval answers from
+// its snapshot and refreshes in the background, at most once per
+// RetryInterval while refreshes keep failing.
+//
+// Errors:
+//   - the initial load failed.
+//   - "unknown market" when the symbol is not listed.
+func (r *SymbolRegistry) Lookup(ctx context.Context, symbol string) (*t.MarketInformation, error) {
+	r.mu.RLock()
+	loadedAt := r.loadedAt
+	r.mu.RUnlock()
+
+	if loadedAt.IsZero() {
+		if err := r.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	} else if time.Since(loadedAt) > r.opts.RefreshInterval {
+		r.refreshStale(ctx)
+	}
+
+	market, ok := r.Get(symbol)
+	if !ok {
+		return nil, &GoTabdealError{Message: fmt.Sprintf("unknown market %q", symbol)}
+	}
+
+	return market, nil
+}
+
+// ResolveMarket converts a symbol in either "BTCIRT" or "BTC_IRT" form into
+// a types.Market using exchangeInfo, so pairs whose compact symbol is
+// ambiguous are split the way Tabdeal defines them. Symbols that are not
+// listed are split on the quote assets seen in exchangeInfo plus
+// types.KnownQuoteAssets.
+//
+// Example:
+//
+//	m, _ := client.Symbols.ResolveMarket(ctx, "BTCUSDT")
+//	fmt.Println(m.Base, m.Quote, m.TabdealSymbol()) // BTC USDT BTC_USDT
+func (r *SymbolRegistry) ResolveMarket(ctx context.Context, symbol string) (t.Market, error) {
+	market, err := r.Lookup(ctx, symbol)
+	if err == nil {
+		return market.Market(), nil
+	}
+
+	if r.LoadedAt().IsZero() {
+		return t.Market{}, err
+	}
+
+	return t.ParseMarketWithQuotes(symbol, r.QuoteAssets())
+}
+
+// QuoteAssets returns every quote asset listed in exchangeInfo together
+// with types.KnownQuoteAssets, without duplicates.
+func (r *SymbolRegistry) QuoteAssets() []string {
+	quotes := slices.Clone(t.KnownQuoteAssets)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, market := range r.markets {
+		quote := strings.ToUpper(market.QuoteAsset)
+		if quote != "" && !slices.Contains(quotes, quote) {
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return quotes
+}
+
+// Markets returns a snapshot of all cached markets in exchangeInfo order.
+func (r *SymbolRegistry) Markets() []*t.MarketInformation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.markets)
+}
+
+// LoadedAt returns when exchangeInfo was last loaded successfully, or the
+// zero time if it never was.
+func (r *SymbolRegistry) LoadedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.loadedAt
+}
+
+// Refresh reloads exchangeInfo. Concurrent calls share a single request:
+// callers arriving while a refresh is in flight wait for it and receive
+// its result (or return early if their own ctx ends).
+//
+// The shared request keeps the values of the ctx that started it but not
+// its cancellation, so a caller giving up does not fail the others; it is
+// bounded by RefreshTimeout instead.
+func (r *SymbolRegistry) Refresh(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.flightMu.Lock()
+	f := r.joinFlight(ctx)
+	r.flightMu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refreshStale starts a background refresh for a stale Lookup unless one
+// is in flight or the last one failed less than RetryInterval ago.
+func (r *SymbolRegistry) refreshStale(ctx context.Context) {
+	r.flightMu.Lock()
+	defer r.flightMu.Unlock()
+
+	if r.flight == nil && time.Since(r.failedAt) >= r.opts.RetryInterval {
+		r.joinFlight(ctx)
+	}
+}
+
+// joinFlight returns the refresh in flight, starting one if there is
+// none. The caller must hold flightMu.
+func (r *SymbolRegistry) joinFlight(ctx context.Context) *registryFlight {
+	if r.flight != nil {
+		return r.flight
+	}
+
+	f := &registryFlight{done: make(chan struct{})}
+	r.flight = f
+
+	go func() {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.opts.RefreshTimeout)
+		defer cancel()
+
+		f.err = r.load(loadCtx)
+
+		r.flightMu.Lock()
+		r.flight = nil
+		if f.err != nil {
+			r.failedAt = time.Now()
+		}
+		r.flightMu.Unlock()
+		close(f.done)
+	}()
+
+	return f
+}
+
+// load fetches exchangeInfo, swaps the index and emits change events.
+func (r *SymbolRegistry) load(ctx context.Context) error {
+	info, err := r.client.GetMarketInformationCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	var markets []*t.MarketInformation
+	if info != nil {
+		for _, market := range *info {
+			if market != nil {
+				markets = append(markets, market)
+			}
+		}
+	}
+
+	index := make(map[string]*t.MarketInformation, 2*len(markets))
+	for _, market := range markets {
+		if market.Symbol != "" {
+			index[strings.ToUpper(market.Symbol)] = market
+		}
+		if market.TabdealSymbol != "" {
+			index[strings.ToUpper(market.TabdealSymbol)] = market
+		}
+	}
+
+	r.mu.Lock()
+	previous := r.markets
+	first := r.loadedAt.IsZero()
+	r.markets = markets
+	r.index = index
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+
+	if !first {
+		for _, change := range diffMarkets(previous, markets) {
+			r.publish(change)
+		}
+	}
+
+	return nil
+}
+
+// Start refreshes the registry immediately and then every RefreshInterval
+// until ctx is done. It returns the error of the initial refresh; the
+// background loop runs regardless.
+func (r *SymbolRegistry) Start(ctx context.Context) error {
+	err := r.Refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(r.opts.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.Refresh(ctx)
+			}
+		}
+	}()
+
+	return err
+}
+
+// Subscribe returns a channel receiving MarketChange events and a function
+// that unsubscribes and closes the channel. buffer sets the channel
+// capacity (default 64). Events are delivered without blocking the
+// refresh; a subscriber whose buffer is full misses the event.
+//
+// Example:
+//
+//	changes, stop := client.Symbols.Subscribe(0)
+//	defer stop()
+//	for ch := range changes {
+//	    if ch.Kind.Has(tabdeal.MarketStatusChanged) {
+//	        log.Printf("%s is now %s", ch.Symbol, ch.New.Status)
+//	    }
+//	}
+func (r *SymbolRegistry) Subscribe(buffer int) (<-chan MarketChange, func()) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+
+	ch := make(chan MarketChange, buffer)
+
+	r.subsMu.Lock()
+	r.subs[ch] = struct{}{}
+	r.subsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.subsMu.Lock()
+			delete(r.subs, ch)
+			r.subsMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (r *SymbolRegistry) publish(change MarketChange) {
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	for ch := range r.subs {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// diffMarkets compares two exchangeInfo snapshots by Symbol.
+func diffMarkets(previous, current []*t.MarketInformation) []MarketChange {
+	old := make(map[string]*t.MarketInformation, len(previous))
+	for _, market := range previous {
+		old[market.Symbol] = market
+	}
+
+	var changes []MarketChange
+	for _, market := range current {
+		before, ok := old[market.Symbol]
+		delete(old, market.Symbol)
+
+		if !ok {
+			changes = append(changes, MarketChange{Symbol: market.Symbol, Kind: MarketAdded, New: market})
+			continue
+		}
+
+		var kind MarketChangeKind
+		if before.Status != market.Status {
+			kind |= MarketStatusChanged
+		}
+		if !sameJSON(before.Filters, market.Filters) {
+			kind |= MarketFiltersChanged
+		}
+		if !slices.Equal(before.Permissions, market.Permissions) ||
+			!slices.Equal(before.OrderTypes, market.OrderTypes) ||
+			before.IsSpotTradingAllowed != market.IsSpotTradingAllowed ||
+			before.IsMarginTradingAllowed != market.IsMarginTradingAllowed ||
+			before.OcoAllowed != market.OcoAllowed ||
+			before.AllowTrailingStop != market.AllowTrailingStop {
+			kind |= MarketPermissionsChanged
+		}
+
+		if kind != 0 {
+			changes = append(changes, MarketChange{Symbol: market.Symbol, Kind: kind, Old: before, New: market})
+		}
+	}
+
+	for _, market := range previous {
+		if _, removed := old[market.Symbol]; removed {
+			changes = append(changes, MarketChange{Symbol: market.Symbol, Kind: MarketRemoved, Old: market})
+		}
+	}
+
+	return changes
+}
+
+// sameJSON compares two values by their JSON encoding, which treats
+// Decimal values with identical wire representation as equal.
+func sameJSON(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,128 @@
+package tabdeal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshOutlivesFirstCaller(tt *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`[{"symbol":"BTCIRT","tabdealSymbol":"BTC_IRT"}]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() { firstErr <- client.Symbols.Refresh(first) }()
+	waitFor(tt, "the exchangeInfo request", func() bool { return requests.Load() == 1 })
+
+	secondErr := make(chan error, 1)
+	go func() { secondErr <- client.Symbols.Refresh(context.Background()) }()
+
+	// The first caller gives up; the shared request must go on.
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		tt.Fatalf("first Refresh() = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	select {
+	case err := <-secondErr:
+		if err != nil {
+			tt.Fatalf("second Refresh() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		tt.Fatal("second Refresh() did not return")
+	}
+
+	if _, ok := client.Symbols.Get("btc_irt"); !ok || requests.Load() != 1 {
+		tt.Errorf("market cached %v after %d requests, want one shared request", ok, requests.Load())
+	}
+}
+
+func TestRefreshTimeout(tt *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	registry := NewSymbolRegistry(client, SymbolRegistryOptions{RefreshTimeout: 50 * time.Millisecond})
+
+	if err := registry.Refresh(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		tt.Errorf("Refresh() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestLookupServesStaleCacheWhileRefreshFails(tt *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			_, _ = w.Write([]byte(`[{"symbol":"BTCIRT","tabdealSymbol":"BTC_IRT"}]`))
+			return
+		}
+		// Every refresh after the first hangs until it times out.
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	registry := NewSymbolRegistry(client, SymbolRegistryOptions{
+		RefreshInterval: time.Nanosecond,
+		RefreshTimeout:  50 * time.Millisecond,
+	})
+	if err := registry.Refresh(context.Background()); err != nil {
+		tt.Fatal(err)
+	}
+
+	lookup := func() {
+		tt.Helper()
+		start := time.Now()
+		market, err := registry.Lookup(context.Background(), "BTCIRT")
+		if err != nil || market.TabdealSymbol != "BTC_IRT" {
+			tt.Fatalf("Lookup() = %v, %v, want the cached market", market, err)
+		}
+		if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+			tt.Errorf("Lookup() waited %v for the refresh", elapsed)
+		}
+	}
+
+	lookup()
+	waitFor(tt, "the failed refresh", func() bool {
+		registry.flightMu.Lock()
+		defer registry.flightMu.Unlock()
+		return !registry.failedAt.IsZero()
+	})
+
+	// Within RetryInterval of the failure no further request is made.
+	lookup()
+	lookup()
+	if n := requests.Load(); n != 2 {
+		tt.Errorf("%d exchangeInfo requests, want the load and one refresh", n)
+	}
+}
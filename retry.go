@@ -0,0 +1,199 @@
+package tabdeal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// RetryPolicy configures how the client retries requests that fail with a
+// transient error.
+//
+// A request is retried only when both conditions hold:
+//   - the request itself is retryable according to Retryable (or
+//     DefaultRetryable when Retryable is nil), and
+//   - the failure is transient: a network error while sending the request
+//     or reading the response, HTTP 429, or any HTTP 5xx.
+//
+// Delays grow exponentially from InitialBackoff by Multiplier up to
+// MaxBackoff, and each delay is randomized by ±Jitter. A Retry-After
+// header sent by Tabdeal takes precedence when it asks for a longer wait.
+// Waiting always honors the request context.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values <= 1 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is the growth factor applied to the delay after each
+	// attempt. Values < 1 are treated as 1 (constant backoff).
+	Multiplier float64
+
+	// Jitter is the relative randomization applied to every delay, in the
+	// range [0, 1]. A value of 0.2 spreads delays over ±20%.
+	Jitter float64
+
+	// Retryable decides whether a request may be retried at all. method is
+	// the HTTP method, path the URL path (e.g. "/r/api/v1/depth"), and body
+	// the parameter value passed to Request. When nil, DefaultRetryable is
+	// used.
+	Retryable func(method, path string, body interface{}) bool
+}
+
+// DefaultRetryPolicy returns the policy used when ClientOptions.Retry is
+// nil: three attempts, 200ms initial backoff doubling up to 5s, with 20%
+// jitter, and DefaultRetryable as the request classifier.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// DefaultRetryable is the default request classifier of RetryPolicy.
+//
+// Behavior:
+//   - GET requests (market data and account reads) are retryable.
+//   - CreateOrder is retryable only when NewClientOrderId is set. Before
+//     every retry the client looks the order up with GetOrderStatus by
+//     OrigClientOrderId, so an order that reached the exchange is returned
+//     instead of being placed twice. Only an "order does not exist"
+//     answer lets the retry go ahead; any other lookup failure ends it with
+//     an *OrderUncertainError.
+//   - Every other mutating request (cancels, bulk cancels) is not retried.
+func DefaultRetryable(method, path string, body interface{}) bool {
+	if method == http.MethodGet {
+		return true
+	}
+
+	switch p := body.(type) {
+	case t.CreateOrderParams:
+		return p.NewClientOrderId != ""
+	case *t.CreateOrderParams:
+		return p != nil && p.NewClientOrderId != ""
+	}
+
+	return false
+}
+
+// retryable reports whether the request may be retried under this policy.
+func (p *RetryPolicy) retryable(method, rawURL string, body interface{}) bool {
+	if p == nil || p.MaxAttempts <= 1 {
+		return false
+	}
+
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.Path
+	}
+
+	if p.Retryable != nil {
+		return p.Retryable(method, path, body)
+	}
+
+	return DefaultRetryable(method, path, body)
+}
+
+// backoff returns the randomized delay to wait after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		delay *= 1 - jitter + 2*jitter*rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// isTransient reports whether err is worth another attempt.
+func isTransient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Operation == "sending request" || reqErr.Operation == "reading response"
+	}
+
+	return false
+}
+
+// withRetry runs attempt until it succeeds, fails permanently, the policy
+// runs out of attempts or ctx is done. attempt receives the 1-based attempt
+// number. The error of the last attempt is returned.
+func (c *Client) withRetry(ctx context.Context, retryable bool, attempt func(n int) error) error {
+	policy := c.RetryPolicy
+	if !retryable || policy == nil || policy.MaxAttempts <= 1 {
+		return attempt(1)
+	}
+
+	for n := 1; ; n++ {
+		err := attempt(n)
+		if err == nil || n >= policy.MaxAttempts || !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := policy.backoff(n)
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter decodes a Retry-After header given in seconds or as an
+// HTTP date. It returns 0 when the header is absent or malformed.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,316 @@
+package tabdeal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// fastRetry is DefaultRetryPolicy without the waiting.
+func fastRetry() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+	return policy
+}
+
+// scriptServer answers each request with the next reply for its method and
+// path, repeating the last one, and records the requests it saw.
+type scriptServer struct {
+	mu       sync.Mutex
+	replies  map[string][]reply
+	requests []string
+}
+
+type reply struct {
+	status int
+	body   string
+}
+
+func (s *scriptServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+	s.requests = append(s.requests, key)
+	replies := s.replies[key]
+	if len(replies) == 0 {
+		s.mu.Unlock()
+		http.NotFound(w, r)
+		return
+	}
+	next := replies[0]
+	if len(replies) > 1 {
+		s.replies[key] = replies[1:]
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(next.status)
+	_, _ = w.Write([]byte(next.body))
+}
+
+func (s *scriptServer) seen() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func newScriptClient(tt *testing.T, replies map[string][]reply) (*Client, *scriptServer) {
+	tt.Helper()
+
+	script := &scriptServer{replies: replies}
+	srv := httptest.NewServer(script)
+	tt.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL, Retry: fastRetry()})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return client, script
+}
+
+const placedOrder = `{"symbol":"BTCIRT","orderId":7,"clientOrderId":"grid-1","status":"NEW"}`
+
+func limitOrder(clientOrderId string) t.CreateOrderParams {
+	return t.CreateOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideBuy,
+		Type:             t.OrderTypeLimit,
+		TimeInForce:      t.TimeInForceGTC,
+		Quantity:         t.MustParseDecimal("0.01"),
+		Price:            t.MustParseDecimal("100"),
+		NewClientOrderId: clientOrderId,
+	}
+}
+
+func TestRetryTransientThenSucceeds(tt *testing.T) {
+	client, script := newScriptClient(tt, map[string][]reply{
+		"GET /r/api/v1/time": {{503, ""}, {500, `{"code":-1000,"msg":"internal"}`}, {200, `{"serverTime":42}`}},
+	})
+
+	var out struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := client.Request(http.MethodGet, client.createApiURI(http.MethodGet, "/time"), false, nil, &out); err != nil {
+		tt.Fatal(err)
+	}
+	if out.ServerTime != 42 || len(script.seen()) != 3 {
+		tt.Errorf("serverTime %d after %d requests, want 42 after 3", out.ServerTime, len(script.seen()))
+	}
+}
+
+func TestRetryGivesUp(tt *testing.T) {
+	tests := []struct {
+		name     string
+		replies  []reply
+		status   int
+		requests int
+	}{
+		{"after MaxAttempts", []reply{{502, ""}}, 502, 3},
+		{"on a client error", []reply{{400, `{"code":-1100,"msg":"bad"}`}, {200, "{}"}}, 400, 1},
+		{"on 429 after MaxAttempts", []reply{{429, ""}}, 429, 3},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			client, script := newScriptClient(tt, map[string][]reply{"GET /r/api/v1/time": tc.replies})
+
+			err := client.Request(http.MethodGet, client.createApiURI(http.MethodGet, "/time"), false, nil, nil)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+				tt.Fatalf("Request() = %v, want HTTP %d", err, tc.status)
+			}
+			if n := len(script.seen()); n != tc.requests {
+				tt.Errorf("%d requests, want %d", n, tc.requests)
+			}
+		})
+	}
+}
+
+func TestRetryHonorsContext(tt *testing.T) {
+	client, script := newScriptClient(tt, map[string][]reply{"GET /r/api/v1/time": {{503, ""}}})
+	client.RetryPolicy.InitialBackoff = time.Hour
+	client.RetryPolicy.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.RequestCtx(ctx, http.MethodGet, client.createApiURI(http.MethodGet, "/time"), false, nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		tt.Fatalf("RequestCtx() = %v, want the HTTP 503 of the last attempt", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || len(script.seen()) != 1 {
+		tt.Errorf("returned after %v and %d requests, want one request and no backoff wait", elapsed, len(script.seen()))
+	}
+}
+
+func TestCreateOrderWithoutClientOrderIdIsNotRetried(tt *testing.T) {
+	client, script := newScriptClient(tt, map[string][]reply{
+		"POST /api/v1/order": {{502, ""}, {200, placedOrder}},
+	})
+
+	_, err := client.CreateOrder(limitOrder(""))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 502 {
+		tt.Fatalf("CreateOrder() = %v, want HTTP 502", err)
+	}
+	if got := script.seen(); len(got) != 1 {
+		tt.Errorf("requests %q, want a single POST", got)
+	}
+}
+
+func TestCreateOrderRetryReconciles(tt *testing.T) {
+	tests := []struct {
+		name      string
+		lookup    reply
+		wantOrder int64
+		uncertain bool
+		requests  []string
+	}{
+		{
+			name:      "order reached the exchange",
+			lookup:    reply{200, placedOrder},
+			wantOrder: 7,
+			requests:  []string{"POST /api/v1/order", "GET /r/api/v1/order"},
+		},
+		{
+			name:      "order was lost",
+			lookup:    reply{400, `{"code":-2013,"msg":"Order does not exist."}`},
+			wantOrder: 8,
+			requests:  []string{"POST /api/v1/order", "GET /r/api/v1/order", "POST /api/v1/order"},
+		},
+		{
+			name:      "lookup fails",
+			lookup:    reply{400, `{"code":-1021,"msg":"Timestamp outside recvWindow."}`},
+			uncertain: true,
+			requests:  []string{"POST /api/v1/order", "GET /r/api/v1/order"},
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			client, script := newScriptClient(tt, map[string][]reply{
+				"POST /api/v1/order":  {{504, ""}, {200, strings.Replace(placedOrder, `"orderId":7`, `"orderId":8`, 1)}},
+				"GET /r/api/v1/order": {tc.lookup},
+			})
+
+			resp, err := client.CreateOrder(limitOrder("grid-1"))
+
+			var uncertain *OrderUncertainError
+			switch {
+			case tc.uncertain:
+				if !errors.As(err, &uncertain) || uncertain.ClientOrderId != "grid-1" {
+					tt.Errorf("CreateOrder() = %v, want an *OrderUncertainError for grid-1", err)
+				}
+			case err != nil:
+				tt.Errorf("CreateOrder() = %v", err)
+			case resp.OrderId != tc.wantOrder:
+				tt.Errorf("CreateOrder() returned order %d, want %d", resp.OrderId, tc.wantOrder)
+			}
+
+			if got := script.seen(); strings.Join(got, ", ") != strings.Join(tc.requests, ", ") {
+				tt.Errorf("requests %q, want %q", got, tc.requests)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(tt *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 300 * time.Millisecond, 3: 900 * time.Millisecond, 4: time.Second, 10: time.Second} {
+		if got := policy.backoff(attempt); got != want {
+			tt.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	policy.Multiplier = 0.5
+	if got := policy.backoff(3); got != 100*time.Millisecond {
+		tt.Errorf("backoff(3) with Multiplier < 1 = %v, want the constant 100ms", got)
+	}
+
+	policy.Multiplier, policy.Jitter = 1, 0.2
+	low, high := time.Hour, time.Duration(0)
+	for range 1000 {
+		got := policy.backoff(1)
+		low, high = min(low, got), max(high, got)
+	}
+	if low < 80*time.Millisecond || high > 120*time.Millisecond || high-low < 20*time.Millisecond {
+		tt.Errorf("jittered delays span [%v, %v], want a spread within [80ms, 120ms]", low, high)
+	}
+}
+
+func TestIsTransient(tt *testing.T) {
+	requestErr := func(operation string) error {
+		return &RequestError{GoTabdealError: GoTabdealError{Message: operation}, Operation: operation}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"HTTP 429", &APIError{StatusCode: 429}, true},
+		{"HTTP 500", &APIError{StatusCode: 500}, true},
+		{"HTTP 503", &APIError{StatusCode: 503}, true},
+		{"HTTP 400", &APIError{StatusCode: 400}, false},
+		{"HTTP 404", &APIError{StatusCode: 404}, false},
+		{"sending request", requestErr("sending request"), true},
+		{"reading response", requestErr("reading response"), true},
+		{"creating request", requestErr("creating request"), false},
+		{"canceled", context.Canceled, false},
+		{"deadline", &RequestError{GoTabdealError: GoTabdealError{Err: context.DeadlineExceeded}, Operation: "sending request"}, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tc := range tests {
+		if got := isTransient(tc.err); got != tc.want {
+			tt.Errorf("isTransient(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultRetryable(tt *testing.T) {
+	named := limitOrder("grid-1")
+
+	tests := []struct {
+		name   string
+		method string
+		body   interface{}
+		want   bool
+	}{
+		{"GET", http.MethodGet, nil, true},
+		{"named order", http.MethodPost, named, true},
+		{"named order pointer", http.MethodPost, &named, true},
+		{"unnamed order", http.MethodPost, limitOrder(""), false},
+		{"nil order pointer", http.MethodPost, (*t.CreateOrderParams)(nil), false},
+		{"cancel", http.MethodDelete, t.CancelOrderParams{OrderId: 7}, false},
+	}
+
+	for _, tc := range tests {
+		if got := DefaultRetryable(tc.method, "/api/v1/order", tc.body); got != tc.want {
+			tt.Errorf("DefaultRetryable(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(tt *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		tt.Errorf("parseRetryAfter(3) = %v", got)
+	}
+	if got := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); got < 58*time.Second || got > time.Minute {
+		tt.Errorf("parseRetryAfter(date) = %v, want about a minute", got)
+	}
+	for _, value := range []string{"", "0", "-1", "soon", "Mon, 01 Jan 2001 00:00:00 GMT"} {
+		if got := parseRetryAfter(value); got != 0 {
+			tt.Errorf("parseRetryAfter(%q) = %v, want 0", value, got)
+		}
+	}
+}
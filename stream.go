@@ -0,0 +1,810 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/darhelm/go-tabdeal/internal/websocket"
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// StreamUrl is the combined-stream WebSocket endpoint of Tabdeal's market
+// data feed. Messages arrive wrapped as {"stream": "...", "data": {...}}.
+const StreamUrl = "wss://api1.tabdeal.org/stream"
+
+// StreamOverflow selects what a Subscription does when its channel buffer
+// is full because the consumer is not keeping up.
+type StreamOverflow int
+
+const (
+	// StreamDropOldest discards the oldest buffered event to make room for
+	// the new one. Consumers always see the most recent data.
+	StreamDropOldest StreamOverflow = iota
+
+	// StreamDropNewest discards the incoming event and keeps the buffer.
+	StreamDropNewest
+
+	// StreamBlock waits until the consumer makes room. A slow consumer
+	// stalls every subscription of the Stream and eventually the
+	// connection, so use it only when no event may be lost.
+	StreamBlock
+)
+
+// StreamState is the connection state of a Stream.
+type StreamState int
+
+const (
+	StreamDisconnected StreamState = iota
+	StreamConnecting
+	StreamConnected
+	StreamReconnecting
+	StreamClosed
+)
+
+// String returns the state name, e.g. "connected".
+func (s StreamState) String() string {
+	switch s {
+	case StreamDisconnected:
+		return "disconnected"
+	case StreamConnecting:
+		return "connecting"
+	case StreamConnected:
+		return "connected"
+	case StreamReconnecting:
+		return "reconnecting"
+	case StreamClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// StreamOptions configures a Stream.
+type StreamOptions struct {
+	// Url is the WebSocket endpoint. Defaults to StreamUrl.
+	Url string
+
+	// HandshakeTimeout bounds every dial and opening handshake. Defaults
+	// to ten seconds.
+	HandshakeTimeout time.Duration
+
+	// PingInterval is how often a ping is sent. Defaults to 20 seconds.
+	PingInterval time.Duration
+
+	// PongTimeout is how long the connection may stay silent after a ping
+	// before it is considered dead and replaced. Defaults to ten seconds.
+	PongTimeout time.Duration
+
+	// Reconnect sets the delays between reconnection attempts. MaxAttempts
+	// of 0 retries forever; after MaxAttempts consecutive failures the
+	// stream is closed. Defaults to DefaultReconnectPolicy().
+	Reconnect *RetryPolicy
+
+	// Buffer is the channel capacity of each subscription. Defaults to 256.
+	Buffer int
+
+	// Overflow selects the backpressure behavior of full subscriptions.
+	// Defaults to StreamDropOldest.
+	Overflow StreamOverflow
+
+	// OnStateChange, when set, is called on every connection state change.
+	OnStateChange func(StreamState)
+
+	// OnError, when set, receives connection failures, rejected
+	// resubscriptions and events that could not be decoded.
+	OnError func(error)
+}
+
+// DefaultReconnectPolicy returns the reconnection policy used when
+// StreamOptions.Reconnect is nil: unlimited attempts, 500ms initial delay
+// doubling up to 30s, with 20% jitter.
+func DefaultReconnectPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Stream is a market data WebSocket client.
+//
+// A Stream multiplexes any number of subscriptions over one connection.
+// Each subscription delivers typed events on its own buffered channel,
+// so a slow consumer of one channel does not delay the others (unless
+// StreamBlock is selected).
+//
+// Behavior:
+//   - Pings are sent every PingInterval; a connection that stays silent
+//     for PingInterval+PongTimeout is dropped and replaced.
+//   - Lost connections are re-established with backoff, and every active
+//     subscription is sent again in a single SUBSCRIBE request.
+//   - Events published while disconnected are lost. Consumers that need
+//     gap-free data, such as order books, must check sequence numbers.
+//
+// Example:
+//
+//	stream := tabdeal.NewStream(tabdeal.StreamOptions{})
+//	if err := stream.Connect(ctx); err != nil {
+//	    return err
+//	}
+//	defer stream.Close()
+//
+//	trades, err := stream.SubscribeTrades(ctx, "BTCIRT")
+//	for trade := range trades.C() {
+//	    fmt.Println(trade.Price, trade.Quantity)
+//	}
+type Stream struct {
+	opts StreamOptions
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	state   StreamState
+	subs    map[string][]streamSubscriber
+	control map[string]*streamControl
+	pending map[int64]chan error
+	nextId  int64
+	started bool
+
+	reconnects atomic.Uint64
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// streamSubscriber is the type-erased side of a Subscription.
+type streamSubscriber interface {
+	deliver(data json.RawMessage)
+	close(err error)
+}
+
+// streamControl orders the SUBSCRIBE and UNSUBSCRIBE requests of one
+// stream name, so an UNSUBSCRIBE for the last subscriber cannot overtake
+// the SUBSCRIBE of a new first one. refs counts holders and waiters.
+type streamControl struct {
+	sem  chan struct{}
+	refs int
+}
+
+// streamRequest is a SUBSCRIBE / UNSUBSCRIBE control message.
+type streamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	Id     int64    `json:"id"`
+}
+
+// streamMessage is any message received from the server: an event
+// wrapped with its stream name, or the response to a streamRequest.
+type streamMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+	Id     *int64          `json:"id"`
+	Error  *struct {
+		Code int16  `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+// NewStream creates a Stream. No connection is made until Connect.
+func NewStream(opts StreamOptions) *Stream {
+	if opts.Url == "" {
+		opts.Url = StreamUrl
+	}
+	if opts.HandshakeTimeout <= 0 {
+		opts.HandshakeTimeout = 10 * time.Second
+	}
+	if opts.PingInterval <= 0 {
+		opts.PingInterval = 20 * time.Second
+	}
+	if opts.PongTimeout <= 0 {
+		opts.PongTimeout = 10 * time.Second
+	}
+	if opts.Reconnect == nil {
+		opts.Reconnect = DefaultReconnectPolicy()
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+
+	return &Stream{
+		opts:    opts,
+		subs:    make(map[string][]streamSubscriber),
+		control: make(map[string]*streamControl),
+		pending: make(map[int64]chan error),
+		done:    make(chan struct{}),
+	}
+}
+
+// Connect opens the connection and starts the background loop that keeps
+// it alive. ctx bounds only the initial connection; the stream runs until
+// Close. Subscriptions made before Connect are sent once connected.
+//
+// Errors:
+//   - *RequestError with Operation "dialing stream" when the initial
+//     connection fails.
+//   - "stream already started" or "stream closed".
+func (s *Stream) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	switch {
+	case s.state == StreamClosed:
+		s.mu.Unlock()
+		return &GoTabdealError{Message: "stream closed"}
+	case s.started:
+		s.mu.Unlock()
+		return &GoTabdealError{Message: "stream already started"}
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	s.setState(StreamConnecting)
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		s.mu.Lock()
+		s.started = false
+		s.mu.Unlock()
+		s.setState(StreamDisconnected)
+		return err
+	}
+
+	go s.run(conn)
+	return nil
+}
+
+// Close closes the connection and every subscription channel. It is safe
+// to call more than once.
+func (s *Stream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.shutdown(&GoTabdealError{Message: "stream closed"})
+	})
+	return nil
+}
+
+// State returns the current connection state.
+func (s *Stream) State() StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// Reconnects returns how many times the connection has been replaced.
+func (s *Stream) Reconnects() uint64 {
+	return s.reconnects.Load()
+}
+
+// Subscriptions returns the names of all active streams, sorted.
+func (s *Stream) Subscriptions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.subs))
+	for name := range s.subs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SubscribeTrades subscribes to every trade of symbol ("BTCIRT" or
+// "BTC_IRT").
+func (s *Stream) SubscribeTrades(ctx context.Context, symbol string) (*Subscription[t.TradeEvent], error) {
+	return subscribe[t.TradeEvent](ctx, s, t.TradeStream(symbol))
+}
+
+// SubscribeDepth subscribes to incremental order book updates of symbol.
+func (s *Stream) SubscribeDepth(ctx context.Context, symbol string) (*Subscription[t.DepthUpdateEvent], error) {
+	return subscribe[t.DepthUpdateEvent](ctx, s, t.DepthStream(symbol))
+}
+
+// SubscribeBookTicker subscribes to best bid/ask updates of symbol.
+func (s *Stream) SubscribeBookTicker(ctx context.Context, symbol string) (*Subscription[t.BookTickerEvent], error) {
+	return subscribe[t.BookTickerEvent](ctx, s, t.BookTickerStream(symbol))
+}
+
+// SubscribeKlines subscribes to candlestick updates of symbol for an
+// interval such as "1m", "1h" or "1d".
+func (s *Stream) SubscribeKlines(ctx context.Context, symbol, interval string) (*Subscription[t.KlineEvent], error) {
+	return subscribe[t.KlineEvent](ctx, s, t.KlineStream(symbol, interval))
+}
+
+// SubscribeRaw subscribes to any stream by name and delivers the undecoded
+// event payloads.
+func (s *Stream) SubscribeRaw(ctx context.Context, name string) (*Subscription[json.RawMessage], error) {
+	return subscribe[json.RawMessage](ctx, s, name)
+}
+
+// subscribe registers a typed subscription and, when it is the first one
+// for name on a live connection, waits for the server to acknowledge it.
+// Later subscribers of name wait for that acknowledgement too.
+func subscribe[T any](ctx context.Context, s *Stream, name string) (*Subscription[T], error) {
+	sub := &Subscription[T]{
+		stream:   s,
+		name:     name,
+		ch:       make(chan T, s.opts.Buffer),
+		done:     make(chan struct{}),
+		overflow: s.opts.Overflow,
+		onError:  s.reportError,
+	}
+
+	unlock, err := s.lockName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	s.mu.Lock()
+	if s.state == StreamClosed {
+		s.mu.Unlock()
+		return nil, &GoTabdealError{Message: "stream closed"}
+	}
+	first := len(s.subs[name]) == 0
+	s.subs[name] = append(s.subs[name], sub)
+	s.mu.Unlock()
+
+	if first {
+		if err := s.request(ctx, "SUBSCRIBE", []string{name}); err != nil {
+			s.remove(name, sub)
+			sub.close(err)
+			return nil, err
+		}
+	}
+
+	return sub, nil
+}
+
+// lockName waits until no other SUBSCRIBE or UNSUBSCRIBE for name is in
+// progress and returns the function that lets the next one proceed.
+func (s *Stream) lockName(ctx context.Context, name string) (func(), error) {
+	s.mu.Lock()
+	control := s.control[name]
+	if control == nil {
+		control = &streamControl{sem: make(chan struct{}, 1)}
+		s.control[name] = control
+	}
+	control.refs++
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if control.refs--; control.refs == 0 {
+			delete(s.control, name)
+		}
+	}
+
+	select {
+	case control.sem <- struct{}{}:
+		return func() {
+			<-control.sem
+			release()
+		}, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// remove unregisters sub and reports whether it was the last subscriber
+// of name.
+func (s *Stream) remove(name string, sub streamSubscriber) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := slices.DeleteFunc(s.subs[name], func(other streamSubscriber) bool { return other == sub })
+	if len(subs) == 0 {
+		delete(s.subs, name)
+		return true
+	}
+	s.subs[name] = subs
+	return false
+}
+
+// request sends a control message and waits for its acknowledgement. It
+// returns nil without sending when not connected; the subscription set is
+// replayed on the next connection instead.
+func (s *Stream) request(ctx context.Context, method string, params []string) error {
+	s.mu.Lock()
+	conn := s.conn
+	if conn == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.nextId++
+	id := s.nextId
+	ack := make(chan error, 1)
+	s.pending[id] = ack
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	payload, err := json.Marshal(streamRequest{Method: method, Params: params, Id: id})
+	if err != nil {
+		return &RequestError{
+			GoTabdealError: GoTabdealError{Message: "failed to marshal stream request", Err: err},
+			Operation:      "preparing stream request",
+		}
+	}
+
+	if err := conn.WriteMessage(websocket.OpText, payload); err != nil {
+		return &RequestError{
+			GoTabdealError: GoTabdealError{Message: "failed to send stream request", Err: err},
+			Operation:      "sending stream request",
+		}
+	}
+
+	select {
+	case err := <-ack:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.done:
+		return &GoTabdealError{Message: "stream closed"}
+	}
+}
+
+// dial opens a connection and replays the active subscriptions.
+func (s *Stream) dial(ctx context.Context) (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.HandshakeTimeout)
+	defer cancel()
+
+	conn, err := websocket.Dial(ctx, s.opts.Url, nil)
+	if err != nil {
+		return nil, &RequestError{
+			GoTabdealError: GoTabdealError{Message: "failed to connect to stream", Err: err},
+			Operation:      "dialing stream",
+		}
+	}
+
+	s.mu.Lock()
+	if s.state == StreamClosed {
+		s.mu.Unlock()
+		_ = conn.Close()
+		return nil, &GoTabdealError{Message: "stream closed"}
+	}
+	s.conn = conn
+	names := make([]string, 0, len(s.subs))
+	for name := range s.subs {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+
+	if len(names) > 0 {
+		slices.Sort(names)
+		// The acknowledgement is read by serve; a rejection is reported
+		// through OnError.
+		payload, _ := json.Marshal(streamRequest{Method: "SUBSCRIBE", Params: names, Id: 0})
+		if err := conn.WriteMessage(websocket.OpText, payload); err != nil {
+			_ = conn.Abort()
+			return nil, &RequestError{
+				GoTabdealError: GoTabdealError{Message: "failed to resubscribe", Err: err},
+				Operation:      "sending stream request",
+			}
+		}
+	}
+
+	s.setState(StreamConnected)
+	return conn, nil
+}
+
+// run serves conn and keeps replacing it until the stream is closed or
+// the reconnect policy gives up.
+func (s *Stream) run(conn *websocket.Conn) {
+	policy := s.opts.Reconnect
+
+	for {
+		err := s.serve(conn)
+
+		s.mu.Lock()
+		s.conn = nil
+		for id, ack := range s.pending {
+			select {
+			case ack <- err:
+			default:
+			}
+			delete(s.pending, id)
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		s.reportError(&RequestError{
+			GoTabdealError: GoTabdealError{Message: "stream connection lost", Err: err},
+			Operation:      "reading stream",
+		})
+		s.setState(StreamReconnecting)
+
+		for attempt := 1; ; attempt++ {
+			timer := time.NewTimer(policy.backoff(attempt))
+			select {
+			case <-s.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			conn, err = s.dial(context.Background())
+			if err == nil {
+				s.reconnects.Add(1)
+				break
+			}
+
+			s.reportError(err)
+			if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+				s.closeOnce.Do(func() {
+					close(s.done)
+					s.shutdown(&GoTabdealError{Message: "stream reconnect failed", Err: err})
+				})
+				return
+			}
+		}
+	}
+}
+
+// serve reads from conn until it fails, answering the keepalive.
+func (s *Stream) serve(conn *websocket.Conn) error {
+	silence := s.opts.PingInterval + s.opts.PongTimeout
+	conn.PongHandler = func([]byte) {
+		_ = conn.SetReadDeadline(time.Now().Add(silence))
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		ticker := time.NewTicker(s.opts.PingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := conn.WriteMessage(websocket.OpPing, nil); err != nil {
+					_ = conn.Abort()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(silence))
+
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			_ = conn.Abort()
+			return err
+		}
+
+		s.dispatch(payload)
+	}
+}
+
+// dispatch routes one server message to acknowledgements or subscribers.
+func (s *Stream) dispatch(payload []byte) {
+	var msg streamMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		s.reportError(&RequestError{
+			GoTabdealError: GoTabdealError{Message: "failed to unmarshal stream message", Err: err},
+			Operation:      "parsing stream message",
+		})
+		return
+	}
+
+	if msg.Stream == "" && msg.Id != nil {
+		var err error
+		if msg.Error != nil {
+			err = &APIError{
+				GoTabdealError: GoTabdealError{Message: msg.Error.Msg},
+				Code:           msg.Error.Code,
+				Msg:            msg.Error.Msg,
+			}
+		}
+
+		s.mu.Lock()
+		ack, ok := s.pending[*msg.Id]
+		s.mu.Unlock()
+
+		switch {
+		case ok:
+			select {
+			case ack <- err:
+			default:
+			}
+		case err != nil:
+			s.reportError(err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	subs := slices.Clone(s.subs[msg.Stream])
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(msg.Data)
+	}
+}
+
+// shutdown closes the connection and every subscription.
+func (s *Stream) shutdown(err error) {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.state = StreamClosed
+	subs := s.subs
+	s.subs = make(map[string][]streamSubscriber)
+	s.mu.Unlock()
+
+	if conn != nil {
+		_ = conn.Close()
+	}
+
+	for _, list := range subs {
+		for _, sub := range list {
+			sub.close(err)
+		}
+	}
+
+	if s.opts.OnStateChange != nil {
+		s.opts.OnStateChange(StreamClosed)
+	}
+}
+
+func (s *Stream) setState(state StreamState) {
+	s.mu.Lock()
+	if s.state == StreamClosed || s.state == state {
+		s.mu.Unlock()
+		return
+	}
+	s.state = state
+	s.mu.Unlock()
+
+	if s.opts.OnStateChange != nil {
+		s.opts.OnStateChange(state)
+	}
+}
+
+func (s *Stream) reportError(err error) {
+	if s.opts.OnError != nil && err != nil {
+		s.opts.OnError(err)
+	}
+}
+
+// Subscription is a single subscriber of a stream. Events are delivered
+// on C until Unsubscribe is called or the Stream is closed.
+type Subscription[T any] struct {
+	stream   *Stream
+	name     string
+	ch       chan T
+	overflow StreamOverflow
+	onError  func(error)
+	dropped  atomic.Uint64
+
+	mu       sync.Mutex
+	done     chan struct{}
+	doneOnce sync.Once
+	err      error
+}
+
+// C returns the event channel. It is closed when the subscription ends.
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// Name returns the stream name, e.g. "btcirt@trade".
+func (s *Subscription[T]) Name() string {
+	return s.name
+}
+
+// Dropped returns how many events were discarded because the channel
+// buffer was full.
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Err returns why the channel was closed: nil after Unsubscribe, or the
+// error that closed the Stream.
+func (s *Subscription[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Unsubscribe ends the subscription and closes C. The server is asked to
+// stop the stream once its last subscriber is gone; ctx bounds the wait
+// for that acknowledgement and for a SUBSCRIBE of the same stream still
+// in progress. C is closed even when ctx ends first, but the server then
+// keeps sending the stream until the next reconnect.
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	unlock, err := s.stream.lockName(ctx, s.name)
+	if err != nil {
+		s.stream.remove(s.name, s)
+		s.close(nil)
+		return err
+	}
+	defer unlock()
+
+	last := s.stream.remove(s.name, s)
+	s.close(nil)
+	if !last {
+		return nil
+	}
+
+	return s.stream.request(ctx, "UNSUBSCRIBE", []string{s.name})
+}
+
+func (s *Subscription[T]) deliver(data json.RawMessage) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		s.onError(&RequestError{
+			GoTabdealError: GoTabdealError{Message: "failed to unmarshal " + s.name + " event", Err: err},
+			Operation:      "parsing stream message",
+		})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	switch s.overflow {
+	case StreamBlock:
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+
+	case StreamDropNewest:
+		select {
+		case s.ch <- event:
+		default:
+			s.dropped.Add(1)
+		}
+
+	default:
+		for {
+			select {
+			case s.ch <- event:
+				return
+			default:
+			}
+
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+func (s *Subscription[T]) close(err error) {
+	s.doneOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		s.err = err
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
@@ -0,0 +1,457 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-tabdeal/internal/websocket"
+	"github.com/darhelm/go-tabdeal/streamtest"
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// connectStream connects a Stream with fast reconnects to url and closes
+// it when the test ends.
+func connectStream(tt *testing.T, url string, opts StreamOptions) *Stream {
+	tt.Helper()
+
+	opts.Url = url
+	if opts.Reconnect == nil {
+		opts.Reconnect = &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, Multiplier: 2}
+	}
+
+	stream := NewStream(opts)
+	if err := stream.Connect(testContext(tt)); err != nil {
+		tt.Fatal(err)
+	}
+	tt.Cleanup(func() { _ = stream.Close() })
+	return stream
+}
+
+func testContext(tt *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	tt.Cleanup(cancel)
+	return ctx
+}
+
+// waitFor polls cond until it holds or five seconds pass.
+func waitFor(tt *testing.T, what string, cond func() bool) {
+	tt.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			tt.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// receive returns the next event of sub, failing the test after a timeout.
+func receive[T any](tt *testing.T, sub *Subscription[T]) T {
+	tt.Helper()
+
+	select {
+	case event, ok := <-sub.C():
+		if !ok {
+			tt.Fatalf("%s closed: %v", sub.Name(), sub.Err())
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		tt.Fatalf("no event on %s", sub.Name())
+	}
+	panic("unreachable")
+}
+
+func publishTrades(srv *streamtest.Server, symbol string, ids ...int64) {
+	for _, id := range ids {
+		srv.PublishTrade(t.TradeEvent{Symbol: symbol, TradeId: id, Price: t.MustParseDecimal("100")})
+	}
+}
+
+func TestStreamSubscribeUnsubscribe(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	ctx := testContext(tt)
+	stream := connectStream(tt, srv.URL, StreamOptions{})
+
+	first, err := stream.SubscribeTrades(ctx, "BTC_IRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+	second, err := stream.SubscribeTrades(ctx, "BTCIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if got := srv.Subscriptions(); !slices.Equal(got, []string{"btcirt@trade"}) {
+		tt.Fatalf("server subscriptions = %v", got)
+	}
+	if got := stream.Subscriptions(); !slices.Equal(got, []string{"btcirt@trade"}) {
+		tt.Fatalf("Subscriptions() = %v", got)
+	}
+
+	publishTrades(srv, "BTCIRT", 1)
+	for _, sub := range []*Subscription[t.TradeEvent]{first, second} {
+		if event := receive(tt, sub); event.TradeId != 1 || event.Price.String() != "100" {
+			tt.Errorf("%s got %+v", sub.Name(), event)
+		}
+	}
+
+	// The server keeps the stream until its last subscriber is gone.
+	if err := first.Unsubscribe(ctx); err != nil {
+		tt.Fatal(err)
+	}
+	if _, ok := <-first.C(); ok || first.Err() != nil {
+		tt.Errorf("first: channel open %v, Err() = %v", ok, first.Err())
+	}
+	if got := srv.Subscriptions(); !slices.Equal(got, []string{"btcirt@trade"}) {
+		tt.Fatalf("server subscriptions after the first Unsubscribe = %v", got)
+	}
+
+	publishTrades(srv, "BTCIRT", 2)
+	if event := receive(tt, second); event.TradeId != 2 {
+		tt.Errorf("second got trade %d, want 2", event.TradeId)
+	}
+
+	if err := second.Unsubscribe(ctx); err != nil {
+		tt.Fatal(err)
+	}
+	if got := srv.Subscriptions(); len(got) != 0 {
+		tt.Errorf("server subscriptions after the last Unsubscribe = %v", got)
+	}
+	if got := stream.Subscriptions(); len(got) != 0 {
+		tt.Errorf("Subscriptions() = %v", got)
+	}
+}
+
+func TestStreamResubscribeWaitsForUnsubscribe(tt *testing.T) {
+	// The server logs control requests as it reads them and holds the
+	// acknowledgement of UNSUBSCRIBE until release is closed.
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var methods []string
+	var server *websocket.Conn
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		server = conn
+		mu.Unlock()
+
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req streamRequest
+			_ = json.Unmarshal(payload, &req)
+			mu.Lock()
+			methods = append(methods, req.Method)
+			mu.Unlock()
+
+			ack := []byte(fmt.Sprintf(`{"result":null,"id":%d}`, req.Id))
+			if req.Method == "UNSUBSCRIBE" {
+				go func() {
+					<-release
+					_ = conn.WriteMessage(websocket.OpText, ack)
+				}()
+				continue
+			}
+			_ = conn.WriteMessage(websocket.OpText, ack)
+		}
+	}))
+	defer srv.Close()
+
+	logged := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(methods)
+	}
+
+	ctx := testContext(tt)
+	stream := connectStream(tt, "ws"+strings.TrimPrefix(srv.URL, "http"), StreamOptions{})
+	first, err := stream.SubscribeTrades(ctx, "BTCIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	unsubscribed := make(chan error, 1)
+	go func() { unsubscribed <- first.Unsubscribe(ctx) }()
+	waitFor(tt, "the UNSUBSCRIBE request", func() bool { return len(logged()) == 2 })
+
+	type result struct {
+		sub *Subscription[t.TradeEvent]
+		err error
+	}
+	subscribed := make(chan result, 1)
+	go func() {
+		sub, err := stream.SubscribeTrades(ctx, "BTCIRT")
+		subscribed <- result{sub, err}
+	}()
+
+	// The new SUBSCRIBE must not be sent before UNSUBSCRIBE is answered.
+	time.Sleep(50 * time.Millisecond)
+	if got := logged(); !slices.Equal(got, []string{"SUBSCRIBE", "UNSUBSCRIBE"}) {
+		tt.Fatalf("requests before the UNSUBSCRIBE acknowledgement = %v", got)
+	}
+
+	close(release)
+	if err := <-unsubscribed; err != nil {
+		tt.Fatal(err)
+	}
+	second := <-subscribed
+	if second.err != nil {
+		tt.Fatal(second.err)
+	}
+	if got := logged(); !slices.Equal(got, []string{"SUBSCRIBE", "UNSUBSCRIBE", "SUBSCRIBE"}) {
+		tt.Fatalf("requests = %v", got)
+	}
+
+	mu.Lock()
+	_ = server.WriteMessage(websocket.OpText, []byte(`{"stream":"btcirt@trade","data":{"e":"trade","t":3}}`))
+	mu.Unlock()
+	if event := receive(tt, second.sub); event.TradeId != 3 {
+		tt.Errorf("trade %d, want 3", event.TradeId)
+	}
+}
+
+func TestStreamSubscribeRejected(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+	srv.Reject = func(stream string) string {
+		if strings.HasPrefix(stream, "nope") {
+			return "Invalid request: unknown stream"
+		}
+		return ""
+	}
+
+	stream := connectStream(tt, srv.URL, StreamOptions{})
+
+	_, err := stream.SubscribeTrades(testContext(tt), "NOPE")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 2 || apiErr.Msg != "Invalid request: unknown stream" {
+		tt.Fatalf("SubscribeTrades() error = %v, want the server's rejection", err)
+	}
+	if got := stream.Subscriptions(); len(got) != 0 {
+		tt.Errorf("Subscriptions() = %v after a rejection", got)
+	}
+}
+
+func TestStreamTradeBuyerMaker(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	stream := connectStream(tt, srv.URL, StreamOptions{})
+	sub, err := stream.SubscribeTrades(testContext(tt), "BTCIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	// "m" and "M" differ only in case; each must reach its own field.
+	srv.Publish("btcirt@trade", map[string]any{"e": "trade", "s": "BTCIRT", "t": 1, "m": true, "M": false})
+	srv.Publish("btcirt@trade", map[string]any{"e": "trade", "s": "BTCIRT", "t": 2, "m": false, "M": true})
+
+	for _, want := range []t.TradeEvent{{TradeId: 1, IsBuyerMaker: true}, {TradeId: 2, IsBestMatch: true}} {
+		event := receive(tt, sub)
+		if event.TradeId != want.TradeId || event.IsBuyerMaker != want.IsBuyerMaker || event.IsBestMatch != want.IsBestMatch {
+			tt.Errorf("trade %d: IsBuyerMaker = %v, IsBestMatch = %v, want %v and %v",
+				event.TradeId, event.IsBuyerMaker, event.IsBestMatch, want.IsBuyerMaker, want.IsBestMatch)
+		}
+	}
+}
+
+func TestStreamClose(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	stream := connectStream(tt, srv.URL, StreamOptions{})
+	sub, err := stream.SubscribeTrades(testContext(tt), "BTCIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	_ = stream.Close()
+
+	if _, ok := <-sub.C(); ok {
+		tt.Fatal("subscription channel still open after Close")
+	}
+	if sub.Err() == nil || stream.State() != StreamClosed {
+		tt.Errorf("Err() = %v, State() = %v", sub.Err(), stream.State())
+	}
+	if _, err := stream.SubscribeTrades(testContext(tt), "BTCIRT"); err == nil {
+		tt.Error("SubscribeTrades() succeeded on a closed stream")
+	}
+	if err := stream.Connect(testContext(tt)); err == nil {
+		tt.Error("Connect() succeeded on a closed stream")
+	}
+}
+
+func TestStreamReconnectResubscribes(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var states []StreamState
+	stream := connectStream(tt, srv.URL, StreamOptions{
+		OnStateChange: func(state StreamState) {
+			mu.Lock()
+			states = append(states, state)
+			mu.Unlock()
+		},
+	})
+
+	ctx := testContext(tt)
+	trades, err := stream.SubscribeTrades(ctx, "BTCIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+	depth, err := stream.SubscribeDepth(ctx, "ETHIRT")
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	srv.DropConnections()
+
+	waitFor(tt, "reconnect", func() bool { return stream.Reconnects() == 1 })
+	waitFor(tt, "resubscription", func() bool {
+		return srv.Connections() == 1 && slices.Equal(srv.Subscriptions(), []string{"btcirt@trade", "ethirt@depth"})
+	})
+
+	publishTrades(srv, "BTCIRT", 1)
+	srv.PublishDepth(t.DepthUpdateEvent{Symbol: "ETHIRT", FinalUpdateId: 9})
+
+	if event := receive(tt, trades); event.TradeId != 1 {
+		tt.Errorf("trade %d after reconnect, want 1", event.TradeId)
+	}
+	if event := receive(tt, depth); event.FinalUpdateId != 9 {
+		tt.Errorf("depth update %d after reconnect, want 9", event.FinalUpdateId)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []StreamState{StreamConnecting, StreamConnected, StreamReconnecting, StreamConnected}
+	if !slices.Equal(states, want) {
+		tt.Errorf("states = %v, want %v", states, want)
+	}
+}
+
+func TestStreamKeepalive(tt *testing.T) {
+	srv := streamtest.NewServer()
+	defer srv.Close()
+
+	// Answered pings keep a silent connection alive well past
+	// PingInterval+PongTimeout.
+	stream := connectStream(tt, srv.URL, StreamOptions{
+		PingInterval: 10 * time.Millisecond,
+		PongTimeout:  30 * time.Millisecond,
+	})
+
+	time.Sleep(300 * time.Millisecond)
+
+	if n := stream.Reconnects(); n != 0 || stream.State() != StreamConnected {
+		tt.Errorf("Reconnects() = %d, State() = %v, want a single live connection", n, stream.State())
+	}
+}
+
+func TestStreamPongTimeout(tt *testing.T) {
+	// A server that never reads never answers pings.
+	var conns atomic.Int32
+	var mu sync.Mutex
+	var open []*websocket.Conn
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r)
+		if err != nil {
+			return
+		}
+		conns.Add(1)
+		mu.Lock()
+		open = append(open, conn)
+		mu.Unlock()
+	}))
+	defer func() {
+		mu.Lock()
+		for _, conn := range open {
+			_ = conn.Abort()
+		}
+		mu.Unlock()
+		srv.Close()
+	}()
+
+	var lost atomic.Int32
+	stream := connectStream(tt, "ws"+strings.TrimPrefix(srv.URL, "http"), StreamOptions{
+		PingInterval: 10 * time.Millisecond,
+		PongTimeout:  20 * time.Millisecond,
+		OnError: func(err error) {
+			var reqErr *RequestError
+			if errors.As(err, &reqErr) && reqErr.Operation == "reading stream" {
+				lost.Add(1)
+			}
+		},
+	})
+
+	waitFor(tt, "a replacement connection", func() bool { return stream.Reconnects() >= 1 })
+	if conns.Load() < 2 || lost.Load() < 1 {
+		tt.Errorf("connections = %d, lost = %d", conns.Load(), lost.Load())
+	}
+}
+
+func TestStreamOverflow(tt *testing.T) {
+	tests := []struct {
+		name     string
+		overflow StreamOverflow
+		want     []int64
+		dropped  uint64
+	}{
+		{"drop oldest", StreamDropOldest, []int64{4, 5}, 3},
+		{"drop newest", StreamDropNewest, []int64{1, 2}, 3},
+		{"block", StreamBlock, []int64{1, 2, 3, 4, 5}, 0},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			srv := streamtest.NewServer()
+			defer srv.Close()
+
+			stream := connectStream(tt, srv.URL, StreamOptions{Buffer: 2, Overflow: tc.overflow})
+			sub, err := stream.SubscribeTrades(testContext(tt), "BTCIRT")
+			if err != nil {
+				tt.Fatal(err)
+			}
+
+			// Nothing is read until every event has reached the
+			// subscription (or, when blocking, the connection).
+			publishTrades(srv, "BTCIRT", 1, 2, 3, 4, 5)
+			if tc.dropped > 0 {
+				waitFor(tt, "dropped events", func() bool { return sub.Dropped() == tc.dropped })
+			} else {
+				waitFor(tt, "a full buffer", func() bool { return len(sub.C()) == cap(sub.C()) })
+			}
+
+			var got []int64
+			for range tc.want {
+				got = append(got, receive(tt, sub).TradeId)
+			}
+			if !slices.Equal(got, tc.want) {
+				tt.Errorf("received trades %v, want %v", got, tc.want)
+			}
+			if n := sub.Dropped(); n != tc.dropped {
+				tt.Errorf("Dropped() = %d, want %d", n, tc.dropped)
+			}
+			if n := len(sub.C()); n != 0 {
+				tt.Errorf("%d events left in the buffer", n)
+			}
+		})
+	}
+}
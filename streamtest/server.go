@@ -0,0 +1,270 @@
+// Package streamtest provides an in-process stand-in for Tabdeal's market
+// data WebSocket, so code built on tabdeal.Stream can run offline.
+//
+// The server speaks the same combined-stream protocol as the real feed:
+// it answers SUBSCRIBE, UNSUBSCRIBE and LIST_SUBSCRIPTIONS requests and
+// pushes events wrapped as {"stream": "...", "data": {...}} to every
+// connection subscribed to that stream.
+//
+// Example:
+//
+//	srv := streamtest.NewServer()
+//	defer srv.Close()
+//
+//	stream := tabdeal.NewStream(tabdeal.StreamOptions{Url: srv.URL})
+//	_ = stream.Connect(ctx)
+//	trades, _ := stream.SubscribeTrades(ctx, "BTCIRT")
+//
+//	srv.PublishTrade(types.TradeEvent{Symbol: "BTCIRT", Price: types.MustParseDecimal("100")})
+//	fmt.Println((<-trades.C()).Price)
+package streamtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darhelm/go-tabdeal/internal/websocket"
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// Server is a local WebSocket server that mimics the Tabdeal stream.
+type Server struct {
+	// URL is the ws:// endpoint to pass as tabdeal.StreamOptions.Url.
+	URL string
+
+	// Reject, when set, is consulted for every stream in a SUBSCRIBE
+	// request. A non-empty message rejects the whole request with that
+	// error message, as the real server does for unknown streams.
+	Reject func(stream string) string
+
+	http *httptest.Server
+
+	mu    sync.Mutex
+	conns map[*serverConn]struct{}
+}
+
+type serverConn struct {
+	ws      *websocket.Conn
+	streams map[string]bool
+}
+
+type request struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	Id     int64    `json:"id"`
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() *Server {
+	s := &Server{conns: make(map[*serverConn]struct{})}
+	s.http = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = "ws" + strings.TrimPrefix(s.http.URL, "http") + "/stream"
+	return s
+}
+
+// Close disconnects every client and stops the server.
+func (s *Server) Close() {
+	s.DropConnections()
+	s.http.Close()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r)
+	if err != nil {
+		return
+	}
+
+	c := &serverConn{ws: ws, streams: make(map[string]bool)}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = ws.Abort()
+	}()
+
+	for {
+		_, payload, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req request
+		if err := json.Unmarshal(payload, &req); err != nil {
+			s.reply(c, map[string]any{"error": map[string]any{"code": 3, "msg": "Invalid JSON"}, "id": nil})
+			continue
+		}
+
+		s.reply(c, s.apply(c, req))
+	}
+}
+
+// apply executes a control request and returns the response.
+func (s *Server) apply(c *serverConn, req request) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Method {
+	case "SUBSCRIBE":
+		for _, name := range req.Params {
+			if s.Reject != nil {
+				if msg := s.Reject(name); msg != "" {
+					return map[string]any{"error": map[string]any{"code": 2, "msg": msg}, "id": req.Id}
+				}
+			}
+		}
+		for _, name := range req.Params {
+			c.streams[name] = true
+		}
+		return map[string]any{"result": nil, "id": req.Id}
+
+	case "UNSUBSCRIBE":
+		for _, name := range req.Params {
+			delete(c.streams, name)
+		}
+		return map[string]any{"result": nil, "id": req.Id}
+
+	case "LIST_SUBSCRIPTIONS":
+		names := make([]string, 0, len(c.streams))
+		for name := range c.streams {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return map[string]any{"result": names, "id": req.Id}
+	}
+
+	return map[string]any{"error": map[string]any{"code": 2, "msg": fmt.Sprintf("Invalid request: unknown method %q", req.Method)}, "id": req.Id}
+}
+
+func (s *Server) reply(c *serverConn, response map[string]any) {
+	payload, _ := json.Marshal(response)
+	_ = c.ws.WriteMessage(websocket.OpText, payload)
+}
+
+// Publish sends data on the named stream to every subscribed connection
+// and returns how many connections received it.
+func (s *Server) Publish(stream string, data any) int {
+	payload, err := json.Marshal(map[string]any{"stream": stream, "data": data})
+	if err != nil {
+		panic(fmt.Sprintf("streamtest: cannot marshal event: %v", err))
+	}
+
+	s.mu.Lock()
+	var targets []*serverConn
+	for c := range s.conns {
+		if c.streams[stream] {
+			targets = append(targets, c)
+		}
+	}
+	s.mu.Unlock()
+
+	sent := 0
+	for _, c := range targets {
+		if c.ws.WriteMessage(websocket.OpText, payload) == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// PublishTrade publishes event on its symbol's trade stream. EventType
+// is filled in when empty.
+func (s *Server) PublishTrade(event t.TradeEvent) int {
+	if event.EventType == "" {
+		event.EventType = "trade"
+	}
+	return s.Publish(t.TradeStream(event.Symbol), event)
+}
+
+// PublishDepth publishes event on its symbol's depth stream.
+func (s *Server) PublishDepth(event t.DepthUpdateEvent) int {
+	if event.EventType == "" {
+		event.EventType = "depthUpdate"
+	}
+	return s.Publish(t.DepthStream(event.Symbol), event)
+}
+
+// PublishBookTicker publishes event on its symbol's book ticker stream.
+func (s *Server) PublishBookTicker(event t.BookTickerEvent) int {
+	return s.Publish(t.BookTickerStream(event.Symbol), event)
+}
+
+// PublishKline publishes event on the kline stream of its symbol and
+// Kline.Interval.
+func (s *Server) PublishKline(event t.KlineEvent) int {
+	if event.EventType == "" {
+		event.EventType = "kline"
+	}
+	return s.Publish(t.KlineStream(event.Symbol, event.Kline.Interval), event)
+}
+
+// Subscriptions returns the union of streams subscribed by all
+// connections, sorted.
+func (s *Server) Subscriptions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for c := range s.conns {
+		for name := range c.streams {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Connections returns the number of open client connections.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.conns)
+}
+
+// WaitForSubscription blocks until some connection is subscribed to
+// stream or ctx is done.
+func (s *Server) WaitForSubscription(ctx context.Context, stream string) error {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if slices.Contains(s.Subscriptions(), stream) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DropConnections aborts every connection without a closing handshake,
+// simulating a network failure. Clients are expected to reconnect.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*serverConn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.ws.Abort()
+	}
+}
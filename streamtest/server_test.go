@@ -0,0 +1,187 @@
+package streamtest
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-tabdeal/internal/websocket"
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// dial connects a raw client to srv.
+func dial(tb testing.TB, srv *Server) *websocket.Conn {
+	tb.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := websocket.Dial(ctx, srv.URL, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	tb.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// roundTrip sends message and returns the decoded reply.
+func roundTrip(tb testing.TB, conn *websocket.Conn, message string) map[string]any {
+	tb.Helper()
+
+	if err := conn.WriteMessage(websocket.OpText, []byte(message)); err != nil {
+		tb.Fatal(err)
+	}
+	return read(tb, conn)
+}
+
+func read(tb testing.TB, conn *websocket.Conn) map[string]any {
+	tb.Helper()
+
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var reply map[string]any
+	if err := json.Unmarshal(payload, &reply); err != nil {
+		tb.Fatalf("reply %s: %v", payload, err)
+	}
+	return reply
+}
+
+func TestServerRequests(tt *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Reject = func(stream string) string {
+		if stream == "bad@trade" {
+			return "Invalid request: unknown stream"
+		}
+		return ""
+	}
+
+	conn := dial(tt, srv)
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+		streams []string
+	}{
+		{
+			name:    "subscribe",
+			message: `{"method":"SUBSCRIBE","params":["btcirt@trade","ethirt@depth"],"id":1}`,
+			want:    `{"id":1,"result":null}`,
+			streams: []string{"btcirt@trade", "ethirt@depth"},
+		},
+		{
+			name:    "list subscriptions",
+			message: `{"method":"LIST_SUBSCRIPTIONS","id":2}`,
+			want:    `{"id":2,"result":["btcirt@trade","ethirt@depth"]}`,
+			streams: []string{"btcirt@trade", "ethirt@depth"},
+		},
+		{
+			name:    "rejected subscribe changes nothing",
+			message: `{"method":"SUBSCRIBE","params":["usdtirt@trade","bad@trade"],"id":3}`,
+			want:    `{"error":{"code":2,"msg":"Invalid request: unknown stream"},"id":3}`,
+			streams: []string{"btcirt@trade", "ethirt@depth"},
+		},
+		{
+			name:    "unsubscribe",
+			message: `{"method":"UNSUBSCRIBE","params":["ethirt@depth"],"id":4}`,
+			want:    `{"id":4,"result":null}`,
+			streams: []string{"btcirt@trade"},
+		},
+		{
+			name:    "unknown method",
+			message: `{"method":"SUBSCRIBE_ALL","id":5}`,
+			want:    `{"error":{"code":2,"msg":"Invalid request: unknown method \"SUBSCRIBE_ALL\""},"id":5}`,
+			streams: []string{"btcirt@trade"},
+		},
+		{
+			name:    "invalid json",
+			message: `{"method":`,
+			want:    `{"error":{"code":3,"msg":"Invalid JSON"},"id":null}`,
+			streams: []string{"btcirt@trade"},
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			reply, _ := json.Marshal(roundTrip(tt, conn, tc.message))
+			if string(reply) != tc.want {
+				tt.Errorf("reply = %s, want %s", reply, tc.want)
+			}
+			if got := srv.Subscriptions(); !slices.Equal(got, tc.streams) {
+				tt.Errorf("Subscriptions() = %v, want %v", got, tc.streams)
+			}
+		})
+	}
+}
+
+func TestServerPublish(tt *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	subscribed := dial(tt, srv)
+	other := dial(tt, srv)
+	roundTrip(tt, subscribed, `{"method":"SUBSCRIBE","params":["btcirt@trade"],"id":1}`)
+	roundTrip(tt, other, `{"method":"SUBSCRIBE","params":["btcirt@depth"],"id":1}`)
+
+	if n := srv.Connections(); n != 2 {
+		tt.Fatalf("Connections() = %d, want 2", n)
+	}
+
+	if n := srv.PublishTrade(t.TradeEvent{Symbol: "BTC_IRT", TradeId: 7, Price: t.MustParseDecimal("100")}); n != 1 {
+		tt.Fatalf("PublishTrade() reached %d connections, want 1", n)
+	}
+
+	msg := read(tt, subscribed)
+	if msg["stream"] != "btcirt@trade" {
+		tt.Errorf("stream = %v, want btcirt@trade", msg["stream"])
+	}
+	data := msg["data"].(map[string]any)
+	if data["e"] != "trade" || data["s"] != "BTC_IRT" || data["t"] != float64(7) || data["p"] != "100" {
+		tt.Errorf("data = %v", data)
+	}
+
+	if n := srv.Publish("ltcirt@trade", map[string]any{}); n != 0 {
+		tt.Errorf("Publish() to an unsubscribed stream reached %d connections", n)
+	}
+}
+
+func TestServerDropConnections(tt *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	conn := dial(tt, srv)
+	roundTrip(tt, conn, `{"method":"SUBSCRIBE","params":["btcirt@trade"],"id":1}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.WaitForSubscription(ctx, "btcirt@trade"); err != nil {
+		tt.Fatal(err)
+	}
+
+	srv.DropConnections()
+
+	if _, _, err := conn.ReadMessage(); err == nil {
+		tt.Fatal("ReadMessage() succeeded on a dropped connection")
+	}
+	for srv.Connections() != 0 {
+		if ctx.Err() != nil {
+			tt.Fatal("connection still registered after DropConnections")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := srv.Subscriptions(); len(got) != 0 {
+		tt.Errorf("Subscriptions() = %v after DropConnections", got)
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := srv.WaitForSubscription(short, "btcirt@trade"); err != context.DeadlineExceeded {
+		tt.Errorf("WaitForSubscription() = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,231 @@
+package tabdeal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// TimeSyncOptions configures clock-drift compensation for signed requests.
+type TimeSyncOptions struct {
+	// Interval is how often StartTimeSync re-measures the offset.
+	// Defaults to five minutes.
+	Interval time.Duration
+
+	// Samples is the number of /time round trips taken per measurement.
+	// The sample with the smallest round-trip time wins. Defaults to 5.
+	Samples int
+
+	// OnSync, when set, is called after every measurement attempt. It can
+	// be used to alert on excessive drift.
+	OnSync func(TimeSyncStats)
+}
+
+// TimeSyncStats describes the most recent clock measurement.
+type TimeSyncStats struct {
+	// Offset is server time minus local time. It is added to the local
+	// clock when signing requests.
+	Offset time.Duration
+
+	// RTT is the round-trip time of the selected sample. The offset is
+	// accurate to within RTT/2.
+	RTT time.Duration
+
+	// SyncedAt is the local time of the last successful measurement.
+	SyncedAt time.Time
+
+	// Err is the error of the last measurement attempt, if it failed.
+	Err error
+}
+
+// timeSync holds the measured clock offset of a Client.
+type timeSync struct {
+	mu    sync.RWMutex
+	opts  TimeSyncOptions
+	stats TimeSyncStats
+}
+
+func (s *timeSync) offset() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stats.Offset
+}
+
+type recvWindowKey struct{}
+
+// WithRecvWindow returns a context that overrides the client's RecvWindow
+// for the signed requests made with it.
+//
+// Example:
+//
+//	ctx := tabdeal.WithRecvWindow(context.Background(), 2*time.Second)
+//	resp, err := client.CreateOrderCtx(ctx, params)
+func WithRecvWindow(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, recvWindowKey{}, window)
+}
+
+// recvWindow returns the recvWindow to send with a signed request.
+func (c *Client) recvWindow(ctx context.Context) time.Duration {
+	if window, ok := ctx.Value(recvWindowKey{}).(time.Duration); ok {
+		return window
+	}
+	return c.RecvWindow
+}
+
+// timestamp returns the signing timestamp in milliseconds, corrected by the
+// measured server offset.
+func (c *Client) timestamp() int64 {
+	return time.Now().Add(c.clock.offset()).UnixMilli()
+}
+
+// TimeOffset returns the last measured difference between Tabdeal's clock
+// and the local clock (server minus local). It is zero until SyncTime has
+// succeeded at least once.
+func (c *Client) TimeOffset() time.Duration {
+	return c.clock.offset()
+}
+
+// TimeSyncStats returns the result of the most recent clock measurement.
+func (c *Client) TimeSyncStats() TimeSyncStats {
+	c.clock.mu.RLock()
+	defer c.clock.mu.RUnlock()
+	return c.clock.stats
+}
+
+// SyncTime measures the offset between the local clock and Tabdeal's
+// server clock and applies it to all subsequent signed requests.
+//
+// Endpoint:
+//
+//	GET /r/api/v1/time
+//
+// Behavior:
+//   - Takes TimeSyncOptions.Samples NTP-style samples. For each, the
+//     offset is serverTime - (sent + received)/2.
+//   - Keeps the sample with the smallest round-trip time, since it bounds
+//     the error most tightly.
+//   - Samples bypass the retry policy so retries cannot distort RTT, and
+//     the round trip is timed from after the rate limiter admits the
+//     sample, so waiting for budget does not count as RTT.
+//
+// Returns:
+//   - TimeSyncStats of the new measurement.
+//   - error when no sample succeeded; the previous offset is kept.
+//
+// Example:
+//
+//	stats, err := client.SyncTime(ctx)
+//	if err == nil && stats.Offset.Abs() > time.Second {
+//	    log.Printf("clock drift: %s", stats.Offset)
+//	}
+func (c *Client) SyncTime(ctx context.Context) (TimeSyncStats, error) {
+	samples := c.clock.opts.Samples
+	if samples <= 0 {
+		samples = 5
+	}
+
+	var best TimeSyncStats
+	var lastErr error
+	found := false
+
+	for i := 0; i < samples; i++ {
+		var serverTime *t.ServerTime
+		url := c.createApiURI("GET", "/time")
+
+		// The round trip is timed from after the rate limiter wait.
+		err := c.acquire(ctx, "GET", url, false, nil)
+		sent := time.Now()
+		if err == nil {
+			err = c.sendRequest(ctx, "GET", url, false, nil, &serverTime)
+		}
+		received := time.Now()
+
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if serverTime == nil {
+			continue
+		}
+
+		rtt := received.Sub(sent)
+		midpoint := sent.Add(rtt / 2)
+		offset := time.UnixMilli(serverTime.ServerTime).Sub(midpoint)
+
+		if !found || rtt < best.RTT {
+			best = TimeSyncStats{Offset: offset, RTT: rtt, SyncedAt: received}
+			found = true
+		}
+	}
+
+	c.clock.mu.Lock()
+	if found {
+		c.clock.stats = best
+	} else {
+		if lastErr == nil {
+			lastErr = &GoTabdealError{Message: "server time response was empty"}
+		}
+		c.clock.stats.Err = &GoTabdealError{
+			Message: "failed to synchronize server time",
+			Err:     lastErr,
+		}
+	}
+	stats := c.clock.stats
+	c.clock.mu.Unlock()
+
+	if c.clock.opts.OnSync != nil {
+		c.clock.opts.OnSync(stats)
+	}
+
+	if !found {
+		return stats, stats.Err
+	}
+	return stats, nil
+}
+
+// StartTimeSync performs an initial SyncTime and then keeps re-measuring
+// every TimeSyncOptions.Interval in a background goroutine until ctx is
+// done. Failures of background measurements are reported through
+// TimeSyncStats().Err and the OnSync callback; the last good offset stays
+// in effect.
+//
+// Returns:
+//   - the error of the initial measurement. The background loop is started
+//     regardless, so a transient failure at startup heals itself.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	if err := client.StartTimeSync(ctx); err != nil {
+//	    log.Println("initial time sync failed:", err)
+//	}
+func (c *Client) StartTimeSync(ctx context.Context) error {
+	_, err := c.SyncTime(ctx)
+
+	interval := c.clock.opts.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, _ = c.SyncTime(ctx)
+			}
+		}
+	}()
+
+	return err
+}
@@ -0,0 +1,48 @@
+package tabdeal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSyncTimeExcludesRateLimitWait(tt *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"serverTime":` + strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10) + `}`))
+	}))
+	defer srv.Close()
+
+	// One weight per 100ms: once the bucket is drained, every sample
+	// waits about 100ms for the limiter.
+	client, err := NewClient(ClientOptions{
+		BaseUrl:   srv.URL,
+		TimeSync:  &TimeSyncOptions{Samples: 3},
+		RateLimit: &RateLimitOptions{PublicWeight: 1, Interval: 100 * time.Millisecond},
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	if err := client.Request(http.MethodGet, client.createApiURI(http.MethodGet, "/time"), false, nil, nil); err != nil {
+		tt.Fatal(err)
+	}
+
+	start := time.Now()
+	stats, err := client.SyncTime(context.Background())
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		tt.Fatalf("SyncTime() took %v, want the samples rate limited", elapsed)
+	}
+	if stats.RTT >= 50*time.Millisecond {
+		tt.Errorf("RTT = %v, want the limiter wait left out", stats.RTT)
+	}
+	if offset := stats.Offset - time.Hour; offset.Abs() > 50*time.Millisecond {
+		tt.Errorf("Offset = %v, want about an hour", stats.Offset)
+	}
+}
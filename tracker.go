@@ -0,0 +1,701 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// trackerScale is the number of decimal places kept for average prices.
+const trackerScale = 18
+
+// OrderEventType is the lifecycle stage an OrderEvent reports. Orders move
+// from OrderEventNew through OrderEventPartiallyFilled (once per fill) to
+// one of the final stages, skipping stages they never passed through.
+type OrderEventType int
+
+const (
+	// OrderEventNew means the order is open and nothing has filled.
+	OrderEventNew OrderEventType = iota
+
+	// OrderEventPartiallyFilled means part of the order filled since the
+	// previous event and the rest is still open.
+	OrderEventPartiallyFilled
+
+	// OrderEventFilled means the order filled completely.
+	OrderEventFilled
+
+	// OrderEventCanceled means the order was cancelled, possibly after
+	// partial fills.
+	OrderEventCanceled
+
+	// OrderEventRejected means the exchange rejected the order.
+	OrderEventRejected
+
+	// OrderEventExpired means the order expired, e.g. an IOC or FOK order
+	// that could not fill, possibly after partial fills.
+	OrderEventExpired
+)
+
+// String returns the stage name, e.g. "PARTIALLY_FILLED".
+func (e OrderEventType) String() string {
+	switch e {
+	case OrderEventNew:
+		return "NEW"
+	case OrderEventPartiallyFilled:
+		return "PARTIALLY_FILLED"
+	case OrderEventFilled:
+		return "FILLED"
+	case OrderEventCanceled:
+		return "CANCELED"
+	case OrderEventRejected:
+		return "REJECTED"
+	case OrderEventExpired:
+		return "EXPIRED"
+	}
+	return "UNKNOWN"
+}
+
+// IsFinal reports whether no further events follow for the order.
+func (e OrderEventType) IsFinal() bool {
+	return e >= OrderEventFilled
+}
+
+// OrderEvent reports a change of a tracked order: a new stage, a fill, or
+// both.
+type OrderEvent struct {
+	Type OrderEventType
+
+	// Symbol addresses the order's market as it was registered.
+	Symbol        t.BaseSymbolParams
+	OrderId       int64
+	ClientOrderId string
+
+	// Status is the raw order status behind Type.
+	Status t.OrderStatus
+
+	// FillQty and FillQuoteQty are what filled since the previous event,
+	// at an average of FillPrice. All three are zero when nothing filled.
+	FillQty      t.Decimal
+	FillQuoteQty t.Decimal
+	FillPrice    t.Decimal
+
+	// Commission is the commission of Trades, keyed by asset.
+	Commission map[string]t.Decimal
+
+	// Trades are the executions reported by GetUserTrades since the
+	// previous event.
+	Trades []t.UserTradeResponse
+
+	// ExecutedQty, CumulativeQuoteQty and AvgPrice cover every fill so far;
+	// TotalCommission every commission reported so far.
+	ExecutedQty        t.Decimal
+	CumulativeQuoteQty t.Decimal
+	AvgPrice           t.Decimal
+	TotalCommission    map[string]t.Decimal
+
+	// Order is the order as last reported by the exchange.
+	Order t.BaseOrderResponse
+
+	// Time is when the change was observed.
+	Time time.Time
+}
+
+// OrderTrackerOptions configures an OrderTracker.
+type OrderTrackerOptions struct {
+	// Interval is the polling period of Start. Defaults to one second.
+	Interval time.Duration
+
+	// FillGrace is how many polls a fill may wait for its trades to show up
+	// in GetUserTrades, so the event carries their commission. After that
+	// the event is sent without them and late trades are reported with the
+	// order's next event, if any. Defaults to 3; negative disables waiting.
+	FillGrace int
+
+	// OnError, when set, receives the errors of background polls.
+	OnError func(error)
+}
+
+// OrderTracker follows orders until they finish and reports every stage
+// change and fill as an OrderEvent.
+//
+// Each poll makes, per symbol with tracked orders:
+//   - one GetOpenOrders call, which covers every tracked order still open;
+//   - one GetOrderStatus call per tracked order missing from the open
+//     orders, to learn how it finished;
+//   - GetUserTrades calls only when an order filled further than the
+//     trades seen so far, paging forward from the last trade id.
+//
+// Behavior:
+//   - Fill quantities come from the order's executedQty and
+//     cummulativeQuoteQty, so events always agree with GetOrderStatus;
+//     commission and individual executions come from GetUserTrades.
+//   - A snapshot older than what was already reported (lower executedQty,
+//     or open after a final stage) is ignored.
+//   - The first event of an order reports its stage when first seen:
+//     OrderEventNew for an untouched order, or its fills so far.
+//   - After a final event the order is no longer tracked.
+//   - Events are delivered to every subscriber in order. Delivery waits
+//     for subscribers with a full buffer, so no event is lost; the poll
+//     stalls until they catch up or its context is done.
+//
+// Example:
+//
+//	tracker := client.NewOrderTracker(tabdeal.OrderTrackerOptions{})
+//	events, stop := tracker.Subscribe(0)
+//	defer stop()
+//	tracker.Start(ctx)
+//
+//	order, _ := client.CreateOrder(params)
+//	tracker.Track(t.GetOrderStatusParams{
+//	    BaseSymbolParams: params.BaseSymbolParams,
+//	    OrderId:          order.OrderId,
+//	})
+//
+//	for event := range events {
+//	    fmt.Println(event.OrderId, event.Type, event.FillQty, event.FillPrice, event.Commission)
+//	}
+type OrderTracker struct {
+	client *Client
+	opts   OrderTrackerOptions
+
+	mu     sync.Mutex
+	orders []*trackedOrder
+
+	// lastTradeIds holds, per market, the last trade id GetUserTrades
+	// returned.
+	lastTradeIds map[string]int64
+
+	// pollMu serializes polls; the state of tracked orders is only
+	// touched while it is held.
+	pollMu sync.Mutex
+
+	subsMu sync.Mutex
+	subs   map[*orderSubscriber]struct{}
+}
+
+// trackedOrder is the reported state of one tracked order.
+type trackedOrder struct {
+	symbol        t.BaseSymbolParams
+	orderId       int64
+	clientOrderId string
+	registered    time.Time
+
+	seen       bool
+	stage      OrderEventType
+	executed   t.Decimal
+	quote      t.Decimal
+	commission map[string]t.Decimal
+
+	tradeIds map[int64]bool
+	tradeQty t.Decimal
+	pending  []t.UserTradeResponse
+	waited   int
+	removed  bool
+}
+
+type orderSubscriber struct {
+	ch     chan OrderEvent
+	done   chan struct{}
+	sendMu sync.Mutex
+	closed bool
+}
+
+// NewOrderTracker creates an idle tracker. Orders are polled by Start or
+// by calling Poll.
+func (c *Client) NewOrderTracker(opts OrderTrackerOptions) *OrderTracker {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.FillGrace == 0 {
+		opts.FillGrace = 3
+	}
+
+	return &OrderTracker{
+		client:       c,
+		opts:         opts,
+		lastTradeIds: make(map[string]int64),
+		subs:         make(map[*orderSubscriber]struct{}),
+	}
+}
+
+// Track starts tracking an order, addressed by symbol and OrderId or
+// OrigClientOrderId. Tracking an order twice has no effect.
+func (tr *OrderTracker) Track(params t.GetOrderStatusParams) error {
+	if err := requireSymbol(params.BaseSymbolParams, ""); err != nil {
+		return err
+	}
+	if params.OrderId == 0 && params.OrigClientOrderId == "" {
+		return &GoTabdealError{Message: "orderId or origClientOrderId is required"}
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.findLocked(params) != nil {
+		return nil
+	}
+
+	tr.orders = append(tr.orders, &trackedOrder{
+		symbol:        params.BaseSymbolParams,
+		orderId:       params.OrderId,
+		clientOrderId: params.OrigClientOrderId,
+		registered:    time.Now(),
+		commission:    make(map[string]t.Decimal),
+		tradeIds:      make(map[int64]bool),
+	})
+	return nil
+}
+
+// Untrack stops tracking an order without a final event. It reports
+// whether the order was tracked.
+func (tr *OrderTracker) Untrack(params t.GetOrderStatusParams) bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	order := tr.findLocked(params)
+	if order == nil {
+		return false
+	}
+	tr.removeLocked(order)
+	return true
+}
+
+// Len returns the number of tracked orders.
+func (tr *OrderTracker) Len() int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return len(tr.orders)
+}
+
+// Subscribe returns a channel receiving every OrderEvent and a function
+// that unsubscribes and closes the channel. buffer sets the channel
+// capacity (default 64).
+func (tr *OrderTracker) Subscribe(buffer int) (<-chan OrderEvent, func()) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+
+	sub := &orderSubscriber{
+		ch:   make(chan OrderEvent, buffer),
+		done: make(chan struct{}),
+	}
+
+	tr.subsMu.Lock()
+	tr.subs[sub] = struct{}{}
+	tr.subsMu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			tr.subsMu.Lock()
+			delete(tr.subs, sub)
+			tr.subsMu.Unlock()
+
+			close(sub.done)
+			sub.sendMu.Lock()
+			sub.closed = true
+			close(sub.ch)
+			sub.sendMu.Unlock()
+		})
+	}
+}
+
+// Start polls the tracked orders every Interval in the background until
+// ctx is done. Errors go to OnError.
+func (tr *OrderTracker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(tr.opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := tr.Poll(ctx); err != nil && tr.opts.OnError != nil {
+					tr.opts.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Poll checks every tracked order once and delivers the resulting events
+// before it returns. A failed request skips the orders it covers until the
+// next poll; the errors are returned joined.
+func (tr *OrderTracker) Poll(ctx context.Context) error {
+	tr.pollMu.Lock()
+	defer tr.pollMu.Unlock()
+
+	tr.mu.Lock()
+	var keys []string
+	groups := make(map[string][]*trackedOrder)
+	for _, order := range tr.orders {
+		key := emulatorSymbol(cmp.Or(order.symbol.Symbol, order.symbol.TabdealSymbol))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], order)
+	}
+	tr.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		if err := tr.pollSymbol(ctx, key, groups[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pollSymbol polls the tracked orders of one market.
+func (tr *OrderTracker) pollSymbol(ctx context.Context, key string, orders []*trackedOrder) error {
+	symbol := orders[0].symbol
+
+	open, err := tr.client.GetOpenOrdersCtx(ctx, t.GetOpenOrdersParams{BaseSymbolParams: symbol})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	snapshots := make(map[*trackedOrder]t.BaseOrderResponse, len(orders))
+	for _, order := range orders {
+		if snapshot, ok := findOpenOrder(derefPage(open), order); ok {
+			snapshots[order] = snapshot
+			continue
+		}
+
+		lookup := t.GetOrderStatusParams{BaseSymbolParams: order.symbol, OrderId: order.orderId}
+		if lookup.OrderId == 0 {
+			lookup.OrigClientOrderId = order.clientOrderId
+		}
+		status, err := tr.client.GetOrderStatusCtx(ctx, lookup)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if status != nil {
+			snapshots[order] = status.BaseOrderResponse
+		}
+	}
+
+	behind := false
+	for order, snapshot := range snapshots {
+		order.orderId = cmp.Or(order.orderId, snapshot.OrderId)
+		order.clientOrderId = cmp.Or(order.clientOrderId, snapshot.ClientOrderId)
+
+		// Fills from before the order was tracked may predate the
+		// market's trade cursor, so they are fetched by order id.
+		if !order.seen && snapshot.ExecutedQty.IsPositive() {
+			if err := tr.fetchOrderTrades(ctx, order); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		covered := order.tradeQty
+		for _, trade := range order.pending {
+			covered = covered.Add(trade.Qty)
+		}
+		if snapshot.ExecutedQty.GreaterThan(covered) {
+			behind = true
+		}
+	}
+
+	if behind {
+		if err := tr.fetchTrades(ctx, key, symbol, orders, snapshots); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, order := range orders {
+		snapshot, ok := snapshots[order]
+		if !ok {
+			continue
+		}
+		if event, ok := tr.advance(order, snapshot); ok {
+			tr.publish(ctx, event)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fetchTrades reads the market's trades since the last one seen and
+// queues them on the orders they belong to.
+func (tr *OrderTracker) fetchTrades(ctx context.Context, key string, symbol t.BaseSymbolParams, orders []*trackedOrder, snapshots map[*trackedOrder]t.BaseOrderResponse) error {
+	tr.mu.Lock()
+	lastTradeId := tr.lastTradeIds[key]
+	tr.mu.Unlock()
+
+	params := t.GetUserTradesParams{}
+	params.BaseSymbolParams = symbol
+	params.Limit = historyPageSize
+	if lastTradeId > 0 {
+		from := lastTradeId + 1
+		params.FromId = &from
+	} else {
+		// Nothing seen yet: start at the oldest order, which cannot have
+		// traded before it was placed.
+		var since int64
+		for _, order := range orders {
+			placed := order.registered.UnixMilli()
+			if snapshot, ok := snapshots[order]; ok {
+				placed = cmp.Or(snapshot.Time, snapshot.TransactTime, placed)
+			}
+			if since == 0 || placed < since {
+				since = placed
+			}
+		}
+		params.StartTime = since - time.Second.Milliseconds()
+	}
+
+	byId := make(map[int64]*trackedOrder, len(orders))
+	for _, order := range orders {
+		if order.orderId != 0 {
+			byId[order.orderId] = order
+		}
+	}
+
+	for {
+		page, err := tr.client.GetUserTradesCtx(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		trades := derefPage(page)
+		for _, trade := range trades {
+			lastTradeId = max(lastTradeId, trade.Id)
+			if order, ok := byId[trade.OrderId]; ok {
+				order.addTrade(*trade)
+			}
+		}
+
+		tr.mu.Lock()
+		tr.lastTradeIds[key] = lastTradeId
+		tr.mu.Unlock()
+
+		if len(trades) < historyPageSize {
+			return nil
+		}
+		from := lastTradeId + 1
+		params.FromId = &from
+		params.StartTime = 0
+	}
+}
+
+// fetchOrderTrades reads every trade of one order.
+func (tr *OrderTracker) fetchOrderTrades(ctx context.Context, order *trackedOrder) error {
+	params := t.GetUserTradesParams{OrderId: order.orderId}
+	params.BaseSymbolParams = order.symbol
+	params.Limit = historyPageSize
+
+	for {
+		page, err := tr.client.GetUserTradesCtx(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		trades := derefPage(page)
+		from := derefId(params.FromId)
+		for _, trade := range trades {
+			if trade.OrderId == order.orderId {
+				order.addTrade(*trade)
+			}
+			from = max(from, trade.Id+1)
+		}
+		params.FromId = &from
+
+		if len(trades) < historyPageSize {
+			return nil
+		}
+	}
+}
+
+// addTrade queues a trade for the order's next event, once.
+func (o *trackedOrder) addTrade(trade t.UserTradeResponse) {
+	if o.tradeIds[trade.Id] {
+		return
+	}
+	o.tradeIds[trade.Id] = true
+	o.pending = append(o.pending, trade)
+}
+
+// advance moves an order to its snapshot and returns the event to send,
+// if any.
+func (tr *OrderTracker) advance(order *trackedOrder, snapshot t.BaseOrderResponse) (OrderEvent, bool) {
+	stage, ok := orderEventType(snapshot.Status, snapshot.ExecutedQty)
+	if !ok || (order.seen && order.stage.IsFinal()) || snapshot.ExecutedQty.LessThan(order.executed) {
+		return OrderEvent{}, false
+	}
+
+	filled := snapshot.ExecutedQty.GreaterThan(order.executed)
+	if order.seen && !filled && stage == order.stage {
+		return OrderEvent{}, false
+	}
+
+	// Give the trades behind a fill a few polls to show up.
+	tradeQty := order.tradeQty
+	tradeQuote := t.Decimal{}
+	for _, trade := range order.pending {
+		tradeQty = tradeQty.Add(trade.Qty)
+		tradeQuote = tradeQuote.Add(tradeQuoteQty(trade))
+	}
+	if filled && tradeQty.LessThan(snapshot.ExecutedQty) && order.waited < tr.opts.FillGrace {
+		order.waited++
+		return OrderEvent{}, false
+	}
+
+	quote := snapshot.CummulativeQuoteQty
+	if quote.IsZero() {
+		quote = snapshot.CumulativeQuoteQty
+	}
+	if quote.IsZero() && snapshot.ExecutedQty.IsPositive() {
+		quote = order.quote.Add(tradeQuote)
+	}
+
+	event := OrderEvent{
+		Type:               stage,
+		Symbol:             order.symbol,
+		OrderId:            order.orderId,
+		ClientOrderId:      order.clientOrderId,
+		Status:             snapshot.Status,
+		FillQty:            snapshot.ExecutedQty.Sub(order.executed).Normalize(),
+		FillQuoteQty:       quote.Sub(order.quote).Normalize(),
+		Commission:         make(map[string]t.Decimal),
+		Trades:             order.pending,
+		ExecutedQty:        snapshot.ExecutedQty,
+		CumulativeQuoteQty: quote,
+		AvgPrice:           averagePrice(quote, snapshot.ExecutedQty),
+		TotalCommission:    make(map[string]t.Decimal, len(order.commission)),
+		Order:              snapshot,
+		Time:               time.Now(),
+	}
+	event.FillPrice = averagePrice(event.FillQuoteQty, event.FillQty)
+
+	for _, trade := range order.pending {
+		event.Commission[trade.CommissionAsset] = event.Commission[trade.CommissionAsset].Add(trade.Commission)
+		order.commission[trade.CommissionAsset] = order.commission[trade.CommissionAsset].Add(trade.Commission)
+		order.tradeQty = order.tradeQty.Add(trade.Qty)
+	}
+	for asset, commission := range order.commission {
+		event.TotalCommission[asset] = commission
+	}
+
+	order.seen = true
+	order.stage = stage
+	order.executed = snapshot.ExecutedQty
+	order.quote = quote
+	order.pending = nil
+	order.waited = 0
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if order.removed {
+		return OrderEvent{}, false
+	}
+	if stage.IsFinal() {
+		tr.removeLocked(order)
+	}
+	return event, true
+}
+
+// publish delivers an event to every subscriber, waiting for those whose
+// buffer is full until they receive it, unsubscribe or ctx is done.
+func (tr *OrderTracker) publish(ctx context.Context, event OrderEvent) {
+	tr.subsMu.Lock()
+	subs := make([]*orderSubscriber, 0, len(tr.subs))
+	for sub := range tr.subs {
+		subs = append(subs, sub)
+	}
+	tr.subsMu.Unlock()
+
+	for _, sub := range subs {
+		sub.sendMu.Lock()
+		if !sub.closed {
+			select {
+			case sub.ch <- event:
+			case <-sub.done:
+			case <-ctx.Done():
+			}
+		}
+		sub.sendMu.Unlock()
+	}
+}
+
+func (tr *OrderTracker) findLocked(params t.GetOrderStatusParams) *trackedOrder {
+	key := emulatorSymbol(cmp.Or(params.Symbol, params.TabdealSymbol))
+	for _, order := range tr.orders {
+		if key != "" && emulatorSymbol(cmp.Or(order.symbol.Symbol, order.symbol.TabdealSymbol)) != key {
+			continue
+		}
+		if params.OrderId != 0 && order.orderId == params.OrderId ||
+			params.OrigClientOrderId != "" && order.clientOrderId == params.OrigClientOrderId {
+			return order
+		}
+	}
+	return nil
+}
+
+func (tr *OrderTracker) removeLocked(order *trackedOrder) {
+	order.removed = true
+	tr.orders = slices.DeleteFunc(tr.orders, func(o *trackedOrder) bool { return o == order })
+}
+
+// findOpenOrder finds a tracked order among a market's open orders.
+func findOpenOrder(open []*t.BaseOrderResponse, order *trackedOrder) (t.BaseOrderResponse, bool) {
+	for _, candidate := range open {
+		if order.orderId != 0 && candidate.OrderId == order.orderId ||
+			order.orderId == 0 && candidate.ClientOrderId == order.clientOrderId {
+			return *candidate, true
+		}
+	}
+	return t.BaseOrderResponse{}, false
+}
+
+// orderEventType maps an order status to its lifecycle stage. It reports
+// false for unknown statuses.
+func orderEventType(status t.OrderStatus, executed t.Decimal) (OrderEventType, bool) {
+	switch status {
+	case t.OrderStatusFilled:
+		return OrderEventFilled, true
+	case t.OrderStatusCanceled:
+		return OrderEventCanceled, true
+	case t.OrderStatusRejected:
+		return OrderEventRejected, true
+	case t.OrderStatusExpired, t.OrderStatusExpiredInMatch:
+		return OrderEventExpired, true
+	}
+
+	if !status.IsOpen() {
+		return 0, false
+	}
+	if executed.IsPositive() {
+		return OrderEventPartiallyFilled, true
+	}
+	return OrderEventNew, true
+}
+
+// tradeQuoteQty returns the quote quantity of a trade, computing it when
+// the server left it out.
+func tradeQuoteQty(trade t.UserTradeResponse) t.Decimal {
+	if !trade.QuoteQty.IsZero() {
+		return trade.QuoteQty
+	}
+	return trade.Price.Mul(trade.Qty)
+}
+
+func averagePrice(quote, quantity t.Decimal) t.Decimal {
+	if !quantity.IsPositive() {
+		return t.Decimal{}
+	}
+	return quote.DivRound(quantity, trackerScale, t.RoundNearest).Normalize()
+}
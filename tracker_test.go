@@ -0,0 +1,245 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// trackerServer reports one order and the account's trades, and serves
+// /openOrders, /order and /myTrades from them.
+type trackerServer struct {
+	mu     sync.Mutex
+	order  t.BaseOrderResponse
+	trades []t.UserTradeResponse
+}
+
+func (s *trackerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/openOrders"):
+		open := []t.BaseOrderResponse{}
+		if s.order.Status.IsOpen() {
+			open = append(open, s.order)
+		}
+		_ = json.NewEncoder(w).Encode(open)
+
+	case strings.HasSuffix(r.URL.Path, "/order"):
+		_ = json.NewEncoder(w).Encode(s.order)
+
+	case strings.HasSuffix(r.URL.Path, "/myTrades"):
+		fromId, _ := strconv.ParseInt(query.Get("fromId"), 10, 64)
+		orderId, _ := strconv.ParseInt(query.Get("orderId"), 10, 64)
+		trades := []t.UserTradeResponse{}
+		for _, trade := range s.trades {
+			if trade.Id >= fromId && (orderId == 0 || trade.OrderId == orderId) {
+				trades = append(trades, trade)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(trades)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// set updates the order and adds trades.
+func (s *trackerServer) set(status t.OrderStatus, executed, quote string, trades ...t.UserTradeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.order.Status = status
+	s.order.ExecutedQty = t.MustParseDecimal(executed)
+	s.order.CummulativeQuoteQty = t.MustParseDecimal(quote)
+	s.trades = append(s.trades, trades...)
+}
+
+func trade(id, orderId int64, price, qty, commission, asset string) t.UserTradeResponse {
+	return t.UserTradeResponse{
+		Id:              id,
+		OrderId:         orderId,
+		Price:           t.MustParseDecimal(price),
+		Qty:             t.MustParseDecimal(qty),
+		Commission:      t.MustParseDecimal(commission),
+		CommissionAsset: asset,
+	}
+}
+
+func newTracker(tt *testing.T, exchange *trackerServer, opts OrderTrackerOptions) *OrderTracker {
+	tt.Helper()
+
+	exchange.order.Symbol = "BTCIRT"
+	exchange.order.OrderId = 9
+	exchange.order.ClientOrderId = "order-9"
+
+	srv := httptest.NewServer(exchange)
+	tt.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	tracker := client.NewOrderTracker(opts)
+	if err := tracker.Track(t.GetOrderStatusParams{BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"}, OrderId: 9}); err != nil {
+		tt.Fatal(err)
+	}
+	return tracker
+}
+
+// poll polls once and returns the events it delivered.
+func poll(tt *testing.T, tracker *OrderTracker, events <-chan OrderEvent) []OrderEvent {
+	tt.Helper()
+
+	if err := tracker.Poll(context.Background()); err != nil {
+		tt.Fatal(err)
+	}
+
+	var delivered []OrderEvent
+	for {
+		select {
+		case event := <-events:
+			delivered = append(delivered, event)
+		default:
+			return delivered
+		}
+	}
+}
+
+// commissions formats a commission map for comparison.
+func commissions(m map[string]t.Decimal) string {
+	var parts []string
+	for _, asset := range []string{"BTC", "IRT"} {
+		if value, ok := m[asset]; ok {
+			parts = append(parts, asset+"="+value.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestOrderTrackerLifecycle(tt *testing.T) {
+	exchange := &trackerServer{}
+	exchange.set(t.OrderStatusNew, "0", "0")
+	tracker := newTracker(tt, exchange, OrderTrackerOptions{FillGrace: 2})
+	events, stop := tracker.Subscribe(0)
+	defer stop()
+
+	type want struct {
+		typ                            OrderEventType
+		fillQty, fillPrice, commission string
+		executed, avgPrice, total      string
+		trades                         int
+	}
+	check := func(got []OrderEvent, want want) {
+		tt.Helper()
+
+		if len(got) != 1 {
+			tt.Fatalf("%d events, want one %s", len(got), want.typ)
+		}
+		e := got[0]
+		if e.Type != want.typ || e.OrderId != 9 || e.ClientOrderId != "order-9" ||
+			e.FillQty.String() != want.fillQty || e.FillPrice.String() != want.fillPrice ||
+			commissions(e.Commission) != want.commission || len(e.Trades) != want.trades ||
+			e.ExecutedQty.String() != want.executed || e.AvgPrice.String() != want.avgPrice ||
+			commissions(e.TotalCommission) != want.total {
+			tt.Errorf("event %s: fill %s at %s, commission %q, %d trades, executed %s at %s, total commission %q; want %+v",
+				e.Type, e.FillQty, e.FillPrice, commissions(e.Commission), len(e.Trades), e.ExecutedQty, e.AvgPrice, commissions(e.TotalCommission), want)
+		}
+	}
+
+	check(poll(tt, tracker, events), want{typ: OrderEventNew, fillQty: "0", fillPrice: "0", executed: "0", avgPrice: "0"})
+	if got := poll(tt, tracker, events); len(got) != 0 {
+		tt.Errorf("unchanged order reported %d events", len(got))
+	}
+
+	exchange.set(t.OrderStatusPartiallyFilled, "0.4", "40", trade(1, 9, "100", "0.4", "0.0004", "BTC"), trade(2, 8, "100", "5", "0.005", "BTC"))
+	check(poll(tt, tracker, events), want{
+		typ: OrderEventPartiallyFilled, fillQty: "0.4", fillPrice: "100", commission: "BTC=0.0004",
+		executed: "0.4", avgPrice: "100", total: "BTC=0.0004", trades: 1,
+	})
+
+	// The rest fills before its trades are visible: the event waits.
+	exchange.set(t.OrderStatusFilled, "1.0", "106")
+	if got := poll(tt, tracker, events); len(got) != 0 {
+		tt.Fatalf("fill reported before its trades: %+v", got)
+	}
+
+	exchange.set(t.OrderStatusFilled, "1.0", "106", trade(3, 9, "110", "0.5", "0.0005", "BTC"), trade(4, 9, "110", "0.1", "11", "IRT"))
+	check(poll(tt, tracker, events), want{
+		typ: OrderEventFilled, fillQty: "0.6", fillPrice: "110", commission: "BTC=0.0005 IRT=11",
+		executed: "1.0", avgPrice: "106", total: "BTC=0.0009 IRT=11", trades: 2,
+	})
+
+	if n := tracker.Len(); n != 0 {
+		tt.Errorf("%d orders still tracked after FILLED", n)
+	}
+}
+
+func TestOrderTrackerFillGraceExpires(tt *testing.T) {
+	exchange := &trackerServer{}
+	exchange.set(t.OrderStatusNew, "0", "0")
+	tracker := newTracker(tt, exchange, OrderTrackerOptions{FillGrace: 1})
+	events, stop := tracker.Subscribe(0)
+	defer stop()
+
+	poll(tt, tracker, events)
+
+	exchange.set(t.OrderStatusPartiallyFilled, "0.5", "50")
+	if got := poll(tt, tracker, events); len(got) != 0 {
+		tt.Fatalf("%d events during the grace poll", len(got))
+	}
+
+	// Without its trades after FillGrace polls, the fill is sent anyway.
+	got := poll(tt, tracker, events)
+	if len(got) != 1 || got[0].Type != OrderEventPartiallyFilled || got[0].FillQty.String() != "0.5" || len(got[0].Trades) != 0 {
+		tt.Fatalf("events = %+v, want the fill without trades", got)
+	}
+
+	// The late trade comes with the next event.
+	exchange.set(t.OrderStatusCanceled, "0.5", "50", trade(1, 9, "100", "0.5", "0.0005", "BTC"))
+	got = poll(tt, tracker, events)
+	if len(got) != 1 || got[0].Type != OrderEventCanceled || !got[0].FillQty.IsZero() ||
+		len(got[0].Trades) != 1 || commissions(got[0].Commission) != "BTC=0.0005" {
+		tt.Fatalf("events = %+v, want CANCELED carrying the late trade", got)
+	}
+}
+
+func TestOrderTrackerSubscribers(tt *testing.T) {
+	exchange := &trackerServer{}
+	exchange.set(t.OrderStatusNew, "0", "0")
+	tracker := newTracker(tt, exchange, OrderTrackerOptions{})
+
+	first, stopFirst := tracker.Subscribe(1)
+	second, stopSecond := tracker.Subscribe(0)
+	defer stopSecond()
+
+	if err := tracker.Poll(context.Background()); err != nil {
+		tt.Fatal(err)
+	}
+	for i, events := range []<-chan OrderEvent{first, second} {
+		if event := <-events; event.Type != OrderEventNew {
+			tt.Errorf("subscriber %d got %s", i, event.Type)
+		}
+	}
+
+	stopFirst()
+	if _, ok := <-first; ok {
+		tt.Fatal("channel open after unsubscribing")
+	}
+
+	// The unsubscribed channel must not hold up delivery.
+	exchange.set(t.OrderStatusRejected, "0", "0")
+	if got := poll(tt, tracker, second); len(got) != 1 || got[0].Type != OrderEventRejected {
+		tt.Errorf("events = %+v, want REJECTED", got)
+	}
+}
@@ -0,0 +1,120 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// aggTradesWindow is the widest startTime–endTime span GET /aggTrades
+// accepts.
+const aggTradesWindow = time.Hour
+
+// BackfillTrades reconstructs the complete public trade tape of a market
+// between two timestamps.
+//
+// Params:
+//   - params.Symbol (required).
+//   - params.StartTime (required): Unix ms, inclusive.
+//   - params.EndTime (optional): Unix ms, inclusive; defaults to now.
+//
+// Returns:
+//   - every trade with StartTime <= Time <= EndTime, oldest first, without
+//     gaps or duplicates.
+//   - the error of the first failed request; trades fetched before it are
+//     discarded.
+//
+// Behavior:
+//   - GET /aggTrades is scanned hour by hour to find the first trade at or
+//     after StartTime, then GET /historicalTrades is paged by id until a
+//     trade past EndTime appears or the latest trade is reached.
+//   - Pages are paced by the client's rate limiter like any other call;
+//     /historicalTrades pages weigh 25, so long ranges on busy markets
+//     take a while.
+//
+// Example:
+//
+//	tape, err := client.BackfillTrades(t.BackfillTradesParams{
+//	    BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+//	    StartTime:        fill.Time - 60_000,
+//	    EndTime:          fill.Time + 60_000,
+//	})
+func (c *Client) BackfillTrades(params t.BackfillTradesParams) ([]*t.Trade, error) {
+	return c.BackfillTradesCtx(context.Background(), params)
+}
+
+// BackfillTradesCtx is BackfillTrades with a caller-supplied context; see RequestCtx.
+func (c *Client) BackfillTradesCtx(ctx context.Context, params t.BackfillTradesParams) ([]*t.Trade, error) {
+	if params.StartTime <= 0 {
+		return nil, &GoTabdealError{Message: "BackfillTrades requires a StartTime"}
+	}
+	if params.EndTime <= 0 {
+		params.EndTime = time.Now().UnixMilli()
+	}
+	if params.EndTime < params.StartTime {
+		return nil, &GoTabdealError{Message: "BackfillTrades EndTime is before StartTime"}
+	}
+
+	fromId, found, err := c.firstTradeIdAfter(ctx, params)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var tape []*t.Trade
+	for {
+		page, err := c.GetHistoricalTradesCtx(ctx, t.GetHistoricalTradesParams{
+			BaseSymbolParams: params.BaseSymbolParams,
+			FromId:           fromId,
+			Limit:            historyPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		trades := derefPage(page)
+		slices.SortFunc(trades, func(a, b *t.Trade) int { return cmp.Compare(a.Id, b.Id) })
+
+		for _, trade := range trades {
+			if trade.Id < fromId {
+				continue
+			}
+			if trade.Time > params.EndTime {
+				return tape, nil
+			}
+			if trade.Time >= params.StartTime {
+				tape = append(tape, trade)
+			}
+			fromId = trade.Id + 1
+		}
+
+		if len(trades) < historyPageSize {
+			return tape, nil
+		}
+	}
+}
+
+// firstTradeIdAfter returns the id of the first trade in the range of
+// params, scanning GET /aggTrades one window at a time. found is false
+// when the range has no trades.
+func (c *Client) firstTradeIdAfter(ctx context.Context, params t.BackfillTradesParams) (id int64, found bool, err error) {
+	for start := params.StartTime; start <= params.EndTime; start += aggTradesWindow.Milliseconds() {
+		aggs, err := c.GetAggTradesCtx(ctx, t.GetAggTradesParams{
+			BaseSymbolParams: params.BaseSymbolParams,
+			StartTime:        start,
+			EndTime:          min(start+aggTradesWindow.Milliseconds()-1, params.EndTime),
+			Limit:            1,
+		})
+		if err != nil {
+			return 0, false, err
+		}
+
+		if first := derefPage(aggs); len(first) > 0 {
+			return first[0].FirstTradeId, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
@@ -0,0 +1,117 @@
+package types
+
+import "strings"
+
+// Account describes the authenticated user's spot account, as returned by
+// GET /account: balances, commission rates and trading permissions.
+//
+// MakerCommission and friends are expressed in basis points (10 = 0.1%);
+// CommissionRates carries the same rates as fractions (0.001 = 0.1%). Use
+// MakerRate and TakerRate to read whichever the server filled in.
+type Account struct {
+	MakerCommission  int64           `json:"makerCommission"`
+	TakerCommission  int64           `json:"takerCommission"`
+	BuyerCommission  int64           `json:"buyerCommission"`
+	SellerCommission int64           `json:"sellerCommission"`
+	CommissionRates  CommissionRates `json:"commissionRates"`
+	CanTrade         bool            `json:"canTrade"`
+	CanWithdraw      bool            `json:"canWithdraw"`
+	CanDeposit       bool            `json:"canDeposit"`
+	UpdateTime       int64           `json:"updateTime"`
+	AccountType      string          `json:"accountType"`
+	Balances         []Balance       `json:"balances"`
+	Permissions      []string        `json:"permissions"`
+}
+
+// CommissionRates holds an account's commission rates as fractions of the
+// traded amount, e.g. 0.001 for 0.1%.
+type CommissionRates struct {
+	Maker  Decimal `json:"maker"`
+	Taker  Decimal `json:"taker"`
+	Buyer  Decimal `json:"buyer"`
+	Seller Decimal `json:"seller"`
+}
+
+// Balance is the spot balance of one asset. Free is available for new
+// orders and withdrawals; Locked is held by open orders or pending
+// operations.
+type Balance struct {
+	Asset  string  `json:"asset"`
+	Free   Decimal `json:"free"`
+	Locked Decimal `json:"locked"`
+}
+
+// Total returns Free + Locked.
+func (b Balance) Total() Decimal {
+	return b.Free.Add(b.Locked)
+}
+
+// Balance returns the balance of asset, matched case-insensitively. It
+// reports false when the account has no entry for the asset.
+func (a *Account) Balance(asset string) (Balance, bool) {
+	for _, balance := range a.Balances {
+		if strings.EqualFold(balance.Asset, asset) {
+			return balance, true
+		}
+	}
+	return Balance{}, false
+}
+
+// Free returns the available balance of asset, or zero when the account
+// has no entry for it.
+//
+// Example:
+//
+//	need := price.Mul(quantity)
+//	if account.Free("IRT").LessThan(need) {
+//	    return errors.New("insufficient IRT")
+//	}
+func (a *Account) Free(asset string) Decimal {
+	balance, _ := a.Balance(asset)
+	return balance.Free
+}
+
+// Locked returns the locked balance of asset, or zero when the account has
+// no entry for it.
+func (a *Account) Locked(asset string) Decimal {
+	balance, _ := a.Balance(asset)
+	return balance.Locked
+}
+
+// NonZeroBalances returns the balances with a positive free or locked
+// amount.
+func (a *Account) NonZeroBalances() []Balance {
+	var balances []Balance
+	for _, balance := range a.Balances {
+		if balance.Free.IsPositive() || balance.Locked.IsPositive() {
+			balances = append(balances, balance)
+		}
+	}
+	return balances
+}
+
+// MakerRate returns the maker commission as a fraction, preferring
+// CommissionRates.Maker and falling back to MakerCommission.
+func (a *Account) MakerRate() Decimal {
+	return commissionRate(a.CommissionRates.Maker, a.MakerCommission)
+}
+
+// TakerRate returns the taker commission as a fraction, preferring
+// CommissionRates.Taker and falling back to TakerCommission.
+func (a *Account) TakerRate() Decimal {
+	return commissionRate(a.CommissionRates.Taker, a.TakerCommission)
+}
+
+func commissionRate(rate Decimal, bps int64) Decimal {
+	if !rate.IsZero() {
+		return rate
+	}
+	return NewDecimal(bps, 4).Normalize()
+}
+
+// GetAccountParams defines the optional query parameters of GET /account.
+// OmitZeroBalances drops assets whose free and locked balances are both
+// zero from the response.
+type GetAccountParams struct {
+	OmitZeroBalances bool `json:"omitZeroBalances,omitempty"`
+}
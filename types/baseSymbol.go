@@ -20,6 +20,10 @@ package types
 //   - Prefer `symbol` for trade, order, and market-data endpoints.
 //   - Use `tabdealSymbol` only when an endpoint explicitly documents it.
 //
+// Market values convert to BaseSymbolParams with Market.SymbolParams or
+// Market.TabdealSymbolParams, and BaseSymbolParams.Market parses the pair
+// back into base and quote assets.
+//
 // Fields are tagged with `omitempty` so they are omitted when empty,
 // matching Tabdeal's expectations for signed request construction.
 type BaseSymbolParams struct {
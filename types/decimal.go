@@ -0,0 +1,490 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number used for prices,
+// quantities and balances.
+//
+// Tabdeal transmits monetary values as strings such as "12345678900.50"
+// to avoid floating-point loss; IRT prices easily exceed the 15–17
+// significant digits a float64 can hold. Decimal keeps every digit.
+//
+// A Decimal is the value coefficient × 10^-scale. The scale is preserved
+// through parsing and formatting, so "0.10000000" round-trips unchanged.
+// The zero value is a valid 0 with scale 0.
+//
+// Decimal values are immutable: every operation returns a new value.
+//
+// JSON and text encoding:
+//   - Marshals as a quoted string ("0.001").
+//   - Unmarshals from a string, a bare JSON number, or null. Empty strings
+//     and null decode to zero.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// RoundingMode selects how values are rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundNearest rounds to the nearest value; ties round away from zero.
+	RoundNearest RoundingMode = iota
+
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+
+	// RoundCeil rounds toward positive infinity.
+	RoundCeil
+
+	// RoundDown rounds toward zero (truncation).
+	RoundDown
+)
+
+// maxExponent bounds the exponent accepted by ParseDecimal so malformed
+// input such as "1e999999999" cannot allocate unbounded memory.
+const maxExponent = 1 << 12
+
+var (
+	bigZero = big.NewInt(0)
+	bigOne  = big.NewInt(1)
+	bigTen  = big.NewInt(10)
+)
+
+// Zero is the Decimal value 0.
+var Zero = Decimal{}
+
+// NewDecimal returns coefficient × 10^-scale. A negative scale multiplies
+// the coefficient by the corresponding power of ten.
+//
+// Example:
+//
+//	NewDecimal(12345, 2) // 123.45
+func NewDecimal(coefficient int64, scale int32) Decimal {
+	d := Decimal{coef: big.NewInt(coefficient), scale: scale}
+	if scale < 0 {
+		d.coef.Mul(d.coef, pow10(int64(-scale)))
+		d.scale = 0
+	}
+	return d
+}
+
+// NewDecimalFromInt returns the integer value i.
+func NewDecimalFromInt(i int64) Decimal {
+	return Decimal{coef: big.NewInt(i)}
+}
+
+// NewDecimalFromFloat converts f using its shortest exact decimal
+// representation, so NewDecimalFromFloat(0.1) is exactly 0.1. It is meant
+// for migrating code that still holds float64 values.
+//
+// It panics if f is NaN or infinite.
+func NewDecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		panic(fmt.Sprintf("types: cannot convert %v to Decimal", f))
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "123", "-0.0015",
+// "1.5e-3" or "+42.10". Leading and trailing whitespace is ignored.
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %q", orig)
+	}
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || e > maxExponent || e < -maxExponent {
+			return Decimal{}, fmt.Errorf("types: invalid decimal %q", orig)
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %q", orig)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("types: invalid decimal %q", orig)
+		}
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %q", orig)
+	}
+	if neg {
+		coef.Neg(coef)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale < 0 {
+		coef.Mul(coef, pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input. It is
+// intended for constants and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+// coefficient returns the coefficient, treating nil as zero.
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return bigZero
+	}
+	return d.coef
+}
+
+// rescaled returns the coefficient expressed at a larger scale.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	c := d.coefficient()
+	if scale == d.scale {
+		return c
+	}
+	return new(big.Int).Mul(c, pow10(int64(scale-d.scale)))
+}
+
+// align returns both coefficients at a common scale.
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := max(a.scale, b.scale)
+	return a.rescaled(scale), b.rescaled(scale), scale
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+// IsZero reports whether d equals zero. It is also used by the request
+// encoder to honor `omitempty`.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsNegative reports whether d < 0.
+func (d Decimal) IsNegative() bool {
+	return d.Sign() < 0
+}
+
+// IsPositive reports whether d > 0.
+func (d Decimal) IsPositive() bool {
+	return d.Sign() > 0
+}
+
+// Cmp compares d and other and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other represent the same number, regardless
+// of scale ("1.50" equals "1.5").
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// LessThan reports whether d < other.
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.Cmp(other) < 0
+}
+
+// LessThanOrEqual reports whether d <= other.
+func (d Decimal) LessThanOrEqual(other Decimal) bool {
+	return d.Cmp(other) <= 0
+}
+
+// GreaterThan reports whether d > other.
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.Cmp(other) > 0
+}
+
+// GreaterThanOrEqual reports whether d >= other.
+func (d Decimal) GreaterThanOrEqual(other Decimal) bool {
+	return d.Cmp(other) >= 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), scale: d.scale}
+}
+
+// Add returns d + other. The result scale is the larger of both scales.
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: new(big.Int).Add(a, b), scale: scale}
+}
+
+// Sub returns d - other. The result scale is the larger of both scales.
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: new(big.Int).Sub(a, b), scale: scale}
+}
+
+// Mul returns d × other exactly. The result scale is the sum of both scales.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{
+		coef:  new(big.Int).Mul(d.coefficient(), other.coefficient()),
+		scale: d.scale + other.scale,
+	}
+}
+
+// Div returns d / other rounded to places fractional digits with
+// RoundNearest. It panics if other is zero.
+func (d Decimal) Div(other Decimal, places int32) Decimal {
+	return d.DivRound(other, places, RoundNearest)
+}
+
+// DivRound returns d / other rounded to places fractional digits with the
+// given rounding mode. It panics if other is zero.
+func (d Decimal) DivRound(other Decimal, places int32, mode RoundingMode) Decimal {
+	if other.IsZero() {
+		panic("types: decimal division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	// d/other = (c1 / 10^s1) / (c2 / 10^s2); scaled by 10^places.
+	num := new(big.Int).Mul(d.coefficient(), pow10(int64(other.scale)+int64(places)))
+	den := new(big.Int).Mul(other.coefficient(), pow10(int64(d.scale)))
+
+	return Decimal{coef: divRound(num, den, mode), scale: places}
+}
+
+// divRound divides num by den and rounds the quotient with mode.
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// Sign of the exact quotient.
+	sign := num.Sign() * den.Sign()
+
+	switch mode {
+	case RoundFloor:
+		if sign < 0 {
+			q.Sub(q, bigOne)
+		}
+	case RoundCeil:
+		if sign > 0 {
+			q.Add(q, bigOne)
+		}
+	case RoundNearest:
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+		if twice.Cmp(new(big.Int).Abs(den)) >= 0 {
+			if sign < 0 {
+				q.Sub(q, bigOne)
+			} else {
+				q.Add(q, bigOne)
+			}
+		}
+	}
+
+	return q
+}
+
+// RoundMode returns d rounded to places fractional digits using mode.
+// When places is greater than the current scale, zeros are appended
+// instead, so the result always has exactly places fractional digits.
+func (d Decimal) RoundMode(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{coef: d.rescaled(places), scale: places}
+	}
+
+	return Decimal{
+		coef:  divRound(d.coefficient(), pow10(int64(d.scale-places)), mode),
+		scale: places,
+	}
+}
+
+// Round returns d rounded to places fractional digits, ties away from zero.
+func (d Decimal) Round(places int32) Decimal {
+	return d.RoundMode(places, RoundNearest)
+}
+
+// Truncate returns d with all digits after places fractional digits
+// dropped (rounding toward zero).
+func (d Decimal) Truncate(places int32) Decimal {
+	return d.RoundMode(places, RoundDown)
+}
+
+// Normalize returns d without trailing fractional zeros ("1.2500" → "1.25").
+func (d Decimal) Normalize() Decimal {
+	c := d.coefficient()
+	if c.Sign() == 0 {
+		return Decimal{}
+	}
+
+	coef := new(big.Int).Set(c)
+	scale := d.scale
+	r := new(big.Int)
+	for scale > 0 {
+		q, rem := new(big.Int).QuoRem(coef, bigTen, r)
+		if rem.Sign() != 0 {
+			break
+		}
+		coef = q
+		scale--
+	}
+
+	return Decimal{coef: coef, scale: scale}
+}
+
+// RoundToStep rounds d to an integer multiple of step using mode. The
+// result carries exactly the number of fractional digits of the
+// normalized step, so a tickSize of "0.01000000" yields values with two
+// decimals. A zero or negative step returns d unchanged.
+//
+// Example:
+//
+//	MustParseDecimal("0.123456").RoundToStep(MustParseDecimal("0.001"), RoundFloor) // 0.123
+func (d Decimal) RoundToStep(step Decimal, mode RoundingMode) Decimal {
+	if step.Sign() <= 0 {
+		return d
+	}
+
+	step = step.Normalize()
+	a, b, _ := align(d, step)
+	steps := divRound(a, b, mode)
+
+	return Decimal{coef: steps.Mul(steps, step.coefficient()), scale: step.scale}
+}
+
+// IsMultipleOf reports whether d is an exact multiple of step. A zero step
+// matches everything.
+func (d Decimal) IsMultipleOf(step Decimal) bool {
+	if step.IsZero() {
+		return true
+	}
+	a, b, _ := align(d, step)
+	return new(big.Int).Rem(a, b).Sign() == 0
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain notation with exactly Scale() fractional
+// digits, e.g. "-0.00150".
+func (d Decimal) String() string {
+	c := d.coefficient()
+	digits := new(big.Int).Abs(c).String()
+
+	if d.scale > 0 {
+		if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		cut := len(digits) - int(d.scale)
+		digits = digits[:cut] + "." + digits[cut:]
+	}
+
+	if c.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed returns d rounded (ties away from zero) to places fractional
+// digits and formatted with exactly that many.
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty input decodes
+// to zero.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts a JSON string, a JSON number or null.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("types: invalid decimal %s", data)
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+
+	return d.UnmarshalText(data)
+}
@@ -0,0 +1,327 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"123", "123"},
+		{"-0.0015", "-0.0015"},
+		{"+42.10", "42.10"},
+		{"0.10000000", "0.10000000"},
+		{" 7 ", "7"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"-0", "0"},
+		{"1.5e-3", "0.0015"},
+		{"1.25e1", "12.5"},
+		{"1E2", "100"},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDecimalBounds(t *testing.T) {
+	if d, err := ParseDecimal("1e4096"); err != nil || len(d.String()) != 4097 {
+		t.Errorf("ParseDecimal(1e4096) = %d digits, %v", len(d.String()), err)
+	}
+	if d, err := ParseDecimal("1e-4096"); err != nil || d.Scale() != 4096 {
+		t.Errorf("ParseDecimal(1e-4096) scale = %d, %v", d.Scale(), err)
+	}
+
+	for _, input := range []string{"1e4097", "1e-4097", "1e9999999999"} {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("ParseDecimal(%q) accepted an exponent out of bounds", input)
+		}
+	}
+}
+
+func TestParseDecimalErrors(t *testing.T) {
+	for _, input := range []string{"", " ", "-", ".", "abc", "1.2.3", "--1", "+-1", "1e", "e5", "1e1.5", "0x10", "1_000", "١"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseDecimal(input)
+			if err == nil || !strings.Contains(err.Error(), "invalid decimal") {
+				t.Errorf("ParseDecimal(%q) error = %v, want invalid decimal", input, err)
+			}
+		})
+	}
+}
+
+func TestDecimalRoundMode(t *testing.T) {
+	modes := []struct {
+		name string
+		mode RoundingMode
+	}{
+		{"nearest", RoundNearest},
+		{"floor", RoundFloor},
+		{"ceil", RoundCeil},
+		{"down", RoundDown},
+	}
+
+	tests := []struct {
+		input string
+		want  [4]string // nearest, floor, ceil, down
+	}{
+		{"2.5", [4]string{"3", "2", "3", "2"}},
+		{"-2.5", [4]string{"-3", "-3", "-2", "-2"}},
+		{"2.4", [4]string{"2", "2", "3", "2"}},
+		{"-2.4", [4]string{"-2", "-3", "-2", "-2"}},
+		{"2.6", [4]string{"3", "2", "3", "2"}},
+		{"-2.6", [4]string{"-3", "-3", "-2", "-2"}},
+		{"-2", [4]string{"-2", "-2", "-2", "-2"}},
+		{"0.4", [4]string{"0", "0", "1", "0"}},
+		{"-0.4", [4]string{"0", "-1", "0", "0"}},
+	}
+
+	for _, tt := range tests {
+		for i, m := range modes {
+			if got := MustParseDecimal(tt.input).RoundMode(0, m.mode).String(); got != tt.want[i] {
+				t.Errorf("%s rounded %s = %s, want %s", tt.input, m.name, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestDecimalRoundPlaces(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"fewer places", MustParseDecimal("1.23456").Round(3), "1.235"},
+		{"more places pad with zeros", MustParseDecimal("1.5").RoundMode(3, RoundFloor), "1.500"},
+		{"negative places round to integers", MustParseDecimal("1.5").RoundMode(-2, RoundNearest), "2"},
+		{"truncate", MustParseDecimal("-1.999").Truncate(2), "-1.99"},
+		{"carry into a new digit", MustParseDecimal("9.99").Round(1), "10.0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if got := MustParseDecimal("1.005").StringFixed(2); got != "1.01" {
+		t.Errorf("StringFixed(2) = %s, want 1.01", got)
+	}
+}
+
+func TestDecimalRoundToStep(t *testing.T) {
+	tests := []struct {
+		value string
+		step  string
+		mode  RoundingMode
+		want  string
+	}{
+		{"0.123456", "0.001", RoundFloor, "0.123"},
+		{"101.237", "0.01000000", RoundFloor, "101.23"},
+		{"101.237", "0.01000000", RoundCeil, "101.24"},
+		{"101.235", "0.01", RoundNearest, "101.24"},
+		{"101.23", "0.01", RoundCeil, "101.23"},
+		{"-101.237", "0.01", RoundFloor, "-101.24"},
+		{"-101.237", "0.01", RoundDown, "-101.23"},
+		{"-101.237", "0.01", RoundCeil, "-101.23"},
+		{"12", "5", RoundNearest, "10"},
+		{"12.5", "5", RoundNearest, "15"},
+		{"1.3", "0.5", RoundFloor, "1.0"},
+		{"1.3", "0", RoundFloor, "1.3"},
+		{"1.3", "-0.5", RoundFloor, "1.3"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.value).RoundToStep(MustParseDecimal(tt.step), tt.mode)
+		if got.String() != tt.want {
+			t.Errorf("%s.RoundToStep(%s, %d) = %s, want %s", tt.value, tt.step, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalIsMultipleOf(t *testing.T) {
+	tests := []struct {
+		value, step string
+		want        bool
+	}{
+		{"1.50", "0.5", true},
+		{"1.51", "0.5", false},
+		{"-3", "1.5", true},
+		{"7", "0", true},
+	}
+
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.value).IsMultipleOf(MustParseDecimal(tt.step)); got != tt.want {
+			t.Errorf("%s.IsMultipleOf(%s) = %v, want %v", tt.value, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalDivRound(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{"1", "3", 4, RoundNearest, "0.3333"},
+		{"2", "3", 4, RoundNearest, "0.6667"},
+		{"-2", "3", 4, RoundNearest, "-0.6667"},
+		{"-2", "3", 4, RoundFloor, "-0.6667"},
+		{"-2", "3", 4, RoundCeil, "-0.6666"},
+		{"-2", "3", 4, RoundDown, "-0.6666"},
+		{"2", "-3", 4, RoundFloor, "-0.6667"},
+		{"-2", "-3", 4, RoundFloor, "0.6666"},
+		{"10", "4", 0, RoundNearest, "3"},
+		{"-10", "4", 0, RoundNearest, "-3"},
+		{"1.5", "0.5", 2, RoundNearest, "3.00"},
+		{"0.001", "1000", 6, RoundNearest, "0.000001"},
+		{"7", "2", -1, RoundFloor, "3"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.a).DivRound(MustParseDecimal(tt.b), tt.places, tt.mode)
+		if got.String() != tt.want {
+			t.Errorf("%s.DivRound(%s, %d, %d) = %s, want %s", tt.a, tt.b, tt.places, tt.mode, got, tt.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("division by zero did not panic")
+		}
+	}()
+	MustParseDecimal("1").Div(Zero, 2)
+}
+
+func TestDecimalStringAndNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Decimal
+		want string
+		norm string
+	}{
+		{"zero value", Decimal{}, "0", "0"},
+		{"small negative", NewDecimal(-15, 4), "-0.0015", "-0.0015"},
+		{"negative scale", NewDecimal(5, -2), "500", "500"},
+		{"trailing zeros", MustParseDecimal("1.2500"), "1.2500", "1.25"},
+		{"integer zeros stay", MustParseDecimal("100"), "100", "100"},
+		{"all zeros", MustParseDecimal("0.000"), "0.000", "0"},
+		{"negative trailing zero", MustParseDecimal("-0.10"), "-0.10", "-0.1"},
+		{"from int", NewDecimalFromInt(-42), "-42", "-42"},
+		{"from float", NewDecimalFromFloat(0.1), "0.1", "0.1"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("%s: String() = %s, want %s", tt.name, got, tt.want)
+		}
+		if got := tt.d.Normalize().String(); got != tt.norm {
+			t.Errorf("%s: Normalize() = %s, want %s", tt.name, got, tt.norm)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustParseDecimal("1.50"), MustParseDecimal("-0.125")
+
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"add", a.Add(b), "1.375"},
+		{"sub", a.Sub(b), "1.625"},
+		{"mul", a.Mul(b), "-0.18750"},
+		{"neg", b.Neg(), "0.125"},
+		{"abs", b.Abs(), "0.125"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if !MustParseDecimal("1.0").Equal(MustParseDecimal("1")) || a.Cmp(b) != 1 || !b.LessThan(a) || !b.IsNegative() || !Zero.IsZero() {
+		t.Error("comparisons disagree with the values")
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type payload struct {
+		Price Decimal `json:"price"`
+	}
+
+	encoded, err := json.Marshal(payload{Price: MustParseDecimal("0.10000000")})
+	if err != nil || string(encoded) != `{"price":"0.10000000"}` {
+		t.Errorf("Marshal() = %s, %v", encoded, err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"price":"0.10000000"}`, "0.10000000"},
+		{`{"price":"-12345678901234567890.5"}`, "-12345678901234567890.5"},
+		{`{"price":0.25}`, "0.25"},
+		{`{"price":1e-3}`, "0.001"},
+		{`{"price":null}`, "0"},
+		{`{"price":""}`, "0"},
+		{`{"price":" 2 "}`, "2"},
+	}
+
+	for _, tt := range tests {
+		var p payload
+		if err := json.Unmarshal([]byte(tt.input), &p); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.input, err)
+			continue
+		}
+		if got := p.Price.String(); got != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+
+		again, _ := json.Marshal(p)
+		var round payload
+		if err := json.Unmarshal(again, &round); err != nil || round.Price.String() != tt.want {
+			t.Errorf("round trip of %s = %s, %v", again, round.Price, err)
+		}
+	}
+
+	for _, input := range []string{`{"price":"abc"}`, `{"price":true}`, `{"price":{}}`, `{"price":"1e99999"}`} {
+		var p payload
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Unmarshal(%s) = %s, want an error", input, p.Price)
+		}
+	}
+}
+
+func TestDecimalText(t *testing.T) {
+	var d Decimal
+	if err := d.UnmarshalText([]byte("  ")); err != nil || !d.IsZero() {
+		t.Errorf("UnmarshalText(blank) = %s, %v", d, err)
+	}
+	if err := d.UnmarshalText([]byte("3.140")); err != nil || d.String() != "3.140" {
+		t.Errorf("UnmarshalText(3.140) = %s, %v", d, err)
+	}
+	if text, _ := d.MarshalText(); string(text) != "3.140" {
+		t.Errorf("MarshalText() = %s", text)
+	}
+	if err := d.UnmarshalText([]byte("x")); err == nil {
+		t.Error("UnmarshalText(x) succeeded")
+	}
+}
@@ -0,0 +1,254 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OrderSide is the direction of an order.
+//
+// Like all enums in this package, OrderSide is lenient when decoding and
+// strict when sending:
+//   - JSON decoding accepts any string. Known values are normalized to
+//     their canonical upper-case form; unrecognized values are kept
+//     verbatim so they round-trip through MarshalJSON, and IsKnown reports
+//     false for them.
+//   - Request encoding (MarshalText, used for query parameters) rejects
+//     unrecognized values, so a typo such as "Buy" fails before the request
+//     leaves the client.
+//   - JSON encoding (MarshalJSON) does not validate. Requests are never
+//     sent as JSON, so JSON only ever carries values that came back from
+//     the server, such as a response cached or persisted by the caller. An
+//     unrecognized status the exchange adds later must survive that round
+//     trip instead of making the whole response unencodable.
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+var orderSides = []OrderSide{OrderSideBuy, OrderSideSell}
+
+// IsKnown reports whether s is one of the defined OrderSide constants.
+func (s OrderSide) IsKnown() bool { return slices.Contains(orderSides, s) }
+
+// Opposite returns the other side. Unknown sides are returned unchanged.
+func (s OrderSide) Opposite() OrderSide {
+	switch s {
+	case OrderSideBuy:
+		return OrderSideSell
+	case OrderSideSell:
+		return OrderSideBuy
+	}
+	return s
+}
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (s OrderSide) MarshalText() ([]byte, error) { return marshalEnum("order side", s, orderSides) }
+
+// MarshalJSON encodes s as a JSON string, including unknown values; see
+// OrderSide for why only MarshalText validates.
+func (s OrderSide) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// UnmarshalJSON decodes a JSON string into s.
+func (s *OrderSide) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("order side", data, s, orderSides)
+}
+
+// OrderType is the execution type of an order.
+type OrderType string
+
+const (
+	OrderTypeLimit           OrderType = "LIMIT"
+	OrderTypeMarket          OrderType = "MARKET"
+	OrderTypeStopLoss        OrderType = "STOP_LOSS"
+	OrderTypeStopLossLimit   OrderType = "STOP_LOSS_LIMIT"
+	OrderTypeTakeProfit      OrderType = "TAKE_PROFIT"
+	OrderTypeTakeProfitLimit OrderType = "TAKE_PROFIT_LIMIT"
+	OrderTypeLimitMaker      OrderType = "LIMIT_MAKER"
+)
+
+var orderTypes = []OrderType{
+	OrderTypeLimit,
+	OrderTypeMarket,
+	OrderTypeStopLoss,
+	OrderTypeStopLossLimit,
+	OrderTypeTakeProfit,
+	OrderTypeTakeProfitLimit,
+	OrderTypeLimitMaker,
+}
+
+// IsKnown reports whether o is one of the defined OrderType constants.
+func (o OrderType) IsKnown() bool { return slices.Contains(orderTypes, o) }
+
+// RequiresPrice reports whether orders of this type need a limit price.
+func (o OrderType) RequiresPrice() bool {
+	switch o {
+	case OrderTypeLimit, OrderTypeStopLossLimit, OrderTypeTakeProfitLimit, OrderTypeLimitMaker:
+		return true
+	}
+	return false
+}
+
+// RequiresStopPrice reports whether orders of this type need a stop price.
+func (o OrderType) RequiresStopPrice() bool {
+	switch o {
+	case OrderTypeStopLoss, OrderTypeStopLossLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit:
+		return true
+	}
+	return false
+}
+
+// RequiresTimeInForce reports whether orders of this type need a
+// time-in-force.
+func (o OrderType) RequiresTimeInForce() bool {
+	switch o {
+	case OrderTypeLimit, OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (o OrderType) MarshalText() ([]byte, error) { return marshalEnum("order type", o, orderTypes) }
+
+// MarshalJSON encodes o as a JSON string, including unknown values.
+func (o OrderType) MarshalJSON() ([]byte, error) { return json.Marshal(string(o)) }
+
+// UnmarshalJSON decodes a JSON string into o.
+func (o *OrderType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("order type", data, o, orderTypes)
+}
+
+// OrderStatus is the lifecycle state of an order.
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPendingNew      OrderStatus = "PENDING_NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusPendingCancel   OrderStatus = "PENDING_CANCEL"
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+	OrderStatusExpiredInMatch  OrderStatus = "EXPIRED_IN_MATCH"
+)
+
+var orderStatuses = []OrderStatus{
+	OrderStatusNew,
+	OrderStatusPendingNew,
+	OrderStatusPartiallyFilled,
+	OrderStatusFilled,
+	OrderStatusPendingCancel,
+	OrderStatusCanceled,
+	OrderStatusRejected,
+	OrderStatusExpired,
+	OrderStatusExpiredInMatch,
+}
+
+// IsKnown reports whether s is one of the defined OrderStatus constants.
+func (s OrderStatus) IsKnown() bool { return slices.Contains(orderStatuses, s) }
+
+// IsOpen reports whether an order in this state may still trade or be
+// cancelled: NEW, PENDING_NEW, PARTIALLY_FILLED or PENDING_CANCEL.
+func (s OrderStatus) IsOpen() bool {
+	switch s {
+	case OrderStatusNew, OrderStatusPendingNew, OrderStatusPartiallyFilled, OrderStatusPendingCancel:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether an order in this state will never change
+// again: FILLED, CANCELED, REJECTED, EXPIRED or EXPIRED_IN_MATCH.
+// Unknown statuses are neither open nor terminal.
+func (s OrderStatus) IsTerminal() bool {
+	switch s {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired, OrderStatusExpiredInMatch:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (s OrderStatus) MarshalText() ([]byte, error) {
+	return marshalEnum("order status", s, orderStatuses)
+}
+
+// MarshalJSON encodes s as a JSON string, including unknown values.
+func (s OrderStatus) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// UnmarshalJSON decodes a JSON string into s.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("order status", data, s, orderStatuses)
+}
+
+// TimeInForce controls how long a limit order stays active.
+type TimeInForce string
+
+const (
+	// TimeInForceGTC keeps the order until it is filled or cancelled.
+	TimeInForceGTC TimeInForce = "GTC"
+
+	// TimeInForceIOC fills what it can immediately and cancels the rest.
+	TimeInForceIOC TimeInForce = "IOC"
+
+	// TimeInForceFOK fills the entire order immediately or cancels it.
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+var timesInForce = []TimeInForce{TimeInForceGTC, TimeInForceIOC, TimeInForceFOK}
+
+// IsKnown reports whether tif is one of the defined TimeInForce constants.
+func (tif TimeInForce) IsKnown() bool { return slices.Contains(timesInForce, tif) }
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (tif TimeInForce) MarshalText() ([]byte, error) {
+	return marshalEnum("time in force", tif, timesInForce)
+}
+
+// MarshalJSON encodes tif as a JSON string, including unknown values.
+func (tif TimeInForce) MarshalJSON() ([]byte, error) { return json.Marshal(string(tif)) }
+
+// UnmarshalJSON decodes a JSON string into tif.
+func (tif *TimeInForce) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("time in force", data, tif, timesInForce)
+}
+
+// marshalEnum renders a request enum value. Empty values encode as empty
+// text so `omitempty` fields are left out; unknown values are an error.
+func marshalEnum[T ~string](kind string, value T, known []T) ([]byte, error) {
+	if value == "" || slices.Contains(known, value) {
+		return []byte(value), nil
+	}
+	return nil, fmt.Errorf("types: invalid %s %q", kind, string(value))
+}
+
+// unmarshalEnum decodes a JSON string into an enum. Known values are
+// matched case-insensitively and normalized; unknown values are kept as
+// received. Non-string JSON values are rejected.
+func unmarshalEnum[T ~string](kind string, data []byte, dst *T, known []T) error {
+	if string(data) == "null" {
+		*dst = ""
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("types: invalid %s %s: expected a string", kind, data)
+	}
+
+	for _, value := range known {
+		if strings.EqualFold(raw, string(value)) {
+			*dst = value
+			return nil
+		}
+	}
+
+	*dst = T(raw)
+	return nil
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEnumUnmarshalJSON(t *testing.T) {
+	var order struct {
+		Side        OrderSide   `json:"side"`
+		Type        OrderType   `json:"type"`
+		Status      OrderStatus `json:"status"`
+		TimeInForce TimeInForce `json:"timeInForce"`
+	}
+
+	data := `{"side":"buy","type":"Stop_Loss_Limit","status":"partially_filled","timeInForce":"ioc"}`
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.Side != OrderSideBuy || order.Type != OrderTypeStopLossLimit ||
+		order.Status != OrderStatusPartiallyFilled || order.TimeInForce != TimeInForceIOC {
+		t.Errorf("decoded %+v, want the canonical constants", order)
+	}
+
+	if err := json.Unmarshal([]byte(`{"side":null}`), &order); err != nil || order.Side != "" {
+		t.Errorf("null side = %q, %v, want empty", order.Side, err)
+	}
+
+	for _, data := range []string{`{"side":1}`, `{"type":true}`, `{"status":["NEW"]}`, `{"timeInForce":{}}`} {
+		if err := json.Unmarshal([]byte(data), &order); err == nil || !strings.Contains(err.Error(), "expected a string") {
+			t.Errorf("Unmarshal(%s) = %v, want a non-string error", data, err)
+		}
+	}
+}
+
+func TestEnumUnknownRoundTrip(t *testing.T) {
+	type response struct {
+		Status OrderStatus `json:"status"`
+		Type   OrderType   `json:"type"`
+	}
+
+	var got response
+	if err := json.Unmarshal([]byte(`{"status":"AWAITING_Review","type":"ICEBERG"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != "AWAITING_Review" || got.Status.IsKnown() || got.Status.IsOpen() || got.Status.IsTerminal() {
+		t.Errorf("status = %q (known %v), want it kept verbatim and neither open nor terminal", got.Status, got.Status.IsKnown())
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"status":"AWAITING_Review","type":"ICEBERG"}` {
+		t.Errorf("Marshal = %s, want the unknown values unchanged", data)
+	}
+}
+
+func TestEnumMarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{ MarshalText() ([]byte, error) }
+		want    string
+		wantErr bool
+	}{
+		{"side", OrderSideSell, "SELL", false},
+		{"type", OrderTypeLimitMaker, "LIMIT_MAKER", false},
+		{"status", OrderStatusExpiredInMatch, "EXPIRED_IN_MATCH", false},
+		{"time in force", TimeInForceFOK, "FOK", false},
+		{"empty", OrderSide(""), "", false},
+		{"lower-case side", OrderSide("buy"), "", true},
+		{"unknown type", OrderType("limit"), "", true},
+		{"unknown status", OrderStatus("DONE"), "", true},
+		{"unknown time in force", TimeInForce("GTD"), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.value.MarshalText()
+		if tt.wantErr {
+			if err == nil || !strings.HasPrefix(err.Error(), "types: invalid ") {
+				t.Errorf("%s: MarshalText() = %q, %v, want an error", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: MarshalText() = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestOrderStatusPredicates(t *testing.T) {
+	open := map[OrderStatus]bool{
+		OrderStatusNew:             true,
+		OrderStatusPendingNew:      true,
+		OrderStatusPartiallyFilled: true,
+		OrderStatusPendingCancel:   true,
+	}
+
+	for _, status := range orderStatuses {
+		if status.IsOpen() != open[status] {
+			t.Errorf("%s.IsOpen() = %v", status, status.IsOpen())
+		}
+		if status.IsTerminal() == open[status] {
+			t.Errorf("%s.IsTerminal() = %v", status, status.IsTerminal())
+		}
+	}
+}
+
+func TestOrderTypeRequirements(t *testing.T) {
+	tests := []struct {
+		typ                           OrderType
+		price, stopPrice, timeInForce bool
+	}{
+		{OrderTypeLimit, true, false, true},
+		{OrderTypeMarket, false, false, false},
+		{OrderTypeStopLoss, false, true, false},
+		{OrderTypeStopLossLimit, true, true, true},
+		{OrderTypeTakeProfit, false, true, false},
+		{OrderTypeTakeProfitLimit, true, true, true},
+		{OrderTypeLimitMaker, true, false, false},
+		{OrderType("ICEBERG"), false, false, false},
+	}
+
+	for _, tt := range tests {
+		if tt.typ.RequiresPrice() != tt.price || tt.typ.RequiresStopPrice() != tt.stopPrice || tt.typ.RequiresTimeInForce() != tt.timeInForce {
+			t.Errorf("%s requires price %v, stop price %v, time in force %v; want %v, %v, %v", tt.typ,
+				tt.typ.RequiresPrice(), tt.typ.RequiresStopPrice(), tt.typ.RequiresTimeInForce(), tt.price, tt.stopPrice, tt.timeInForce)
+		}
+	}
+}
+
+func TestOrderSideOpposite(t *testing.T) {
+	if OrderSideBuy.Opposite() != OrderSideSell || OrderSideSell.Opposite() != OrderSideBuy {
+		t.Error("Opposite() does not swap BUY and SELL")
+	}
+	if side := OrderSide("HOLD"); side.Opposite() != side {
+		t.Errorf("Opposite() of an unknown side = %q", side.Opposite())
+	}
+}
@@ -0,0 +1,162 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Kline intervals served by GET /klines.
+const (
+	KlineInterval1m  = "1m"
+	KlineInterval3m  = "3m"
+	KlineInterval5m  = "5m"
+	KlineInterval15m = "15m"
+	KlineInterval30m = "30m"
+	KlineInterval1h  = "1h"
+	KlineInterval2h  = "2h"
+	KlineInterval4h  = "4h"
+	KlineInterval6h  = "6h"
+	KlineInterval8h  = "8h"
+	KlineInterval12h = "12h"
+	KlineInterval1d  = "1d"
+	KlineInterval3d  = "3d"
+	KlineInterval1w  = "1w"
+	KlineInterval1M  = "1M"
+)
+
+// IsNativeKlineInterval reports whether interval is served by GET /klines.
+// Other intervals, such as "7m", can only be built from trades.
+func IsNativeKlineInterval(interval string) bool {
+	switch interval {
+	case KlineInterval1m, KlineInterval3m, KlineInterval5m, KlineInterval15m, KlineInterval30m,
+		KlineInterval1h, KlineInterval2h, KlineInterval4h, KlineInterval6h, KlineInterval8h, KlineInterval12h,
+		KlineInterval1d, KlineInterval3d, KlineInterval1w, KlineInterval1M:
+		return true
+	}
+	return false
+}
+
+// ParseKlineInterval converts an interval such as "7m", "4h" or "1w" into
+// its length. Supported units are s, m, h, d and w. Calendar months ("1M")
+// have no fixed length and are rejected.
+func ParseKlineInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("types: invalid kline interval %q", interval)
+	}
+
+	n, err := strconv.ParseInt(interval[:len(interval)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("types: invalid kline interval %q", interval)
+	}
+
+	var unit time.Duration
+	switch interval[len(interval)-1] {
+	case 's':
+		unit = time.Second
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("types: unsupported kline interval %q", interval)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+// Kline is a single OHLCV candle.
+//
+// GET /klines returns candles as positional JSON arrays:
+//
+//	[openTime, "open", "high", "low", "close", "volume", closeTime,
+//	 "quoteVolume", tradeCount, "takerBuyBaseVolume", "takerBuyQuoteVolume", "ignore"]
+//
+// Kline decodes that form and encodes back to it. Times are Unix
+// milliseconds; CloseTime is the last millisecond of the candle.
+type Kline struct {
+	OpenTime            int64
+	Open                Decimal
+	High                Decimal
+	Low                 Decimal
+	Close               Decimal
+	Volume              Decimal
+	CloseTime           int64
+	QuoteVolume         Decimal
+	TradeCount          int64
+	TakerBuyBaseVolume  Decimal
+	TakerBuyQuoteVolume Decimal
+}
+
+// MarshalJSON encodes the candle in the positional array form.
+func (k Kline) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{
+		k.OpenTime, k.Open, k.High, k.Low, k.Close, k.Volume, k.CloseTime,
+		k.QuoteVolume, k.TradeCount, k.TakerBuyBaseVolume, k.TakerBuyQuoteVolume, "0",
+	})
+}
+
+// UnmarshalJSON decodes the positional array form. Trailing fields beyond
+// the volume may be missing and decode to zero.
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("types: kline %s is not an array: %w", data, err)
+	}
+	if len(raw) < 6 {
+		return fmt.Errorf("types: kline %s has %d fields, want at least 6", data, len(raw))
+	}
+
+	var kline Kline
+	targets := []interface{}{
+		&kline.OpenTime, &kline.Open, &kline.High, &kline.Low, &kline.Close, &kline.Volume,
+		&kline.CloseTime, &kline.QuoteVolume, &kline.TradeCount, &kline.TakerBuyBaseVolume, &kline.TakerBuyQuoteVolume,
+	}
+
+	for i, target := range targets {
+		if i >= len(raw) {
+			break
+		}
+		if err := json.Unmarshal(raw[i], target); err != nil {
+			return fmt.Errorf("types: invalid kline field %d: %w", i, err)
+		}
+	}
+
+	*k = kline
+	return nil
+}
+
+// Kline converts a streamed candle to a Kline.
+func (k StreamKline) Kline() Kline {
+	return Kline{
+		OpenTime:            k.OpenTime,
+		Open:                k.Open,
+		High:                k.High,
+		Low:                 k.Low,
+		Close:               k.Close,
+		Volume:              k.Volume,
+		CloseTime:           k.CloseTime,
+		QuoteVolume:         k.QuoteVolume,
+		TradeCount:          k.TradeCount,
+		TakerBuyBaseVolume:  k.TakerBuyBaseVolume,
+		TakerBuyQuoteVolume: k.TakerBuyQuoteVolume,
+	}
+}
+
+// GetKlinesParams defines the query parameters of GET /klines.
+//
+// Interval is required and must be one of the KlineInterval constants.
+// StartTime and EndTime are Unix milliseconds; Limit defaults to 500 on
+// the server and is capped at 1000.
+type GetKlinesParams struct {
+	BaseSymbolParams
+	Interval  string `json:"interval"`
+	StartTime int64  `json:"startTime,omitempty"`
+	EndTime   int64  `json:"endTime,omitempty"`
+	Limit     int64  `json:"limit,omitempty"`
+}
@@ -0,0 +1,165 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// KnownQuoteAssets lists the quote assets ParseMarket recognizes when
+// splitting a compact symbol such as "BTCIRT". Longer assets are tried
+// first, so "USDT" wins over a hypothetical "DT".
+var KnownQuoteAssets = []string{"IRT", "TMN", "USDT"}
+
+// Market identifies a trading pair by its base and quote asset.
+//
+// Tabdeal accepts two spellings of the same pair: the compact Symbol
+// ("BTCIRT") and the underscore-separated TabdealSymbol ("BTC_IRT"). Market
+// parses either, renders either, and converts to BaseSymbolParams so it can
+// be used with every parameter struct:
+//
+//	m, _ := types.ParseMarket("BTC_IRT")
+//	params := types.GetOrderBookParams{BaseSymbolParams: m.SymbolParams()}
+//
+// The parameter structs themselves do not hold a Market. BaseSymbolParams
+// stays two plain strings because the symbol registry, validation and
+// quantization all read them directly; a third field naming the same pair
+// would need its own precedence rules in each of those places. Converting
+// at construction with SymbolParams or TabdealSymbolParams keeps a single
+// source of truth.
+//
+// Compact symbols are split on KnownQuoteAssets. For pairs whose split is
+// ambiguous, resolve them from exchangeInfo instead, either with
+// MarketInformation.Market or tabdeal.SymbolRegistry.ResolveMarket.
+type Market struct {
+	Base  string
+	Quote string
+}
+
+// NewMarket returns the market base/quote with upper-cased assets.
+func NewMarket(base, quote string) Market {
+	return Market{Base: strings.ToUpper(base), Quote: strings.ToUpper(quote)}
+}
+
+// ParseMarket parses "BTC_IRT", "BTCIRT", or their lower-case forms.
+//
+// Errors:
+//   - the symbol is empty or has an empty base or quote.
+//   - a compact symbol does not end in any of KnownQuoteAssets.
+func ParseMarket(symbol string) (Market, error) {
+	return ParseMarketWithQuotes(symbol, KnownQuoteAssets)
+}
+
+// ParseMarketWithQuotes is like ParseMarket but splits compact symbols on
+// the given quote assets instead of KnownQuoteAssets.
+func ParseMarketWithQuotes(symbol string, quotes []string) (Market, error) {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+
+	if base, quote, ok := strings.Cut(s, "_"); ok {
+		if base == "" || quote == "" || strings.Contains(quote, "_") {
+			return Market{}, fmt.Errorf("types: invalid market %q", symbol)
+		}
+		return Market{Base: base, Quote: quote}, nil
+	}
+
+	candidates := slices.Clone(quotes)
+	slices.SortFunc(candidates, func(a, b string) int { return len(b) - len(a) })
+
+	for _, quote := range candidates {
+		quote = strings.ToUpper(quote)
+		if quote != "" && len(s) > len(quote) && strings.HasSuffix(s, quote) {
+			return Market{Base: s[:len(s)-len(quote)], Quote: quote}, nil
+		}
+	}
+
+	return Market{}, fmt.Errorf("types: cannot split market %q into base and quote", symbol)
+}
+
+// MustParseMarket is like ParseMarket but panics on error.
+func MustParseMarket(symbol string) Market {
+	m, err := ParseMarket(symbol)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// IsZero reports whether m is the zero Market.
+func (m Market) IsZero() bool {
+	return m.Base == "" && m.Quote == ""
+}
+
+// Symbol returns the compact form, e.g. "BTCIRT".
+func (m Market) Symbol() string {
+	return m.Base + m.Quote
+}
+
+// TabdealSymbol returns the underscore form, e.g. "BTC_IRT".
+func (m Market) TabdealSymbol() string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.Base + "_" + m.Quote
+}
+
+// String returns the compact symbol.
+func (m Market) String() string {
+	return m.Symbol()
+}
+
+// SymbolParams returns BaseSymbolParams addressing m by its compact Symbol,
+// which Tabdeal prefers for trade, order and market-data endpoints.
+func (m Market) SymbolParams() BaseSymbolParams {
+	return BaseSymbolParams{Symbol: m.Symbol()}
+}
+
+// TabdealSymbolParams returns BaseSymbolParams addressing m by its
+// TabdealSymbol.
+func (m Market) TabdealSymbolParams() BaseSymbolParams {
+	return BaseSymbolParams{TabdealSymbol: m.TabdealSymbol()}
+}
+
+// MarshalText renders m in its unambiguous TabdealSymbol form.
+func (m Market) MarshalText() ([]byte, error) {
+	return []byte(m.TabdealSymbol()), nil
+}
+
+// UnmarshalText parses either symbol format. Empty input yields the zero
+// Market.
+func (m *Market) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = Market{}
+		return nil
+	}
+
+	parsed, err := ParseMarket(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Market returns the market addressed by p, preferring Symbol over
+// TabdealSymbol as Tabdeal does.
+func (p BaseSymbolParams) Market() (Market, error) {
+	if p.Symbol != "" {
+		return ParseMarket(p.Symbol)
+	}
+	return ParseMarket(p.TabdealSymbol)
+}
+
+// Market returns the pair described by this exchangeInfo entry. BaseAsset
+// and QuoteAsset are authoritative; TabdealSymbol and Symbol are used when
+// they are missing.
+func (m *MarketInformation) Market() Market {
+	if m.BaseAsset != "" && m.QuoteAsset != "" {
+		return NewMarket(m.BaseAsset, m.QuoteAsset)
+	}
+	if market, err := ParseMarket(m.TabdealSymbol); err == nil {
+		return market
+	}
+	market, _ := ParseMarket(m.Symbol)
+	return market
+}
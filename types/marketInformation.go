@@ -1,5 +1,7 @@
 package types
 
+import "slices"
+
 // Filter represents a ruleset applied by Tabdeal to a given trading pair.
 // Each filter describes a specific constraint related to price, quantity,
 // order size, or other market-level validation.
@@ -25,31 +27,45 @@ package types
 //	  - minNotional
 //	  - applyToMarket
 //
-// Some filters return numeric values as strings. Optional fields use pointers
-// where necessary to distinguish between zero values and absent fields.
+// Tabdeal returns numeric values as strings; they are decoded into Decimal
+// so tick and step sizes keep their exact precision. Fields absent for a
+// given filterType decode to zero.
 type Filter struct {
 	FilterType string `json:"filterType"`
 
 	// PRICE_FILTER
-	MinPrice string `json:"minPrice,omitempty"`
-	MaxPrice string `json:"maxPrice,omitempty"`
-	TickSize string `json:"tickSize,omitempty"`
+	MinPrice Decimal `json:"minPrice,omitempty"`
+	MaxPrice Decimal `json:"maxPrice,omitempty"`
+	TickSize Decimal `json:"tickSize,omitempty"`
 
 	// PERCENT_PRICE
-	MultiplierUp   float64 `json:"multiplierUp,omitempty"`
-	MultiplierDown float64 `json:"multiplierDown,omitempty"`
+	MultiplierUp   Decimal `json:"multiplierUp,omitempty"`
+	MultiplierDown Decimal `json:"multiplierDown,omitempty"`
 	AvgPriceMins   int64   `json:"avgPriceMins,omitempty"`
 
 	// LOT_SIZE and MARKET_LOT_SIZE
-	MinQty   string `json:"minQty,omitempty"`
-	MaxQty   string `json:"maxQty,omitempty"`
-	StepSize string `json:"stepSize,omitempty"`
+	MinQty   Decimal `json:"minQty,omitempty"`
+	MaxQty   Decimal `json:"maxQty,omitempty"`
+	StepSize Decimal `json:"stepSize,omitempty"`
 
 	// MIN_NOTIONAL
-	MinNotional   string `json:"minNotional,omitempty"`
-	ApplyToMarket bool   `json:"applyToMarket,omitempty"`
+	MinNotional   Decimal `json:"minNotional,omitempty"`
+	ApplyToMarket bool    `json:"applyToMarket,omitempty"`
 }
 
+// Filter types published in MarketInformation.Filters.
+const (
+	FilterTypePriceFilter   = "PRICE_FILTER"
+	FilterTypePercentPrice  = "PERCENT_PRICE"
+	FilterTypeLotSize       = "LOT_SIZE"
+	FilterTypeMarketLotSize = "MARKET_LOT_SIZE"
+	FilterTypeMinNotional   = "MIN_NOTIONAL"
+)
+
+// MarketStatusTrading is the MarketInformation.Status of a market that
+// accepts orders.
+const MarketStatusTrading = "TRADING"
+
 // MarketInformation describes a single market available on Tabdeal,
 // including symbol information, trading permissions, supported order types,
 // and the complete set of validation filters associated with the market.
@@ -57,45 +73,70 @@ type Filter struct {
 // This structure maps directly to the result returned by Tabdeal's
 // market-information endpoint.
 type MarketInformation struct {
-	Symbol                     string   `json:"symbol"`
-	TabdealSymbol              string   `json:"tabdealSymbol"`
-	Status                     string   `json:"status"`
-	BaseAsset                  string   `json:"baseAsset"`
-	BaseAssetPrecision         string   `json:"baseAssetPrecision"`
-	QuoteAsset                 string   `json:"quoteAsset"`
-	QuoteAssetPrecision        string   `json:"quoteAssetPrecision"`
-	BaseCommissionPrecision    string   `json:"baseCommissionPrecision"`
-	QuoteCommissionPrecision   string   `json:"quoteCommissionPrecision"`
-	OrderTypes                 []string `json:"orderTypes"`
-	IcebergAllowed             bool     `json:"icebergAllowed"`
-	OcoAllowed                 bool     `json:"ocoAllowed"`
-	QuoteOrderQtyMarketAllowed bool     `json:"quoteOrderQtyMarketAllowed"`
-	AllowTrailingStop          bool     `json:"allowTrailingStop"`
-	IsSpotTradingAllowed       bool     `json:"isSpotTradingAllowed"`
-	IsMarginTradingAllowed     bool     `json:"isMarginTradingAllowed"`
-	Filters                    []Filter `json:"filters"`
-	Permissions                []string `json:"permissions"`
+	Symbol                     string      `json:"symbol"`
+	TabdealSymbol              string      `json:"tabdealSymbol"`
+	Status                     string      `json:"status"`
+	BaseAsset                  string      `json:"baseAsset"`
+	BaseAssetPrecision         string      `json:"baseAssetPrecision"`
+	QuoteAsset                 string      `json:"quoteAsset"`
+	QuoteAssetPrecision        string      `json:"quoteAssetPrecision"`
+	BaseCommissionPrecision    string      `json:"baseCommissionPrecision"`
+	QuoteCommissionPrecision   string      `json:"quoteCommissionPrecision"`
+	OrderTypes                 []OrderType `json:"orderTypes"`
+	IcebergAllowed             bool        `json:"icebergAllowed"`
+	OcoAllowed                 bool        `json:"ocoAllowed"`
+	QuoteOrderQtyMarketAllowed bool        `json:"quoteOrderQtyMarketAllowed"`
+	AllowTrailingStop          bool        `json:"allowTrailingStop"`
+	IsSpotTradingAllowed       bool        `json:"isSpotTradingAllowed"`
+	IsMarginTradingAllowed     bool        `json:"isMarginTradingAllowed"`
+	Filters                    []Filter    `json:"filters"`
+	Permissions                []string    `json:"permissions"`
+}
+
+// Filter returns the filter of the given filterType, such as
+// FilterTypeLotSize, and whether the market defines it.
+func (m *MarketInformation) Filter(filterType string) (Filter, bool) {
+	for _, filter := range m.Filters {
+		if filter.FilterType == filterType {
+			return filter, true
+		}
+	}
+	return Filter{}, false
+}
+
+// SupportsOrderType reports whether the market accepts orderType. Markets
+// that publish no order types accept every type.
+func (m *MarketInformation) SupportsOrderType(orderType OrderType) bool {
+	return len(m.OrderTypes) == 0 || slices.Contains(m.OrderTypes, orderType)
 }
 
 // OrderBook represents the current aggregated order book for a market.
-// Each entry in Asks and Bids is a [price, quantity] pair formatted as strings.
+// Each entry in Asks and Bids is a PriceLevel decoded from Tabdeal's
+// [price, quantity] pair.
 //
 // The best ask appears at Asks[0], and the best bid appears at Bids[0].
+// BestBid, BestAsk and the other methods in orderbook.go read the book
+// without indexing it directly.
+//
+// LastUpdateId is the book sequence number the snapshot reflects. Depth
+// stream updates with a FinalUpdateId at or below it are already included.
 type OrderBook struct {
-	Asks [][]string `json:"asks"`
-	Bids [][]string `json:"bids"`
+	LastUpdateId int64        `json:"lastUpdateId"`
+	Asks         []PriceLevel `json:"asks"`
+	Bids         []PriceLevel `json:"bids"`
 }
 
 // Trade describes a single executed trade on Tabdeal's spot market.
-// Tabdeal returns price, quantity, and quote quantity as strings,
-// along with timestamp and taker/maker information.
+// Tabdeal returns price, quantity, and quote quantity as strings, which
+// are decoded into Decimal, along with timestamp and taker/maker
+// information.
 type Trade struct {
-	Id           int64  `json:"id"`
-	Price        string `json:"price"`
-	Qty          string `json:"qty"`
-	QuoteQty     string `json:"quoteQty"`
-	Time         int64  `json:"time"`
-	IsBuyerMaker bool   `json:"isBuyerMaker"`
+	Id           int64   `json:"id"`
+	Price        Decimal `json:"price"`
+	Qty          Decimal `json:"qty"`
+	QuoteQty     Decimal `json:"quoteQty"`
+	Time         int64   `json:"time"`
+	IsBuyerMaker bool    `json:"isBuyerMaker"`
 }
 
 // ServerTime represents the server's current timestamp in milliseconds,
@@ -112,6 +153,53 @@ type GetRecentTradesParams struct {
 	Limit int64 `json:"limit,omitempty"`
 }
 
+// GetHistoricalTradesParams defines the query parameters of
+// GET /historicalTrades. FromId is the first trade id to return; when it
+// is zero the most recent trades are returned. Limit defaults to 500 on
+// the server and is capped at 1000.
+type GetHistoricalTradesParams struct {
+	BaseSymbolParams
+	Limit  int64 `json:"limit,omitempty"`
+	FromId int64 `json:"fromId,omitempty"`
+}
+
+// AggTrade is a compressed trade: consecutive fills of one taker order at
+// the same price, as returned by GET /aggTrades. FirstTradeId and
+// LastTradeId delimit the individual trades it covers.
+type AggTrade struct {
+	Id           int64   `json:"a"`
+	Price        Decimal `json:"p"`
+	Qty          Decimal `json:"q"`
+	FirstTradeId int64   `json:"f"`
+	LastTradeId  int64   `json:"l"`
+	Time         int64   `json:"T"`
+	IsBuyerMaker bool    `json:"m"`
+	IsBestMatch  bool    `json:"M"`
+}
+
+// GetAggTradesParams defines the query parameters of GET /aggTrades.
+//
+// Select trades either by FromId (first aggregate id to return) or by a
+// StartTime–EndTime range in Unix milliseconds of at most one hour; with
+// neither, the most recent aggregates are returned. Limit defaults to 500
+// on the server and is capped at 1000.
+type GetAggTradesParams struct {
+	BaseSymbolParams
+	FromId    int64 `json:"fromId,omitempty"`
+	StartTime int64 `json:"startTime,omitempty"`
+	EndTime   int64 `json:"endTime,omitempty"`
+	Limit     int64 `json:"limit,omitempty"`
+}
+
+// BackfillTradesParams selects the market and Unix millisecond range of
+// Client.BackfillTrades. StartTime is required; EndTime defaults to now.
+// Both bounds are inclusive.
+type BackfillTradesParams struct {
+	BaseSymbolParams
+	StartTime int64
+	EndTime   int64
+}
+
 // GetOrderBookParams defines the query parameters used to fetch
 // the current order book for a specific market. The limit parameter controls
 // how many price levels are included in the response.
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMarket(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Market
+	}{
+		{"BTC_IRT", Market{"BTC", "IRT"}},
+		{"btc_irt", Market{"BTC", "IRT"}},
+		{"BTCIRT", Market{"BTC", "IRT"}},
+		{" ethusdt ", Market{"ETH", "USDT"}},
+		{"USDTTMN", Market{"USDT", "TMN"}},
+		{"USDTIRT", Market{"USDT", "IRT"}},
+		{"1INCH_USDT", Market{"1INCH", "USDT"}},
+		{"DOGE_BTC", Market{"DOGE", "BTC"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMarket(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMarket(%q) = %+v, %v, want %+v", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseMarketErrors(t *testing.T) {
+	for _, input := range []string{"", "  ", "_IRT", "BTC_", "BTC_IRT_X", "IRT", "DOGEBTC"} {
+		if got, err := ParseMarket(input); err == nil {
+			t.Errorf("ParseMarket(%q) = %+v, want an error", input, got)
+		}
+	}
+}
+
+func TestParseMarketWithQuotes(t *testing.T) {
+	tests := []struct {
+		input  string
+		quotes []string
+		want   Market
+	}{
+		// The longest matching quote wins regardless of list order.
+		{"BTCUSDT", []string{"DT", "usdt"}, Market{"BTC", "USDT"}},
+		{"DOGEBTC", []string{"BTC", "IRT"}, Market{"DOGE", "BTC"}},
+		{"ETHBTC", []string{"", "BTC"}, Market{"ETH", "BTC"}},
+		// The underscore form never consults the quote list.
+		{"ETH_DAI", nil, Market{"ETH", "DAI"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMarketWithQuotes(tt.input, tt.quotes)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMarketWithQuotes(%q, %q) = %+v, %v, want %+v", tt.input, tt.quotes, got, err, tt.want)
+		}
+	}
+
+	if _, err := ParseMarketWithQuotes("BTCIRT", []string{"USDT"}); err == nil {
+		t.Error("ParseMarketWithQuotes split on a quote asset that is not listed")
+	}
+
+	quotes := []string{"IRT", "USDT"}
+	if _, err := ParseMarketWithQuotes("BTCUSDT", quotes); err != nil || quotes[0] != "IRT" {
+		t.Errorf("ParseMarketWithQuotes reordered its input: %q, %v", quotes, err)
+	}
+}
+
+func TestMarketFormats(t *testing.T) {
+	m := NewMarket("btc", "irt")
+	if m.Symbol() != "BTCIRT" || m.TabdealSymbol() != "BTC_IRT" || m.String() != "BTCIRT" {
+		t.Errorf("formats = %s, %s, %s", m.Symbol(), m.TabdealSymbol(), m)
+	}
+	if p := m.SymbolParams(); p != (BaseSymbolParams{Symbol: "BTCIRT"}) {
+		t.Errorf("SymbolParams() = %+v", p)
+	}
+	if p := m.TabdealSymbolParams(); p != (BaseSymbolParams{TabdealSymbol: "BTC_IRT"}) {
+		t.Errorf("TabdealSymbolParams() = %+v", p)
+	}
+
+	var zero Market
+	if !zero.IsZero() || zero.TabdealSymbol() != "" || zero.Symbol() != "" {
+		t.Errorf("zero Market renders as %q and %q", zero.Symbol(), zero.TabdealSymbol())
+	}
+}
+
+func TestBaseSymbolParamsMarket(t *testing.T) {
+	tests := []struct {
+		params BaseSymbolParams
+		want   Market
+	}{
+		{BaseSymbolParams{Symbol: "ETHUSDT"}, Market{"ETH", "USDT"}},
+		{BaseSymbolParams{TabdealSymbol: "ETH_USDT"}, Market{"ETH", "USDT"}},
+		// Symbol takes precedence, as it does on the server.
+		{BaseSymbolParams{Symbol: "BTCIRT", TabdealSymbol: "ETH_USDT"}, Market{"BTC", "IRT"}},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.params.Market()
+		if err != nil || got != tt.want {
+			t.Errorf("%+v.Market() = %+v, %v, want %+v", tt.params, got, err, tt.want)
+		}
+	}
+
+	if _, err := (BaseSymbolParams{}).Market(); err == nil {
+		t.Error("empty BaseSymbolParams produced a market")
+	}
+}
+
+func TestMarketInformationMarket(t *testing.T) {
+	tests := []struct {
+		info MarketInformation
+		want Market
+	}{
+		// exchangeInfo's assets settle a split ParseMarket would get wrong.
+		{MarketInformation{Symbol: "USDTIRT", BaseAsset: "US", QuoteAsset: "DTIRT"}, Market{"US", "DTIRT"}},
+		{MarketInformation{Symbol: "BTCIRT", TabdealSymbol: "BTC_IRT"}, Market{"BTC", "IRT"}},
+		{MarketInformation{Symbol: "ETHUSDT"}, Market{"ETH", "USDT"}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.Market(); got != tt.want {
+			t.Errorf("Market() of %s = %+v, want %+v", tt.info.Symbol, got, tt.want)
+		}
+	}
+}
+
+func TestMarketText(t *testing.T) {
+	var v struct {
+		Market Market `json:"market"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"market":"ethusdt"}`), &v); err != nil || v.Market != (Market{"ETH", "USDT"}) {
+		t.Fatalf("Unmarshal = %+v, %v", v.Market, err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `{"market":"ETH_USDT"}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"market":""}`), &v); err != nil || !v.Market.IsZero() {
+		t.Errorf("empty market = %+v, %v", v.Market, err)
+	}
+	if err := json.Unmarshal([]byte(`{"market":"DOGEBTC"}`), &v); err == nil {
+		t.Error("Unmarshal accepted a market it cannot split")
+	}
+}
@@ -0,0 +1,241 @@
+package types
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// ContingencyType is the kind of link between the orders of a list.
+type ContingencyType string
+
+// ContingencyTypeOCO links one-cancels-the-other lists: when one order
+// executes, the other is cancelled.
+const ContingencyTypeOCO ContingencyType = "OCO"
+
+var contingencyTypes = []ContingencyType{ContingencyTypeOCO}
+
+// IsKnown reports whether c is one of the defined ContingencyType
+// constants.
+func (c ContingencyType) IsKnown() bool { return slices.Contains(contingencyTypes, c) }
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (c ContingencyType) MarshalText() ([]byte, error) {
+	return marshalEnum("contingency type", c, contingencyTypes)
+}
+
+// MarshalJSON encodes c as a JSON string, including unknown values.
+func (c ContingencyType) MarshalJSON() ([]byte, error) { return json.Marshal(string(c)) }
+
+// UnmarshalJSON decodes a JSON string into c.
+func (c *ContingencyType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("contingency type", data, c, contingencyTypes)
+}
+
+// ListStatusType is the state of an order list as a whole.
+type ListStatusType string
+
+const (
+	// ListStatusTypeResponse is used when the list status is only a
+	// response to a failed action, such as a rejected placement.
+	ListStatusTypeResponse ListStatusType = "RESPONSE"
+
+	// ListStatusTypeExecStarted means the list was placed and may still
+	// change.
+	ListStatusTypeExecStarted ListStatusType = "EXEC_STARTED"
+
+	// ListStatusTypeAllDone means the list has finished: one leg executed
+	// and the other was cancelled, or both were cancelled.
+	ListStatusTypeAllDone ListStatusType = "ALL_DONE"
+)
+
+var listStatusTypes = []ListStatusType{ListStatusTypeResponse, ListStatusTypeExecStarted, ListStatusTypeAllDone}
+
+// IsKnown reports whether s is one of the defined ListStatusType constants.
+func (s ListStatusType) IsKnown() bool { return slices.Contains(listStatusTypes, s) }
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (s ListStatusType) MarshalText() ([]byte, error) {
+	return marshalEnum("list status type", s, listStatusTypes)
+}
+
+// MarshalJSON encodes s as a JSON string, including unknown values.
+func (s ListStatusType) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// UnmarshalJSON decodes a JSON string into s.
+func (s *ListStatusType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("list status type", data, s, listStatusTypes)
+}
+
+// ListOrderStatus is the execution state of the orders in a list.
+type ListOrderStatus string
+
+const (
+	// ListOrderStatusExecuting means the list was placed or one of its
+	// orders changed state.
+	ListOrderStatusExecuting ListOrderStatus = "EXECUTING"
+
+	// ListOrderStatusAllDone means the list has finished.
+	ListOrderStatusAllDone ListOrderStatus = "ALL_DONE"
+
+	// ListOrderStatusReject means the list was rejected on placement or
+	// cancellation.
+	ListOrderStatusReject ListOrderStatus = "REJECT"
+)
+
+var listOrderStatuses = []ListOrderStatus{ListOrderStatusExecuting, ListOrderStatusAllDone, ListOrderStatusReject}
+
+// IsKnown reports whether s is one of the defined ListOrderStatus constants.
+func (s ListOrderStatus) IsKnown() bool { return slices.Contains(listOrderStatuses, s) }
+
+// MarshalText implements encoding.TextMarshaler and rejects unknown values.
+func (s ListOrderStatus) MarshalText() ([]byte, error) {
+	return marshalEnum("list order status", s, listOrderStatuses)
+}
+
+// MarshalJSON encodes s as a JSON string, including unknown values.
+func (s ListOrderStatus) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// UnmarshalJSON decodes a JSON string into s.
+func (s *ListOrderStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("list order status", data, s, listOrderStatuses)
+}
+
+// OrderListOrder identifies one order of an order list. Its OrderId
+// matches the OrderId of the order's report and of GetOrderStatus.
+type OrderListOrder struct {
+	Symbol        string `json:"symbol"`
+	OrderId       int64  `json:"orderId"`
+	ClientOrderId string `json:"clientOrderId"`
+}
+
+// OrderList is a group of linked orders, such as the two legs of an OCO,
+// as returned by GET /orderList and GET /openOrderList. Every child order
+// carries the list's OrderListId in BaseOrderResponse.OrderListId.
+type OrderList struct {
+	OrderListId       int64            `json:"orderListId"`
+	ContingencyType   ContingencyType  `json:"contingencyType"`
+	ListStatusType    ListStatusType   `json:"listStatusType"`
+	ListOrderStatus   ListOrderStatus  `json:"listOrderStatus"`
+	ListClientOrderId string           `json:"listClientOrderId"`
+	TransactionTime   int64            `json:"transactionTime"`
+	Symbol            string           `json:"symbol"`
+	Orders            []OrderListOrder `json:"orders"`
+}
+
+// IsDone reports whether the list has finished and none of its orders
+// can trade any more.
+func (l *OrderList) IsDone() bool {
+	return l.ListStatusType == ListStatusTypeAllDone || l.ListOrderStatus == ListOrderStatusAllDone
+}
+
+// OrderListResponse is an order list together with the full report of
+// each child order, as returned when placing or cancelling a list.
+// OrderReports are in the same order as Orders.
+type OrderListResponse struct {
+	OrderList
+	OrderReports []BaseOrderResponse `json:"orderReports"`
+}
+
+// Report returns the report of the child order with the given id.
+func (l *OrderListResponse) Report(orderId int64) (BaseOrderResponse, bool) {
+	for _, report := range l.OrderReports {
+		if report.OrderId == orderId {
+			return report, true
+		}
+	}
+	return BaseOrderResponse{}, false
+}
+
+// LimitLeg returns the report of the limit (take-profit) leg of an OCO:
+// the order of type LIMIT_MAKER.
+func (l *OrderListResponse) LimitLeg() (BaseOrderResponse, bool) {
+	return l.leg(func(o OrderType) bool { return o == OrderTypeLimitMaker })
+}
+
+// StopLeg returns the report of the stop-loss leg of an OCO: the order of
+// type STOP_LOSS or STOP_LOSS_LIMIT.
+func (l *OrderListResponse) StopLeg() (BaseOrderResponse, bool) {
+	return l.leg(func(o OrderType) bool { return o == OrderTypeStopLoss || o == OrderTypeStopLossLimit })
+}
+
+func (l *OrderListResponse) leg(match func(OrderType) bool) (BaseOrderResponse, bool) {
+	for _, report := range l.OrderReports {
+		if match(report.Type) {
+			return report, true
+		}
+	}
+	return BaseOrderResponse{}, false
+}
+
+// CreateOCOOrderParams defines the parameters of POST /order/oco, which
+// places a limit order and a stop-loss order for the same quantity: when
+// one of them executes, the other is cancelled.
+//
+// For a SELL, Price (take profit) must be above the current price and
+// StopPrice below it; for a BUY, the other way round.
+//
+// The stop leg is a STOP_LOSS_LIMIT order when StopLimitPrice is set, in
+// which case StopLimitTimeInForce is required, and a STOP_LOSS (market)
+// order otherwise. The *ClientOrderId fields optionally name the list
+// and each leg.
+type CreateOCOOrderParams struct {
+	BaseSymbolParams
+
+	ListClientOrderId    string      `json:"listClientOrderId,omitempty"`
+	Side                 OrderSide   `json:"side"`
+	Quantity             Decimal     `json:"quantity"`
+	LimitClientOrderId   string      `json:"limitClientOrderId,omitempty"`
+	Price                Decimal     `json:"price"`
+	StopClientOrderId    string      `json:"stopClientOrderId,omitempty"`
+	StopPrice            Decimal     `json:"stopPrice"`
+	StopLimitPrice       Decimal     `json:"stopLimitPrice,omitempty"`
+	StopLimitTimeInForce TimeInForce `json:"stopLimitTimeInForce,omitempty"`
+}
+
+// Legs returns the two orders of the OCO as CreateOrderParams, for
+// validation and quantization with the single-order helpers: a
+// LIMIT_MAKER order at Price and a STOP_LOSS or STOP_LOSS_LIMIT order at
+// StopPrice.
+func (p CreateOCOOrderParams) Legs() (limit, stop CreateOrderParams) {
+	limit = CreateOrderParams{
+		BaseSymbolParams: p.BaseSymbolParams,
+		Side:             p.Side,
+		Type:             OrderTypeLimitMaker,
+		Quantity:         p.Quantity,
+		NewClientOrderId: p.LimitClientOrderId,
+		Price:            p.Price,
+	}
+
+	stop = CreateOrderParams{
+		BaseSymbolParams: p.BaseSymbolParams,
+		Side:             p.Side,
+		Type:             OrderTypeStopLoss,
+		Quantity:         p.Quantity,
+		NewClientOrderId: p.StopClientOrderId,
+		StopPrice:        p.StopPrice,
+	}
+	if p.StopLimitPrice.IsPositive() {
+		stop.Type = OrderTypeStopLossLimit
+		stop.Price = p.StopLimitPrice
+		stop.TimeInForce = p.StopLimitTimeInForce
+	}
+
+	return limit, stop
+}
+
+// CancelOrderListParams identifies the order list to cancel, by
+// OrderListId or ListClientOrderId. NewClientOrderId optionally names the
+// cancellation.
+type CancelOrderListParams struct {
+	BaseSymbolParams
+	OrderListId       int64  `json:"orderListId,omitempty"`
+	ListClientOrderId string `json:"listClientOrderId,omitempty"`
+	NewClientOrderId  string `json:"newClientOrderId,omitempty"`
+}
+
+// GetOrderListParams identifies the order list to query, by OrderListId
+// or by the listClientOrderId given at placement (OrigClientOrderId).
+type GetOrderListParams struct {
+	OrderListId       int64  `json:"orderListId,omitempty"`
+	OrigClientOrderId string `json:"origClientOrderId,omitempty"`
+}
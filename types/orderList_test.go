@@ -0,0 +1,117 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func ocoParams() CreateOCOOrderParams {
+	return CreateOCOOrderParams{
+		BaseSymbolParams:   BaseSymbolParams{Symbol: "BTCIRT"},
+		ListClientOrderId:  "bracket",
+		Side:               OrderSideSell,
+		Quantity:           MustParseDecimal("0.5"),
+		LimitClientOrderId: "take",
+		Price:              MustParseDecimal("110"),
+		StopClientOrderId:  "stop",
+		StopPrice:          MustParseDecimal("90"),
+	}
+}
+
+func TestCreateOCOOrderParamsLegs(t *testing.T) {
+	params := ocoParams()
+
+	limit, stop := params.Legs()
+	if limit.Type != OrderTypeLimitMaker || limit.Side != OrderSideSell || limit.Symbol != "BTCIRT" ||
+		limit.Price.String() != "110" || limit.Quantity.String() != "0.5" || limit.NewClientOrderId != "take" ||
+		!limit.StopPrice.IsZero() || limit.TimeInForce != "" {
+		t.Errorf("limit leg = %+v", limit)
+	}
+
+	// Without StopLimitPrice the stop leg executes at market.
+	if stop.Type != OrderTypeStopLoss || stop.Side != OrderSideSell || stop.StopPrice.String() != "90" ||
+		!stop.Price.IsZero() || stop.TimeInForce != "" || stop.Quantity.String() != "0.5" || stop.NewClientOrderId != "stop" {
+		t.Errorf("stop leg = %+v", stop)
+	}
+
+	params.StopLimitPrice = MustParseDecimal("89")
+	params.StopLimitTimeInForce = TimeInForceGTC
+	limit, stop = params.Legs()
+	if stop.Type != OrderTypeStopLossLimit || stop.StopPrice.String() != "90" || stop.Price.String() != "89" ||
+		stop.TimeInForce != TimeInForceGTC || stop.NewClientOrderId != "stop" {
+		t.Errorf("stop-limit leg = %+v", stop)
+	}
+	if limit.Type != OrderTypeLimitMaker || limit.Price.String() != "110" {
+		t.Errorf("limit leg with a stop-limit = %+v", limit)
+	}
+
+	// A zero StopLimitPrice is the same as none.
+	params.StopLimitPrice = Decimal{}
+	if _, stop = params.Legs(); stop.Type != OrderTypeStopLoss || stop.TimeInForce != "" {
+		t.Errorf("stop leg with a zero StopLimitPrice = %+v", stop)
+	}
+}
+
+func TestOrderListResponse(t *testing.T) {
+	data := `{
+		"orderListId": 4,
+		"contingencyType": "oco",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "bracket",
+		"symbol": "BTCIRT",
+		"orders": [{"symbol": "BTCIRT", "orderId": 11, "clientOrderId": "stop"}, {"symbol": "BTCIRT", "orderId": 12, "clientOrderId": "take"}],
+		"orderReports": [
+			{"symbol": "BTCIRT", "orderId": 11, "orderListId": 4, "type": "STOP_LOSS_LIMIT", "status": "NEW"},
+			{"symbol": "BTCIRT", "orderId": 12, "orderListId": 4, "type": "LIMIT_MAKER", "status": "NEW"}
+		]
+	}`
+
+	var list OrderListResponse
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.ContingencyType != ContingencyTypeOCO || list.IsDone() || len(list.Orders) != 2 {
+		t.Errorf("list = %+v", list.OrderList)
+	}
+
+	if leg, ok := list.LimitLeg(); !ok || leg.OrderId != 12 {
+		t.Errorf("LimitLeg() = %d, %v", leg.OrderId, ok)
+	}
+	if leg, ok := list.StopLeg(); !ok || leg.OrderId != 11 {
+		t.Errorf("StopLeg() = %d, %v", leg.OrderId, ok)
+	}
+	if report, ok := list.Report(12); !ok || report.Type != OrderTypeLimitMaker {
+		t.Errorf("Report(12) = %+v, %v", report, ok)
+	}
+	if _, ok := list.Report(13); ok {
+		t.Error("Report(13) found an order that is not in the list")
+	}
+
+	list.ListStatusType = ListStatusTypeAllDone
+	if !list.IsDone() {
+		t.Error("IsDone() = false for an ALL_DONE list")
+	}
+}
+
+func TestContingencyType(t *testing.T) {
+	var list OrderList
+	if err := json.Unmarshal([]byte(`{"contingencyType":"OTO"}`), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.ContingencyType != "OTO" || list.ContingencyType.IsKnown() {
+		t.Errorf("ContingencyType = %q, want the unknown value kept", list.ContingencyType)
+	}
+
+	data, err := json.Marshal(list.ContingencyType)
+	if err != nil || string(data) != `"OTO"` {
+		t.Errorf("MarshalJSON = %s, %v", data, err)
+	}
+
+	if _, err := list.ContingencyType.MarshalText(); err == nil {
+		t.Error("MarshalText accepted an unknown contingency type")
+	}
+	if text, err := ContingencyTypeOCO.MarshalText(); err != nil || string(text) != "OCO" {
+		t.Errorf("MarshalText(OCO) = %s, %v", text, err)
+	}
+}
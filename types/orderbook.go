@@ -0,0 +1,189 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PriceLevel is a single [price, quantity] entry of an order book.
+//
+// Tabdeal encodes levels as two-element JSON arrays of strings, e.g.
+// ["3350000000", "0.125"]. PriceLevel decodes that form (numbers are
+// accepted as well) and encodes back to it.
+type PriceLevel struct {
+	Price    Decimal
+	Quantity Decimal
+}
+
+// Notional returns Price × Quantity.
+func (l PriceLevel) Notional() Decimal {
+	return l.Price.Mul(l.Quantity)
+}
+
+// MarshalJSON encodes the level as ["price", "quantity"].
+func (l PriceLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]Decimal{l.Price, l.Quantity})
+}
+
+// UnmarshalJSON decodes a [price, quantity] array.
+//
+// Errors:
+//   - the value is not an array of at least two elements.
+//   - price or quantity is not a valid decimal.
+func (l *PriceLevel) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("types: order book level %s is not an array: %w", data, err)
+	}
+	if len(raw) < 2 {
+		return fmt.Errorf("types: order book level %s needs price and quantity", data)
+	}
+
+	var level PriceLevel
+	if err := level.Price.UnmarshalJSON(raw[0]); err != nil {
+		return fmt.Errorf("types: invalid order book price: %w", err)
+	}
+	if err := level.Quantity.UnmarshalJSON(raw[1]); err != nil {
+		return fmt.Errorf("types: invalid order book quantity: %w", err)
+	}
+
+	*l = level
+	return nil
+}
+
+// The methods below assume Tabdeal's ordering: Bids from the highest price
+// down and Asks from the lowest price up.
+
+// BestBid returns the highest bid, or false when there are no bids.
+func (b *OrderBook) BestBid() (PriceLevel, bool) {
+	if len(b.Bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return b.Bids[0], true
+}
+
+// BestAsk returns the lowest ask, or false when there are no asks.
+func (b *OrderBook) BestAsk() (PriceLevel, bool) {
+	if len(b.Asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return b.Asks[0], true
+}
+
+// Spread returns best ask minus best bid, or false when either side is
+// empty.
+func (b *OrderBook) Spread() (Decimal, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return Decimal{}, false
+	}
+	return ask.Price.Sub(bid.Price), true
+}
+
+// MidPrice returns the average of the best bid and best ask, or false when
+// either side is empty.
+func (b *OrderBook) MidPrice() (Decimal, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return Decimal{}, false
+	}
+
+	sum := bid.Price.Add(ask.Price)
+	return sum.DivRound(NewDecimalFromInt(2), sum.Scale()+1, RoundNearest).Normalize(), true
+}
+
+// takerLevels returns the side of the book a taker order on side consumes:
+// asks for buys, bids for sells.
+func (b *OrderBook) takerLevels(side OrderSide) []PriceLevel {
+	if side == OrderSideSell {
+		return b.Bids
+	}
+	return b.Asks
+}
+
+// walk consumes quantity from the taker side and returns the notional
+// spent, the price of the last level touched, and whether the book was
+// deep enough.
+func (b *OrderBook) walk(side OrderSide, quantity Decimal) (notional, last Decimal, ok bool) {
+	remaining := quantity
+	for _, level := range b.takerLevels(side) {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		fill := level.Quantity
+		if fill.GreaterThan(remaining) {
+			fill = remaining
+		}
+
+		notional = notional.Add(level.Price.Mul(fill))
+		remaining = remaining.Sub(fill)
+		last = level.Price
+	}
+
+	return notional, last, quantity.IsPositive() && !remaining.IsPositive()
+}
+
+// ImpactPrice returns the worst price a taker order of quantity on side
+// would reach: the last ask level consumed by a buy, or the last bid level
+// consumed by a sell. It reports false when the book is too thin or
+// quantity is not positive.
+//
+// Example:
+//
+//	price, ok := book.ImpactPrice(types.OrderSideBuy, types.MustParseDecimal("0.5"))
+func (b *OrderBook) ImpactPrice(side OrderSide, quantity Decimal) (Decimal, bool) {
+	_, last, ok := b.walk(side, quantity)
+	if !ok {
+		return Decimal{}, false
+	}
+	return last, true
+}
+
+// VWAPForQuantity returns the volume-weighted average price a taker order
+// of quantity on side would pay (buy) or receive (sell). It reports false
+// when the book is too thin or quantity is not positive.
+func (b *OrderBook) VWAPForQuantity(side OrderSide, quantity Decimal) (Decimal, bool) {
+	notional, last, ok := b.walk(side, quantity)
+	if !ok {
+		return Decimal{}, false
+	}
+	return notional.DivRound(quantity, last.Scale()+8, RoundNearest).Normalize(), true
+}
+
+// DepthWithin returns the total bid and ask quantity resting within bps
+// basis points of the mid price (1 bps = 0.01%). Both are zero when either
+// side of the book is empty.
+//
+// Example:
+//
+//	// Liquidity within ±0.5% of mid.
+//	bids, asks := book.DepthWithin(50)
+func (b *OrderBook) DepthWithin(bps int64) (bids, asks Decimal) {
+	mid, ok := b.MidPrice()
+	if !ok {
+		return Decimal{}, Decimal{}
+	}
+
+	band := mid.Mul(NewDecimal(bps, 4))
+	low := mid.Sub(band)
+	high := mid.Add(band)
+
+	for _, level := range b.Bids {
+		if level.Price.LessThan(low) {
+			break
+		}
+		bids = bids.Add(level.Quantity)
+	}
+
+	for _, level := range b.Asks {
+		if level.Price.GreaterThan(high) {
+			break
+		}
+		asks = asks.Add(level.Quantity)
+	}
+
+	return bids, asks
+}
@@ -0,0 +1,115 @@
+package types
+
+import "strconv"
+
+// SideRounding returns the default price rounding for an order side:
+// RoundCeil for buys and RoundFloor for sells. Both move the price toward
+// the opposite side of the book, so quantization never makes an order
+// less likely to execute. Unknown sides round to nearest.
+func SideRounding(side OrderSide) RoundingMode {
+	switch side {
+	case OrderSideBuy:
+		return RoundCeil
+	case OrderSideSell:
+		return RoundFloor
+	}
+	return RoundNearest
+}
+
+// precision parses a precision field such as BaseAssetPrecision ("8").
+// It returns -1 when the field is empty or malformed.
+func precision(value string) int32 {
+	p, err := strconv.Atoi(value)
+	if err != nil || p < 0 {
+		return -1
+	}
+	return int32(p)
+}
+
+// PriceDecimals returns the number of fractional digits a legal price has:
+// the decimals of the normalized tickSize (or of minPrice, when it has
+// more) when PRICE_FILTER defines a tick, otherwise QuoteAssetPrecision.
+// It returns -1 when neither is known.
+func (m *MarketInformation) PriceDecimals() int32 {
+	if step, low := m.priceGrid(); step.IsPositive() {
+		return max(step.Normalize().Scale(), low.Normalize().Scale())
+	}
+	return precision(m.QuoteAssetPrecision)
+}
+
+// QuantityDecimals returns the number of fractional digits a legal
+// quantity has for the given order type: the decimals of the normalized
+// stepSize (or of minQty, when it has more) of MARKET_LOT_SIZE for MARKET
+// orders when defined, LOT_SIZE otherwise, falling back to
+// BaseAssetPrecision. It returns -1 when neither is known.
+func (m *MarketInformation) QuantityDecimals(orderType OrderType) int32 {
+	if step, low := m.quantityGrid(orderType); step.IsPositive() {
+		return max(step.Normalize().Scale(), low.Normalize().Scale())
+	}
+	return precision(m.BaseAssetPrecision)
+}
+
+// priceGrid returns the tickSize and minPrice of PRICE_FILTER.
+func (m *MarketInformation) priceGrid() (step, low Decimal) {
+	if filter, ok := m.Filter(FilterTypePriceFilter); ok {
+		return filter.TickSize, filter.MinPrice
+	}
+	return Decimal{}, Decimal{}
+}
+
+// quantityGrid returns the stepSize and minQty that apply to orderType.
+func (m *MarketInformation) quantityGrid(orderType OrderType) (step, low Decimal) {
+	if orderType == OrderTypeMarket {
+		if filter, ok := m.Filter(FilterTypeMarketLotSize); ok && filter.StepSize.IsPositive() {
+			return filter.StepSize, filter.MinQty
+		}
+	}
+	if filter, ok := m.Filter(FilterTypeLotSize); ok {
+		return filter.StepSize, filter.MinQty
+	}
+	return Decimal{}, Decimal{}
+}
+
+// roundToGrid rounds value to low plus an integer multiple of step, the
+// grid the exchange checks steps against. A non-positive low is the grid
+// from zero.
+func roundToGrid(value, step, low Decimal, mode RoundingMode) Decimal {
+	if !low.IsPositive() {
+		return value.RoundToStep(step, mode)
+	}
+	return value.Sub(low).RoundToStep(step, mode).Add(low.Normalize())
+}
+
+// QuantizePrice rounds price to a legal value for this market using mode:
+// to minPrice plus a multiple of tickSize when PRICE_FILTER defines a
+// tick, otherwise to QuoteAssetPrecision decimals. The result is
+// formatted with exactly PriceDecimals() fractional digits.
+//
+// Example:
+//
+//	// tickSize "0.01000000"
+//	m.QuantizePrice(MustParseDecimal("101.237"), RoundFloor) // 101.23
+func (m *MarketInformation) QuantizePrice(price Decimal, mode RoundingMode) Decimal {
+	if step, low := m.priceGrid(); step.IsPositive() {
+		return roundToGrid(price, step, low, mode)
+	}
+	if decimals := precision(m.QuoteAssetPrecision); decimals >= 0 {
+		return price.RoundMode(decimals, mode)
+	}
+	return price
+}
+
+// QuantizeQuantity rounds quantity to a legal value for orders of the
+// given type using mode: to minQty plus a multiple of the applicable
+// stepSize, or to BaseAssetPrecision decimals when no step is defined.
+// The result is formatted with exactly QuantityDecimals(orderType)
+// fractional digits.
+func (m *MarketInformation) QuantizeQuantity(quantity Decimal, orderType OrderType, mode RoundingMode) Decimal {
+	if step, low := m.quantityGrid(orderType); step.IsPositive() {
+		return roundToGrid(quantity, step, low, mode)
+	}
+	if decimals := precision(m.BaseAssetPrecision); decimals >= 0 {
+		return quantity.RoundMode(decimals, mode)
+	}
+	return quantity
+}
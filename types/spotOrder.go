@@ -4,38 +4,41 @@ package types
 // for order-related endpoints, including newly-created orders, queried
 // orders, and updated order states.
 //
-// Numeric and monetary values are returned as strings to preserve
-// precision. Time fields represent milliseconds since Unix epoch.
+// Numeric and monetary values are returned as strings and decoded into
+// Decimal to preserve precision. Time fields represent milliseconds since
+// Unix epoch.
 type BaseOrderResponse struct {
-	Symbol               string `json:"symbol"`
-	TabdealSymbol        string `json:"tabdealSymbol"`
-	OrderId              int64  `json:"orderId"`
-	OrderListId          int64  `json:"orderListId"`
-	ClientOrderId        string `json:"clientOrderId,omitempty"`
-	TransactTime         int64  `json:"transactTime"`
-	Price                string `json:"price"`
-	OrigQty              string `json:"origQty"`
-	ExecutedQty          string `json:"executedQty"`
-	CummulativeQuoteQty  string `json:"cummulativeQuoteQty"`
-	CumulativeQuoteQty   string `json:"cumulativeQuoteQty"`
-	Status               string `json:"status"`
-	Type                 string `json:"type"`
-	Side                 string `json:"side"`
-	StopPrice            string `json:"stopPrice"`
-	UpdateTime           int64  `json:"updateTime"`
-	IsWorking            bool   `json:"isWorking"`
-	IsStopOrderTriggered bool   `json:"isStopOrderTriggered"`
+	Symbol               string      `json:"symbol"`
+	TabdealSymbol        string      `json:"tabdealSymbol"`
+	OrderId              int64       `json:"orderId"`
+	OrderListId          int64       `json:"orderListId"`
+	ClientOrderId        string      `json:"clientOrderId,omitempty"`
+	TransactTime         int64       `json:"transactTime"`
+	Time                 int64       `json:"time,omitempty"`
+	Price                Decimal     `json:"price"`
+	OrigQty              Decimal     `json:"origQty"`
+	ExecutedQty          Decimal     `json:"executedQty"`
+	CummulativeQuoteQty  Decimal     `json:"cummulativeQuoteQty"`
+	CumulativeQuoteQty   Decimal     `json:"cumulativeQuoteQty"`
+	Status               OrderStatus `json:"status"`
+	TimeInForce          TimeInForce `json:"timeInForce,omitempty"`
+	Type                 OrderType   `json:"type"`
+	Side                 OrderSide   `json:"side"`
+	StopPrice            Decimal     `json:"stopPrice"`
+	UpdateTime           int64       `json:"updateTime"`
+	IsWorking            bool        `json:"isWorking"`
+	IsStopOrderTriggered bool        `json:"isStopOrderTriggered"`
 }
 
 // Fills represents an individual trade execution that occurred while
 // fulfilling an order. A single order may generate multiple fills,
 // each contributing to the total executed quantity.
 type Fills struct {
-	Price           string `json:"price"`
-	Qty             string `json:"qty"`
-	Commission      string `json:"commission"`
-	CommissionAsset string `json:"commissionAsset"`
-	TradeId         int64  `json:"tradeId"`
+	Price           Decimal `json:"price"`
+	Qty             Decimal `json:"qty"`
+	Commission      Decimal `json:"commission"`
+	CommissionAsset string  `json:"commissionAsset"`
+	TradeId         int64   `json:"tradeId"`
 }
 
 // CreateOrderResponse contains the complete details of an order
@@ -55,19 +58,26 @@ type CreateOrderResponse struct {
 //   - STOP or STOP-LIMIT orders may require stopPrice.
 //
 // Side and type must correspond to the allowed values returned by
-// Tabdeal's market-information endpoint.
+// Tabdeal's market-information endpoint. They are typed enums, so an
+// invalid value such as "Buy" is rejected while encoding the request.
+// timeInForce (GTC, IOC, FOK) applies to limit-type orders.
 //
 // newClientOrderId may be supplied to assign a custom tracking ID
 // to the order.
+//
+// Quantity, Price and StopPrice are Decimal values and are sent exactly as
+// formatted by Decimal.String. Use NewDecimalFromFloat when migrating code
+// that still works with float64.
 type CreateOrderParams struct {
 	BaseSymbolParams
 
-	Side             string  `json:"side"`
-	Type             string  `json:"type"`
-	Quantity         float64 `json:"quantity"`
-	NewClientOrderId string  `json:"newClientOrderId,omitempty"`
-	Price            float64 `json:"price,omitempty"`
-	StopPrice        float64 `json:"stopPrice,omitempty"`
+	Side             OrderSide   `json:"side"`
+	Type             OrderType   `json:"type"`
+	TimeInForce      TimeInForce `json:"timeInForce,omitempty"`
+	Quantity         Decimal     `json:"quantity"`
+	NewClientOrderId string      `json:"newClientOrderId,omitempty"`
+	Price            Decimal     `json:"price,omitempty"`
+	StopPrice        Decimal     `json:"stopPrice,omitempty"`
 }
 
 // GetOrderStatusParams specifies how to retrieve the status of a single
@@ -76,9 +86,11 @@ type CreateOrderParams struct {
 //   - orderId
 //   - origClientOrderId
 //
-// At least one should be supplied.
+// At least one should be supplied, together with the trading pair
+// through the embedded BaseSymbolParams.
 type GetOrderStatusParams struct {
-	OrderId           int    `json:"orderId,omitempty"`
+	BaseSymbolParams
+	OrderId           int64  `json:"orderId,omitempty"`
 	OrigClientOrderId string `json:"origClientOrderId,omitempty"`
 }
 
@@ -87,7 +99,7 @@ type GetOrderStatusParams struct {
 // Includes order fields plus the fee associated with completed orders.
 type OrderStatusResponse struct {
 	BaseOrderResponse
-	Fee string `json:"fee"`
+	Fee Decimal `json:"fee"`
 }
 
 // GetOpenOrdersParams defines the optional parameters used when
@@ -101,18 +113,18 @@ type GetOpenOrdersParams struct {
 // including price, quantity, commission, and maker/taker flag.
 // These records are returned by Tabdeal's user-trade history endpoints.
 type UserTradeResponse struct {
-	Symbol          string `json:"symbol"`
-	TabdealSymbol   string `json:"tabdealSymbol"`
-	Id              int64  `json:"id"`
-	OrderId         int64  `json:"orderId"`
-	Price           string `json:"price"`
-	Qty             string `json:"qty"`
-	QuoteQty        string `json:"quoteQty"`
-	Commission      string `json:"commission"`
-	CommissionAsset string `json:"commissionAsset"`
-	Time            int64  `json:"time"`
-	IsBuyer         bool   `json:"isBuyer"`
-	IsMaker         bool   `json:"isMaker"`
+	Symbol          string  `json:"symbol"`
+	TabdealSymbol   string  `json:"tabdealSymbol"`
+	Id              int64   `json:"id"`
+	OrderId         int64   `json:"orderId"`
+	Price           Decimal `json:"price"`
+	Qty             Decimal `json:"qty"`
+	QuoteQty        Decimal `json:"quoteQty"`
+	Commission      Decimal `json:"commission"`
+	CommissionAsset string  `json:"commissionAsset"`
+	Time            int64   `json:"time"`
+	IsBuyer         bool    `json:"isBuyer"`
+	IsMaker         bool    `json:"isMaker"`
 }
 
 // GetUserOrdersHistoryParams defines filters for querying a user's
@@ -128,7 +140,13 @@ type GetUserOrdersHistoryParams struct {
 // GetUserTradesParams extends historical-order filters with the ability
 // to return trades associated with a specific orderId, enabling finer
 // selection when analyzing past executions.
+//
+// FromId returns trades with an id greater than or equal to it, oldest
+// first, and cannot be combined with StartTime or EndTime. It is a pointer
+// so that fromId=0, the first trade, can be told apart from no FromId,
+// which returns the latest trades.
 type GetUserTradesParams struct {
 	GetUserOrdersHistoryParams
-	OrderId int64 `json:"orderId,omitempty"`
+	OrderId int64  `json:"orderId,omitempty"`
+	FromId  *int64 `json:"fromId,omitempty"`
 }
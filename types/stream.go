@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stream names follow the Binance-style convention used by Tabdeal:
+// the lower-case compact symbol, "@", and the channel.
+//
+//	btcirt@trade
+//	btcirt@depth
+//	btcirt@bookTicker
+//	btcirt@kline_1m
+
+// TradeStream returns the trade stream name for symbol.
+func TradeStream(symbol string) string {
+	return streamSymbol(symbol) + "@trade"
+}
+
+// DepthStream returns the diff-depth stream name for symbol.
+func DepthStream(symbol string) string {
+	return streamSymbol(symbol) + "@depth"
+}
+
+// BookTickerStream returns the best bid/ask stream name for symbol.
+func BookTickerStream(symbol string) string {
+	return streamSymbol(symbol) + "@bookTicker"
+}
+
+// KlineStream returns the candlestick stream name for symbol and interval,
+// e.g. KlineStream("BTCIRT", "1m") is "btcirt@kline_1m".
+func KlineStream(symbol, interval string) string {
+	return fmt.Sprintf("%s@kline_%s", streamSymbol(symbol), interval)
+}
+
+// streamSymbol converts "BTC_IRT" or "BTCIRT" into "btcirt".
+func streamSymbol(symbol string) string {
+	return strings.ToLower(strings.ReplaceAll(symbol, "_", ""))
+}
+
+// TradeEvent is a single trade pushed on the <symbol>@trade stream.
+//
+// IsBestMatch is declared so that encoding/json, which matches keys
+// case-insensitively, does not decode the "M" key into IsBuyerMaker.
+type TradeEvent struct {
+	EventType    string  `json:"e"`
+	EventTime    int64   `json:"E"`
+	Symbol       string  `json:"s"`
+	TradeId      int64   `json:"t"`
+	Price        Decimal `json:"p"`
+	Quantity     Decimal `json:"q"`
+	TradeTime    int64   `json:"T"`
+	IsBuyerMaker bool    `json:"m"`
+	IsBestMatch  bool    `json:"M"`
+}
+
+// DepthUpdateEvent is an incremental order book update pushed on the
+// <symbol>@depth stream.
+//
+// FirstUpdateId and FinalUpdateId bound the book sequence numbers covered
+// by this event. A level with a zero quantity removes that price.
+type DepthUpdateEvent struct {
+	EventType     string       `json:"e"`
+	EventTime     int64        `json:"E"`
+	Symbol        string       `json:"s"`
+	FirstUpdateId int64        `json:"U"`
+	FinalUpdateId int64        `json:"u"`
+	Bids          []PriceLevel `json:"b"`
+	Asks          []PriceLevel `json:"a"`
+}
+
+// BookTickerEvent is a best bid/ask update pushed on the
+// <symbol>@bookTicker stream.
+type BookTickerEvent struct {
+	UpdateId    int64   `json:"u"`
+	Symbol      string  `json:"s"`
+	BidPrice    Decimal `json:"b"`
+	BidQuantity Decimal `json:"B"`
+	AskPrice    Decimal `json:"a"`
+	AskQuantity Decimal `json:"A"`
+}
+
+// KlineEvent is a candlestick update pushed on the
+// <symbol>@kline_<interval> stream. The same candle is pushed repeatedly
+// while it is open; Kline.Closed is true for its final update.
+type KlineEvent struct {
+	EventType string      `json:"e"`
+	EventTime int64       `json:"E"`
+	Symbol    string      `json:"s"`
+	Kline     StreamKline `json:"k"`
+}
+
+// StreamKline is the candle carried by a KlineEvent.
+type StreamKline struct {
+	OpenTime            int64   `json:"t"`
+	CloseTime           int64   `json:"T"`
+	Symbol              string  `json:"s"`
+	Interval            string  `json:"i"`
+	FirstTradeId        int64   `json:"f"`
+	LastTradeId         int64   `json:"L"`
+	Open                Decimal `json:"o"`
+	Close               Decimal `json:"c"`
+	High                Decimal `json:"h"`
+	Low                 Decimal `json:"l"`
+	Volume              Decimal `json:"v"`
+	TradeCount          int64   `json:"n"`
+	Closed              bool    `json:"x"`
+	QuoteVolume         Decimal `json:"q"`
+	TakerBuyBaseVolume  Decimal `json:"V"`
+	TakerBuyQuoteVolume Decimal `json:"Q"`
+}
@@ -0,0 +1,53 @@
+package types
+
+// Ticker24hr is the rolling 24-hour statistics of a market, as returned by
+// GET /ticker/24hr. Prices and volumes are decoded into Decimal; times are
+// Unix milliseconds.
+type Ticker24hr struct {
+	Symbol             string  `json:"symbol"`
+	PriceChange        Decimal `json:"priceChange"`
+	PriceChangePercent Decimal `json:"priceChangePercent"`
+	WeightedAvgPrice   Decimal `json:"weightedAvgPrice"`
+	PrevClosePrice     Decimal `json:"prevClosePrice"`
+	LastPrice          Decimal `json:"lastPrice"`
+	LastQty            Decimal `json:"lastQty"`
+	BidPrice           Decimal `json:"bidPrice"`
+	BidQty             Decimal `json:"bidQty"`
+	AskPrice           Decimal `json:"askPrice"`
+	AskQty             Decimal `json:"askQty"`
+	OpenPrice          Decimal `json:"openPrice"`
+	HighPrice          Decimal `json:"highPrice"`
+	LowPrice           Decimal `json:"lowPrice"`
+	Volume             Decimal `json:"volume"`
+	QuoteVolume        Decimal `json:"quoteVolume"`
+	OpenTime           int64   `json:"openTime"`
+	CloseTime          int64   `json:"closeTime"`
+	FirstId            int64   `json:"firstId"`
+	LastId             int64   `json:"lastId"`
+	Count              int64   `json:"count"`
+}
+
+// AveragePrice is the current average price of a market over the last
+// Mins minutes, as returned by GET /avgPrice. It is the reference price of
+// the PERCENT_PRICE filter.
+type AveragePrice struct {
+	Mins      int64   `json:"mins"`
+	Price     Decimal `json:"price"`
+	CloseTime int64   `json:"closeTime"`
+}
+
+// BookTicker is the best bid and ask of a market, as returned by
+// GET /ticker/bookTicker.
+type BookTicker struct {
+	Symbol   string  `json:"symbol"`
+	BidPrice Decimal `json:"bidPrice"`
+	BidQty   Decimal `json:"bidQty"`
+	AskPrice Decimal `json:"askPrice"`
+	AskQty   Decimal `json:"askQty"`
+}
+
+// GetTickerParams selects the market of the ticker endpoints. Leave it
+// empty with the GetAll* methods to receive every market.
+type GetTickerParams struct {
+	BaseSymbolParams
+}
@@ -5,11 +5,12 @@ package types
 // ("free") as well as the blocked balance ("freeze"), which includes
 // amounts locked by open orders or pending operations.
 //
-// All balance values are returned as strings to preserve precision.
+// Balance values are returned as strings and decoded into Decimal to
+// preserve precision.
 type Wallet struct {
-	Asset  string `json:"asset"`
-	Free   string `json:"free"`
-	Freeze string `json:"freeze"`
+	Asset  string  `json:"asset"`
+	Free   Decimal `json:"free"`
+	Freeze Decimal `json:"freeze"`
 }
 
 // GetWalletParams defines the optional query parameters used when
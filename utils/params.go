@@ -0,0 +1,268 @@
+package utils
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Param is a single key/value pair of an encoded request.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is an ordered list of request parameters.
+//
+// Unlike url.Values, Params preserves insertion order. This matters for
+// signed requests: Tabdeal verifies the HMAC against the query string
+// exactly as it arrives, so the string that is signed and the string that
+// is sent on the wire must be produced by the same encoder, in the same
+// order. Params.Encode is that encoder.
+type Params []Param
+
+// Add appends a key/value pair and returns the extended list.
+func (p Params) Add(key, value string) Params {
+	return append(p, Param{Key: key, Value: value})
+}
+
+// Get returns the first value stored under key, or "" when absent.
+func (p Params) Get(key string) string {
+	for _, param := range p {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Encode renders the parameters as a URL query string ("a=1&b=2") in
+// their stored order. Keys and values are escaped with url.QueryEscape.
+//
+// The output of Encode is both the payload that WrapWithSignature signs
+// and the query string that Request sends, which guarantees the two match
+// byte for byte.
+func (p Params) Encode() string {
+	var sb strings.Builder
+	for i, param := range p {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(param.Key))
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(param.Value))
+	}
+	return sb.String()
+}
+
+// EncodeParams converts a request parameter struct into an ordered Params
+// list. It is the single canonical encoder used for both signed and
+// unsigned requests.
+//
+// Encoding rules:
+//   - Keys follow encoding/json: the json tag name when present, the Go
+//     field name otherwise. Fields tagged `json:"-"` and unexported fields
+//     are skipped.
+//   - Embedded (anonymous) structs without a tag name are flattened, so the
+//     fields of BaseSymbolParams or GetUserOrdersHistoryParams appear as
+//     top-level parameters, in declaration order.
+//   - Empty values are always dropped, with or without `omitempty`: zero
+//     numbers, false, empty strings and slices, nil pointers and
+//     interfaces never produce a parameter. Types implementing
+//     `IsZero() bool` decide for themselves what zero means, so a zero
+//     Decimal is dropped too.
+//   - A non-nil pointer is sent even when it points to a zero value; use
+//     one for parameters where zero is meaningful, such as fromId=0.
+//   - Values implementing encoding.TextMarshaler are rendered with
+//     MarshalText; numbers use their shortest exact decimal form; slices
+//     produce one pair per element.
+//
+// The input may be nil (no parameters), a Params value (returned as a copy),
+// a struct, or a pointer to a struct.
+//
+// Errors:
+//   - input is not a struct (or pointer to one).
+//   - a field has a type that cannot be represented in a query string.
+//   - two fields map to the same key.
+func EncodeParams(input interface{}) (Params, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	if params, ok := input.(Params); ok {
+		return append(Params(nil), params...), nil
+	}
+
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input must be a struct, got %s", v.Kind())
+	}
+
+	var params Params
+	if err := encodeStruct(v, &params, map[string]struct{}{}); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func encodeStruct(v reflect.Value, params *Params, seen map[string]struct{}) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := parseTag(tag)
+
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := encodeStruct(embedded, params, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if isZero(value) {
+			continue
+		}
+
+		values, err := encodeValue(value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if _, dup := seen[name]; dup {
+			return fmt.Errorf("duplicate parameter %q", name)
+		}
+		seen[name] = struct{}{}
+
+		for _, val := range values {
+			*params = params.Add(name, val)
+		}
+	}
+
+	return nil
+}
+
+// encodeValue renders a single field value. A nil slice result means the
+// value is empty and must not be emitted.
+func encodeValue(value reflect.Value) ([]string, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+		if m, ok := value.Interface().(encoding.TextMarshaler); ok {
+			return marshalText(m)
+		}
+		value = value.Elem()
+	}
+
+	if value.CanInterface() {
+		if m, ok := value.Interface().(encoding.TextMarshaler); ok {
+			return marshalText(m)
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() == "" {
+			return nil, nil
+		}
+		return []string{value.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(value.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{strconv.FormatUint(value.Uint(), 10)}, nil
+	case reflect.Float32:
+		return []string{strconv.FormatFloat(value.Float(), 'f', -1, 32)}, nil
+	case reflect.Float64:
+		return []string{strconv.FormatFloat(value.Float(), 'f', -1, 64)}, nil
+	case reflect.Bool:
+		return []string{strconv.FormatBool(value.Bool())}, nil
+	case reflect.Slice, reflect.Array:
+		var out []string
+		for j := 0; j < value.Len(); j++ {
+			item, err := encodeValue(value.Index(j))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item...)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter kind %s", value.Kind())
+	}
+}
+
+func marshalText(m encoding.TextMarshaler) ([]string, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	if len(text) == 0 {
+		return nil, nil
+	}
+	return []string{string(text)}, nil
+}
+
+// isZero reports whether value is empty and must not be emitted. Pointers
+// and interfaces are empty only when nil.
+func isZero(value reflect.Value) bool {
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		return value.IsNil()
+	}
+
+	if value.CanInterface() {
+		if z, ok := value.Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
+
+// parseTag returns the name part of a json struct tag.
+func parseTag(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/darhelm/go-tabdeal/types"
+)
+
+func TestEncodeParams(t *testing.T) {
+	type inner struct {
+		Symbol string `json:"symbol,omitempty"`
+	}
+	type outer struct {
+		inner
+		Limit  int64    `json:"limit,omitempty"`
+		FromId *int64   `json:"fromId,omitempty"`
+		Side   string   `json:"side"`
+		Ids    []int64  `json:"ids"`
+		Skip   string   `json:"-"`
+		Named  string   // no tag: the field name is the key
+		Rate   *float64 `json:"rate"`
+	}
+
+	zero := int64(0)
+	seven := int64(7)
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "nil",
+			input: nil,
+			want:  "",
+		},
+		{
+			name:  "nil pointer",
+			input: (*outer)(nil),
+			want:  "",
+		},
+		{
+			name:  "zero struct",
+			input: outer{},
+			want:  "",
+		},
+		{
+			name:  "declaration order with embedded fields first",
+			input: outer{inner: inner{Symbol: "BTCIRT"}, Limit: 5, Side: "BUY", Named: "n"},
+			want:  "symbol=BTCIRT&limit=5&side=BUY&Named=n",
+		},
+		{
+			name:  "json dash is skipped",
+			input: outer{Skip: "secret"},
+			want:  "",
+		},
+		{
+			name:  "slices repeat the key",
+			input: outer{Ids: []int64{1, 2}},
+			want:  "ids=1&ids=2",
+		},
+		{
+			name:  "pointer to zero is sent",
+			input: &outer{FromId: &zero},
+			want:  "fromId=0",
+		},
+		{
+			name:  "pointer to value",
+			input: &outer{FromId: &seven},
+			want:  "fromId=7",
+		},
+		{
+			name:  "params are copied as is",
+			input: Params{}.Add("b", "2").Add("a", "1"),
+			want:  "b=2&a=1",
+		},
+		{
+			name:  "values are query escaped",
+			input: outer{Side: "a b&c=d"},
+			want:  "side=a+b%26c%3Dd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := EncodeParams(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := params.Encode(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeParamsTypes(t *testing.T) {
+	trades := types.GetUserTradesParams{OrderId: 42}
+	trades.Symbol = "BTCIRT"
+	trades.StartTime = 1700000000000
+	trades.Limit = 500
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "nested embedding is flattened",
+			input: trades,
+			want:  "symbol=BTCIRT&startTime=1700000000000&limit=500&orderId=42",
+		},
+		{
+			name: "order with decimals and enums",
+			input: types.CreateOrderParams{
+				BaseSymbolParams: types.BaseSymbolParams{Symbol: "BTCIRT"},
+				Side:             types.OrderSideBuy,
+				Type:             types.OrderTypeLimit,
+				TimeInForce:      types.TimeInForceGTC,
+				Quantity:         types.MustParseDecimal("0.0100"),
+				Price:            types.MustParseDecimal("950000000"),
+			},
+			want: "symbol=BTCIRT&side=BUY&type=LIMIT&timeInForce=GTC&quantity=0.0100&price=950000000",
+		},
+		{
+			name:  "zero fields without omitempty are dropped",
+			input: types.CreateOrderParams{},
+			want:  "",
+		},
+		{
+			name:  "zero decimal is dropped",
+			input: types.CreateOrderParams{Side: types.OrderSideSell, Quantity: types.MustParseDecimal("0")},
+			want:  "side=SELL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := EncodeParams(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := params.Encode(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeParamsErrors(t *testing.T) {
+	type duplicate struct {
+		types.BaseSymbolParams
+		Symbol string `json:"symbol"`
+	}
+	type unsupported struct {
+		Meta map[string]string `json:"meta"`
+	}
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{"not a struct", 42, "must be a struct"},
+		{"duplicate key", duplicate{BaseSymbolParams: types.BaseSymbolParams{Symbol: "A"}, Symbol: "B"}, "duplicate parameter"},
+		{"unsupported kind", unsupported{Meta: map[string]string{"a": "b"}}, "unsupported parameter kind"},
+		{"unknown enum", types.CreateOrderParams{Side: "Buy", Type: types.OrderTypeLimit}, `invalid order side "Buy"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodeParams(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("EncodeParams() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructToURLParamsMatchesEncode(t *testing.T) {
+	params := types.GetUserTradesParams{}
+	params.TabdealSymbol = "BTC_IRT"
+	fromId := int64(10)
+	params.FromId = &fromId
+
+	query, err := StructToURLParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, _ := EncodeParams(params)
+	if query != encoded.Encode() || query != "tabdealSymbol=BTC_IRT&fromId=10" {
+		t.Errorf("StructToURLParams() = %q, Encode() = %q", query, encoded.Encode())
+	}
+}
@@ -4,47 +4,50 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
-	"reflect"
 	"strconv"
-	"strings"
 )
 
-func WrapWithSignature(inputStruct interface{}, apiSecret string, timestamp int64) interface{} {
-	v := reflect.ValueOf(inputStruct)
-	t := reflect.TypeOf(inputStruct)
-
-	pairs := make([]string, 0, v.NumField()+1)
-
-	result := make(map[string]interface{}, v.NumField()+1)
-
-	for i := 0; i < v.NumField(); i++ {
-		key := t.Field(i).Name
-
-		// Prefer JSON tag if available, fall back to field name
-		if jsonTag := t.Field(i).Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
-			key = jsonTag
-		}
-
-		val := v.Field(i).Interface()
-
-		pairs = append(pairs, fmt.Sprintf("%s=%v", key, val))
+// Sign returns the hex-encoded HMAC-SHA256 of payload keyed with apiSecret,
+// which is the signature format Tabdeal expects in the `signature`
+// parameter of signed endpoints.
+//
+// Example (from the Tabdeal/Binance signing documentation):
+//
+//	Sign("symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559",
+//	    "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j")
+//	→ "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+func Sign(payload string, apiSecret string) string {
+	mac := hmac.New(sha256.New, []byte(apiSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
-		result[key] = val
+// WrapWithSignature encodes inputStruct with EncodeParams, appends the
+// `timestamp` parameter and signs the result.
+//
+// The signature is computed over Params.Encode() of every parameter that
+// precedes it, and is appended last. Sending the returned list with
+// Params.Encode() therefore yields a query string whose signed portion
+// matches the signed payload byte for byte.
+//
+// Parameters:
+//   - inputStruct: request parameters (struct, pointer to struct, Params or nil).
+//   - apiSecret: the API secret used as HMAC key.
+//   - timestamp: request time in milliseconds since epoch.
+//
+// Returns:
+//   - Params ending in timestamp and signature.
+//   - An error when inputStruct cannot be encoded.
+func WrapWithSignature(inputStruct interface{}, apiSecret string, timestamp int64) (Params, error) {
+	params, err := EncodeParams(inputStruct)
+	if err != nil {
+		return nil, err
 	}
 
-	ts := strconv.FormatInt(timestamp, 10)
-	pairs = append(pairs, "timestamp="+ts)
-	result["timestamp"] = timestamp
-
-	raw := strings.Join(pairs, "&")
-
-	mac := hmac.New(sha256.New, []byte(apiSecret))
-	mac.Write([]byte(raw))
-	sig := hex.EncodeToString(mac.Sum(nil))
-
-	result["timestamp"] = timestamp
-	result["signature"] = sig
+	signed := make(Params, 0, len(params)+2)
+	signed = append(signed, params...)
+	signed = signed.Add("timestamp", strconv.FormatInt(timestamp, 10))
+	signed = signed.Add("signature", Sign(signed.Encode(), apiSecret))
 
-	return result
+	return signed, nil
 }
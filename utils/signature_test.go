@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// docSecret is the API secret of the signing examples in the Tabdeal (and
+// Binance) API documentation.
+const docSecret = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+
+func TestSignDocumentedVectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+	}{
+		{
+			name:      "query string",
+			payload:   "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559",
+			signature: "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71",
+		},
+		{
+			name:      "query string and body concatenated",
+			payload:   "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTCquantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559",
+			signature: "0fd168b8ddb4876a0358a8d14d0c9f3da0e9b20c5d52b2a00fcf7d1c602f9a77",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.payload, docSecret); got != tt.signature {
+				t.Errorf("Sign() = %s, want %s", got, tt.signature)
+			}
+		})
+	}
+}
+
+func TestWrapWithSignatureDocumentedVector(t *testing.T) {
+	params := Params{}.
+		Add("symbol", "LTCBTC").
+		Add("side", "BUY").
+		Add("type", "LIMIT").
+		Add("timeInForce", "GTC").
+		Add("quantity", "1").
+		Add("price", "0.1").
+		Add("recvWindow", "5000")
+
+	signed, err := WrapWithSignature(params, docSecret, 1499827319559)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559" +
+		"&signature=c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+	if got := signed.Encode(); got != want {
+		t.Errorf("Encode() = %s\nwant       %s", got, want)
+	}
+}
+
+func TestWrapWithSignatureMatchesWire(t *testing.T) {
+	type params struct {
+		Symbol   string   `json:"symbol"`
+		Note     string   `json:"note,omitempty"`
+		Price    float64  `json:"price"`
+		Tags     []string `json:"tags,omitempty"`
+		Verified bool     `json:"verified"`
+	}
+
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"nil", nil},
+		{"plain", params{Symbol: "BTCIRT", Price: 0.1}},
+		{"escaped", params{Symbol: "BTC IRT", Note: "a&b=c/é+", Price: 1e-7, Tags: []string{"x y", "z"}, Verified: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := WrapWithSignature(tt.input, docSecret, 1700000000000)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wire := signed.Encode()
+			payload, signature, ok := strings.Cut(wire, "&signature=")
+			if !ok {
+				t.Fatalf("no signature in %q", wire)
+			}
+			if strings.Contains(signature, "&") {
+				t.Fatalf("signature is not the last parameter: %q", wire)
+			}
+			if got := Sign(payload, docSecret); got != signature {
+				t.Errorf("signature %s does not match the wire payload %q (want %s)", signature, payload, got)
+			}
+			if !strings.HasSuffix(payload, "timestamp=1700000000000") {
+				t.Errorf("payload %q does not end with the timestamp", payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package tabdeal
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// OrderViolation describes one exchange rule that an order breaks.
+type OrderViolation struct {
+	// Filter is the exchangeInfo filter that was violated, such as
+	// "PRICE_FILTER" or "LOT_SIZE". Structural problems that are not tied
+	// to a filter (missing price, unsupported order type, market halted)
+	// use "ORDER" or "STATUS".
+	Filter string
+
+	// Field is the offending order field: "price", "stopPrice",
+	// "quantity", "notional", "side", "type" or "symbol".
+	Field string
+
+	// Value is the offending value, when numeric.
+	Value t.Decimal
+
+	// Min and Max bound the allowed range. A zero bound is unbounded.
+	Min t.Decimal
+	Max t.Decimal
+
+	// Step is the required increment (tickSize or stepSize), when relevant.
+	Step t.Decimal
+
+	// Message is a human-readable description of the violation.
+	Message string
+}
+
+// String returns the violation as "FILTER field: message".
+func (v OrderViolation) String() string {
+	return fmt.Sprintf("%s %s: %s", v.Filter, v.Field, v.Message)
+}
+
+// ValidateOrder checks an order against the rules published for its
+// market in exchangeInfo, without any network I/O.
+//
+// Parameters:
+//   - params: the order to validate.
+//   - market: the market definition, as returned by GetMarketInformation.
+//     A nil market is reported as a single STATUS violation.
+//   - referencePrice: the current average price of the market. It is used
+//     by PERCENT_PRICE and by MIN_NOTIONAL for MARKET orders; pass a zero
+//     Decimal to skip those checks.
+//
+// Checks:
+//   - market status is TRADING and the order type is supported.
+//   - side and type are known; price and stopPrice are present when the
+//     order type requires them.
+//   - PRICE_FILTER: minPrice ≤ price ≤ maxPrice and (price - minPrice) is a
+//     multiple of tickSize, for both price and stopPrice.
+//   - PERCENT_PRICE: price lies within referencePrice × [multiplierDown,
+//     multiplierUp].
+//   - LOT_SIZE (and MARKET_LOT_SIZE for MARKET orders): minQty ≤ quantity
+//     ≤ maxQty and (quantity - minQty) is a multiple of stepSize.
+//   - MIN_NOTIONAL: price × quantity ≥ minNotional.
+//
+// Returns:
+//   - every violation found, or nil when the order passes.
+//
+// Example:
+//
+//	if v := tabdeal.ValidateOrder(params, market, t.Decimal{}); len(v) > 0 {
+//	    for _, violation := range v {
+//	        fmt.Println(violation)
+//	    }
+//	}
+func ValidateOrder(params t.CreateOrderParams, market *t.MarketInformation, referencePrice t.Decimal) []OrderViolation {
+	if market == nil {
+		return []OrderViolation{unknownMarket(params.BaseSymbolParams)}
+	}
+
+	var violations []OrderViolation
+	add := func(v OrderViolation) { violations = append(violations, v) }
+
+	if market.Status != "" && market.Status != t.MarketStatusTrading {
+		add(OrderViolation{
+			Filter:  "STATUS",
+			Field:   "symbol",
+			Message: fmt.Sprintf("market %s is not trading (status %s)", market.Symbol, market.Status),
+		})
+	}
+
+	if !params.Side.IsKnown() {
+		add(OrderViolation{Filter: "ORDER", Field: "side", Message: fmt.Sprintf("invalid side %q", params.Side)})
+	}
+
+	switch {
+	case !params.Type.IsKnown():
+		add(OrderViolation{Filter: "ORDER", Field: "type", Message: fmt.Sprintf("invalid order type %q", params.Type)})
+	case !market.SupportsOrderType(params.Type):
+		add(OrderViolation{Filter: "ORDER", Field: "type", Message: fmt.Sprintf("order type %s is not supported by %s", params.Type, market.Symbol)})
+	}
+
+	if !params.Quantity.IsPositive() {
+		add(OrderViolation{Filter: "ORDER", Field: "quantity", Value: params.Quantity, Message: "quantity must be positive"})
+	}
+
+	if params.Type.RequiresPrice() && !params.Price.IsPositive() {
+		add(OrderViolation{Filter: "ORDER", Field: "price", Value: params.Price, Message: fmt.Sprintf("%s orders require a positive price", params.Type)})
+	}
+
+	if params.Type.RequiresStopPrice() && !params.StopPrice.IsPositive() {
+		add(OrderViolation{Filter: "ORDER", Field: "stopPrice", Value: params.StopPrice, Message: fmt.Sprintf("%s orders require a positive stopPrice", params.Type)})
+	}
+
+	if filter, ok := market.Filter(t.FilterTypePriceFilter); ok {
+		if params.Price.IsPositive() {
+			violations = append(violations, checkRange(filter.FilterType, "price", params.Price, filter.MinPrice, filter.MaxPrice, filter.TickSize)...)
+		}
+		if params.StopPrice.IsPositive() {
+			violations = append(violations, checkRange(filter.FilterType, "stopPrice", params.StopPrice, filter.MinPrice, filter.MaxPrice, filter.TickSize)...)
+		}
+	}
+
+	if filter, ok := market.Filter(t.FilterTypePercentPrice); ok && referencePrice.IsPositive() && params.Price.IsPositive() {
+		low := referencePrice.Mul(filter.MultiplierDown)
+		high := referencePrice.Mul(filter.MultiplierUp)
+		if (filter.MultiplierDown.IsPositive() && params.Price.LessThan(low)) ||
+			(filter.MultiplierUp.IsPositive() && params.Price.GreaterThan(high)) {
+			add(OrderViolation{
+				Filter:  filter.FilterType,
+				Field:   "price",
+				Value:   params.Price,
+				Min:     low,
+				Max:     high,
+				Message: fmt.Sprintf("price %s is outside %s–%s around the average price %s", params.Price, low, high, referencePrice),
+			})
+		}
+	}
+
+	if params.Quantity.IsPositive() {
+		if filter, ok := market.Filter(t.FilterTypeLotSize); ok {
+			violations = append(violations, checkRange(filter.FilterType, "quantity", params.Quantity, filter.MinQty, filter.MaxQty, filter.StepSize)...)
+		}
+		if params.Type == t.OrderTypeMarket {
+			if filter, ok := market.Filter(t.FilterTypeMarketLotSize); ok {
+				violations = append(violations, checkRange(filter.FilterType, "quantity", params.Quantity, filter.MinQty, filter.MaxQty, filter.StepSize)...)
+			}
+		}
+	}
+
+	if filter, ok := market.Filter(t.FilterTypeMinNotional); ok && filter.MinNotional.IsPositive() {
+		price := params.Price
+		if params.Type == t.OrderTypeMarket {
+			price = t.Decimal{}
+			if filter.ApplyToMarket {
+				price = referencePrice
+			}
+		}
+
+		if price.IsPositive() && params.Quantity.IsPositive() {
+			notional := price.Mul(params.Quantity)
+			if notional.LessThan(filter.MinNotional) {
+				add(OrderViolation{
+					Filter:  filter.FilterType,
+					Field:   "notional",
+					Value:   notional,
+					Min:     filter.MinNotional,
+					Message: fmt.Sprintf("notional %s is below the minimum %s", notional, filter.MinNotional),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// unknownMarket is the violation of an order without market rules.
+func unknownMarket(symbol t.BaseSymbolParams) OrderViolation {
+	return OrderViolation{
+		Filter:  "STATUS",
+		Field:   "symbol",
+		Message: fmt.Sprintf("no market rules for %q", cmp.Or(symbol.Symbol, symbol.TabdealSymbol)),
+	}
+}
+
+// needsReferencePrice reports whether ValidateOrder uses the reference
+// price for params on market: for PERCENT_PRICE, or for MIN_NOTIONAL of a
+// MARKET order when the filter applies to them.
+func needsReferencePrice(params t.CreateOrderParams, market *t.MarketInformation) bool {
+	if market == nil {
+		return false
+	}
+	if _, ok := market.Filter(t.FilterTypePercentPrice); ok && params.Price.IsPositive() {
+		return true
+	}
+	filter, ok := market.Filter(t.FilterTypeMinNotional)
+	return ok && filter.ApplyToMarket && params.Type == t.OrderTypeMarket
+}
+
+// checkRange validates value against an inclusive [low, high] range and a
+// step measured from low. Zero bounds and steps are not enforced.
+func checkRange(filter, field string, value, low, high, step t.Decimal) []OrderViolation {
+	var violations []OrderViolation
+
+	if low.IsPositive() && value.LessThan(low) {
+		violations = append(violations, OrderViolation{
+			Filter: filter, Field: field, Value: value, Min: low, Max: high, Step: step,
+			Message: fmt.Sprintf("%s %s is below the minimum %s", field, value, low),
+		})
+	}
+
+	if high.IsPositive() && value.GreaterThan(high) {
+		violations = append(violations, OrderViolation{
+			Filter: filter, Field: field, Value: value, Min: low, Max: high, Step: step,
+			Message: fmt.Sprintf("%s %s is above the maximum %s", field, value, high),
+		})
+	}
+
+	if step.IsPositive() && !value.Sub(low).IsMultipleOf(step) {
+		violations = append(violations, OrderViolation{
+			Filter: filter, Field: field, Value: value, Min: low, Max: high, Step: step,
+			Message: fmt.Sprintf("%s %s is not a multiple of %s", field, value, step.Normalize()),
+		})
+	}
+
+	return violations
+}
+
+// ValidateOCOOrder checks an OCO order against the rules of its market,
+// without any network I/O. referencePrice is passed on to ValidateOrder
+// for both legs; a nil market is reported as in ValidateOrder.
+//
+// Checks:
+//   - the market allows OCO orders (OcoAllowed).
+//   - both legs (see CreateOCOOrderParams.Legs) pass ValidateOrder.
+//   - the legs bracket the market: for a SELL, price above stopPrice; for
+//     a BUY, price below stopPrice.
+//
+// Returns:
+//   - every violation found, without duplicates, or nil when the order
+//     passes.
+func ValidateOCOOrder(params t.CreateOCOOrderParams, market *t.MarketInformation, referencePrice t.Decimal) []OrderViolation {
+	if market == nil {
+		return []OrderViolation{unknownMarket(params.BaseSymbolParams)}
+	}
+
+	var violations []OrderViolation
+
+	if !market.OcoAllowed {
+		violations = append(violations, OrderViolation{
+			Filter:  "ORDER",
+			Field:   "type",
+			Message: fmt.Sprintf("market %s does not allow OCO orders", market.Symbol),
+		})
+	}
+
+	limit, stop := params.Legs()
+	for _, v := range append(ValidateOrder(limit, market, referencePrice), ValidateOrder(stop, market, referencePrice)...) {
+		if !slices.ContainsFunc(violations, func(seen OrderViolation) bool { return seen.String() == v.String() }) {
+			violations = append(violations, v)
+		}
+	}
+
+	if params.Price.IsPositive() && params.StopPrice.IsPositive() {
+		switch {
+		case params.Side == t.OrderSideSell && !params.Price.GreaterThan(params.StopPrice):
+			violations = append(violations, OrderViolation{
+				Filter: "ORDER", Field: "price", Value: params.Price, Min: params.StopPrice,
+				Message: fmt.Sprintf("SELL OCO price %s must be above stopPrice %s", params.Price, params.StopPrice),
+			})
+		case params.Side == t.OrderSideBuy && !params.Price.LessThan(params.StopPrice):
+			violations = append(violations, OrderViolation{
+				Filter: "ORDER", Field: "price", Value: params.Price, Max: params.StopPrice,
+				Message: fmt.Sprintf("BUY OCO price %s must be below stopPrice %s", params.Price, params.StopPrice),
+			})
+		}
+	}
+
+	return violations
+}
+
+// lookupMarket returns the market definition for a symbol in either
+// "BTCIRT" or "BTC_IRT" form from the client's SymbolRegistry.
+func (c *Client) lookupMarket(ctx context.Context, symbol t.BaseSymbolParams) (*t.MarketInformation, error) {
+	key := symbol.Symbol
+	if key == "" {
+		key = symbol.TabdealSymbol
+	}
+
+	return c.Symbols.Lookup(ctx, key)
+}
+
+// validateOrder runs ValidateOrder against the registry's market
+// definition, with the current average price as the reference price when
+// the market's filters need one.
+func (c *Client) validateOrder(ctx context.Context, params t.CreateOrderParams) error {
+	market, err := c.lookupMarket(ctx, params.BaseSymbolParams)
+	if err != nil {
+		return &GoTabdealError{Message: "failed to load market rules for validation", Err: err}
+	}
+
+	var referencePrice t.Decimal
+	if needsReferencePrice(params, market) {
+		if referencePrice, err = c.referencePrice(ctx, params.BaseSymbolParams); err != nil {
+			return err
+		}
+	}
+
+	if violations := ValidateOrder(params, market, referencePrice); len(violations) > 0 {
+		return newOrderValidationError(violations)
+	}
+
+	return nil
+}
+
+// validateOCOOrder runs ValidateOCOOrder against the registry's market
+// definition, fetching the reference price as validateOrder does.
+func (c *Client) validateOCOOrder(ctx context.Context, params t.CreateOCOOrderParams) error {
+	market, err := c.lookupMarket(ctx, params.BaseSymbolParams)
+	if err != nil {
+		return &GoTabdealError{Message: "failed to load market rules for validation", Err: err}
+	}
+
+	var referencePrice t.Decimal
+	limit, stop := params.Legs()
+	if needsReferencePrice(limit, market) || needsReferencePrice(stop, market) {
+		if referencePrice, err = c.referencePrice(ctx, params.BaseSymbolParams); err != nil {
+			return err
+		}
+	}
+
+	if violations := ValidateOCOOrder(params, market, referencePrice); len(violations) > 0 {
+		return newOrderValidationError(violations)
+	}
+
+	return nil
+}
+
+// referencePrice returns the current average price of a market.
+func (c *Client) referencePrice(ctx context.Context, symbol t.BaseSymbolParams) (t.Decimal, error) {
+	avg, err := c.GetAveragePriceCtx(ctx, t.GetTickerParams{BaseSymbolParams: symbol})
+	if err != nil {
+		return t.Decimal{}, &GoTabdealError{Message: "failed to load the average price for validation", Err: err}
+	}
+	if avg == nil {
+		return t.Decimal{}, nil
+	}
+	return avg.Price, nil
+}
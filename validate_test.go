@@ -0,0 +1,270 @@
+package tabdeal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	t "github.com/darhelm/go-tabdeal/types"
+)
+
+// testMarket has a ±10% PERCENT_PRICE band and a 1,000,000 MIN_NOTIONAL
+// that applies to MARKET orders.
+func testMarket() *t.MarketInformation {
+	return &t.MarketInformation{
+		Symbol:        "BTCIRT",
+		TabdealSymbol: "BTC_IRT",
+		Status:        t.MarketStatusTrading,
+		OcoAllowed:    true,
+		Filters: []t.Filter{
+			{FilterType: t.FilterTypePercentPrice, MultiplierUp: t.MustParseDecimal("1.1"), MultiplierDown: t.MustParseDecimal("0.9")},
+			{FilterType: t.FilterTypeMinNotional, MinNotional: t.MustParseDecimal("1000000"), ApplyToMarket: true},
+		},
+	}
+}
+
+func testOrder(orderType t.OrderType, price, quantity string) t.CreateOrderParams {
+	params := t.CreateOrderParams{
+		BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+		Side:             t.OrderSideBuy,
+		Type:             orderType,
+		Quantity:         t.MustParseDecimal(quantity),
+	}
+	if price != "" {
+		params.Price = t.MustParseDecimal(price)
+		params.TimeInForce = t.TimeInForceGTC
+	}
+	return params
+}
+
+func TestValidateOrderReferencePrice(tt *testing.T) {
+	tests := []struct {
+		name      string
+		params    t.CreateOrderParams
+		market    *t.MarketInformation
+		reference string
+		want      []string
+	}{
+		{
+			name:   "nil market",
+			params: testOrder(t.OrderTypeLimit, "100", "1"),
+			want:   []string{`STATUS symbol: no market rules for "BTCIRT"`},
+		},
+		{
+			name:      "price within the band",
+			params:    testOrder(t.OrderTypeLimit, "105", "100000"),
+			market:    testMarket(),
+			reference: "100",
+		},
+		{
+			name:      "price above the band",
+			params:    testOrder(t.OrderTypeLimit, "111", "100000"),
+			market:    testMarket(),
+			reference: "100",
+			want:      []string{"PERCENT_PRICE price: price 111 is outside 90.0–110.0 around the average price 100"},
+		},
+		{
+			name:   "no reference price skips the band",
+			params: testOrder(t.OrderTypeLimit, "111", "100000"),
+			market: testMarket(),
+		},
+		{
+			name:      "market order below the minimum notional",
+			params:    testOrder(t.OrderTypeMarket, "", "5"),
+			market:    testMarket(),
+			reference: "100",
+			want:      []string{"MIN_NOTIONAL notional: notional 500 is below the minimum 1000000"},
+		},
+		{
+			name:      "market order above the minimum notional",
+			params:    testOrder(t.OrderTypeMarket, "", "20000"),
+			market:    testMarket(),
+			reference: "100",
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			var reference t.Decimal
+			if tc.reference != "" {
+				reference = t.MustParseDecimal(tc.reference)
+			}
+
+			var got []string
+			for _, v := range ValidateOrder(tc.params, tc.market, reference) {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				tt.Errorf("violations = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateOrdersFetchesReferencePrice(tt *testing.T) {
+	var averagePrices, orders atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/exchangeInfo"):
+			_ = json.NewEncoder(w).Encode([]*t.MarketInformation{testMarket()})
+		case strings.HasSuffix(r.URL.Path, "/avgPrice"):
+			averagePrices.Add(1)
+			_, _ = w.Write([]byte(`{"mins":5,"price":"100"}`))
+		default:
+			orders.Add(1)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(ClientOptions{ApiKey: "key", ApiSecret: "secret", BaseUrl: srv.URL, ValidateOrders: true})
+	if err != nil {
+		tt.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		create func() error
+		want   string
+	}{
+		{
+			name: "limit order outside the band",
+			create: func() error {
+				_, err := client.CreateOrderCtx(ctx, testOrder(t.OrderTypeLimit, "120", "100000"))
+				return err
+			},
+			want: "PERCENT_PRICE",
+		},
+		{
+			name: "market order below the minimum notional",
+			create: func() error {
+				_, err := client.CreateOrderCtx(ctx, testOrder(t.OrderTypeMarket, "", "5"))
+				return err
+			},
+			want: "MIN_NOTIONAL",
+		},
+		{
+			name: "oco leg outside the band",
+			create: func() error {
+				_, err := client.CreateOCOOrderCtx(ctx, t.CreateOCOOrderParams{
+					BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+					Side:             t.OrderSideSell,
+					Quantity:         t.MustParseDecimal("100000"),
+					Price:            t.MustParseDecimal("130"),
+					StopPrice:        t.MustParseDecimal("95"),
+				})
+				return err
+			},
+			want: "PERCENT_PRICE",
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			before := averagePrices.Load()
+
+			err := tc.create()
+			var verr *OrderValidationError
+			if !errors.As(err, &verr) || len(verr.Violations) != 1 || verr.Violations[0].Filter != tc.want {
+				tt.Fatalf("error = %v, want a single %s violation", err, tc.want)
+			}
+			if averagePrices.Load() != before+1 {
+				tt.Errorf("average price fetched %d times, want once", averagePrices.Load()-before)
+			}
+		})
+	}
+
+	if n := orders.Load(); n != 0 {
+		tt.Errorf("%d invalid orders were sent", n)
+	}
+}
+
+func TestValidateOCOOrder(tt *testing.T) {
+	oco := func(side t.OrderSide, price, stopPrice, stopLimitPrice, quantity string) t.CreateOCOOrderParams {
+		params := t.CreateOCOOrderParams{
+			BaseSymbolParams: t.BaseSymbolParams{Symbol: "BTCIRT"},
+			Side:             side,
+			Quantity:         t.MustParseDecimal(quantity),
+			Price:            t.MustParseDecimal(price),
+			StopPrice:        t.MustParseDecimal(stopPrice),
+		}
+		if stopLimitPrice != "" {
+			params.StopLimitPrice = t.MustParseDecimal(stopLimitPrice)
+			params.StopLimitTimeInForce = t.TimeInForceGTC
+		}
+		return params
+	}
+	noOco := testMarket()
+	noOco.OcoAllowed = false
+
+	tests := []struct {
+		name   string
+		params t.CreateOCOOrderParams
+		market *t.MarketInformation
+		want   []string
+	}{
+		{
+			name:   "nil market",
+			params: oco(t.OrderSideSell, "105", "95", "", "100000"),
+			want:   []string{`STATUS symbol: no market rules for "BTCIRT"`},
+		},
+		{
+			name:   "sell with a market stop",
+			params: oco(t.OrderSideSell, "105", "95", "", "100000"),
+			market: testMarket(),
+		},
+		{
+			name:   "buy with a stop-limit",
+			params: oco(t.OrderSideBuy, "95", "105", "106", "100000"),
+			market: testMarket(),
+		},
+		{
+			name:   "stop-limit price outside the band",
+			params: oco(t.OrderSideSell, "105", "95", "80", "100000"),
+			market: testMarket(),
+			want:   []string{"PERCENT_PRICE price: price 80 is outside 90.0–110.0 around the average price 100"},
+		},
+		{
+			name:   "sell price below the stop",
+			params: oco(t.OrderSideSell, "95", "105", "", "100000"),
+			market: testMarket(),
+			want:   []string{"ORDER price: SELL OCO price 95 must be above stopPrice 105"},
+		},
+		{
+			name:   "buy price above the stop",
+			params: oco(t.OrderSideBuy, "105", "95", "94", "100000"),
+			market: testMarket(),
+			want:   []string{"ORDER price: BUY OCO price 105 must be below stopPrice 95"},
+		},
+		{
+			name:   "OCO not allowed",
+			params: oco(t.OrderSideSell, "105", "95", "", "100000"),
+			market: noOco,
+			want:   []string{"ORDER type: market BTCIRT does not allow OCO orders"},
+		},
+		{
+			name:   "shared violations are reported once",
+			params: oco(t.OrderSideSell, "105", "95", "94", "0"),
+			market: testMarket(),
+			want:   []string{"ORDER quantity: quantity must be positive"},
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(tt *testing.T) {
+			var got []string
+			for _, v := range ValidateOCOOrder(tc.params, tc.market, t.MustParseDecimal("100")) {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				tt.Errorf("violations = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}